@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/calendar"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+type Calendar struct {
+	service calendar.Service
+}
+
+func NewCalendar(service calendar.Service) *Calendar {
+	return &Calendar{service: service}
+}
+
+// @Summary Room availability feed
+// @Description iCalendar feed with the reservations and imported blocks of a room
+// @Tags calendar
+// @Produce  text/calendar
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Param   number   path  string  true  "Room number"
+// @Success 200 {string} string
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/rooms/{number}/calendar.ics [get]
+func (h *Calendar) RoomFeed() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		feed, err := h.service.RoomFeed(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), ctx.Param("number"))
+		if err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.Data(http.StatusOK, icsContentType, feed)
+	}
+}
+
+// @Summary Room type availability feed
+// @Description iCalendar feed with the dates on which every room of the type is booked or blocked
+// @Tags calendar
+// @Produce  text/calendar
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Param   type     path  string  true  "Room type"
+// @Success 200 {string} string
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/room-types/{type}/calendar.ics [get]
+func (h *Calendar) RoomTypeFeed() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		feed, err := h.service.RoomTypeFeed(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), ctx.Param("type"))
+		if err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.Data(http.StatusOK, icsContentType, feed)
+	}
+}
+
+// @Summary Register an external calendar
+// @Description Registers an external iCal URL whose events block a room; it is imported periodically
+// @Tags calendar
+// @Accept  json
+// @Produce  json
+// @Param   id                     path  string                        true  "Franquicia ID"
+// @Param   hotelId                path  string                        true  "Hotel ID"
+// @Param   CalendarSourceRequest  body  domain.CalendarSourceRequest  true  "Calendar Source"
+// @Success 201 {object} domain.CalendarSource
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/calendar/sources [post]
+func (h *Calendar) CreateSource() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.CalendarSourceRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		src := &domain.CalendarSource{
+			FranquiciaID: franquiciaID,
+			HotelID:      ctx.Param("hotelId"),
+			RoomNumber:   req.RoomNumber,
+			Name:         req.Name,
+			URL:          req.URL,
+		}
+		if err := h.service.CreateSource(ctx.Request.Context(), src); err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, src)
+	}
+}
+
+// @Summary Get external calendars
+// @Description Retrieves the external calendars of a hotel with the result of their last import
+// @Tags calendar
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Success 200 {array} domain.CalendarSource
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/calendar/sources [get]
+func (h *Calendar) GetSources() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		sources, err := h.service.GetSources(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"))
+		if err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, sources)
+	}
+}
+
+// @Summary Delete an external calendar
+// @Description Deletes an external calendar and the blocks it imported
+// @Tags calendar
+// @Param   id        path  string  true  "Franquicia ID"
+// @Param   hotelId   path  string  true  "Hotel ID"
+// @Param   sourceId  path  string  true  "Calendar Source ID"
+// @Success 204
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/calendar/sources/{sourceId} [delete]
+func (h *Calendar) DeleteSource() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "sourceId")
+		if !ok {
+			return
+		}
+
+		if err := h.service.DeleteSource(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), id); err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Import an external calendar
+// @Description Imports an external calendar now and reports the conflicts with internal reservations
+// @Tags calendar
+// @Produce  json
+// @Param   id        path  string  true  "Franquicia ID"
+// @Param   hotelId   path  string  true  "Hotel ID"
+// @Param   sourceId  path  string  true  "Calendar Source ID"
+// @Success 200 {object} domain.CalendarSyncResult
+// @Failure 400,404,500,502,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/calendar/sources/{sourceId}/sync [post]
+func (h *Calendar) Sync() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "sourceId")
+		if !ok {
+			return
+		}
+
+		result, err := h.service.Sync(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), id)
+		if err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
+
+// @Summary Get calendar conflicts
+// @Description Lists the imported blocks that overlap internal reservations of the same room
+// @Tags calendar
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Success 200 {array} domain.CalendarConflict
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/calendar/conflicts [get]
+func (h *Calendar) GetConflicts() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		conflicts, err := h.service.GetConflicts(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"))
+		if err != nil {
+			calendarError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, conflicts)
+	}
+}
+
+func calendarError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, calendar.ErrSourceNotFound), errors.Is(err, calendar.ErrRoomNotFound), errors.Is(err, calendar.ErrRoomTypeNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, calendar.ErrInvalidSource):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, calendar.ErrFetchFailed):
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
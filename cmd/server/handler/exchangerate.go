@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/domain"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRate struct {
+	service currency.Service
+}
+
+func NewExchangeRate(service currency.Service) *ExchangeRate {
+	return &ExchangeRate{service: service}
+}
+
+// @Summary Upload an exchange-rate table
+// @Description Uploads the rates of every currency against a base currency, effective from the given date
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param   ExchangeRateTableRequest  body  domain.ExchangeRateTableRequest  true  "Exchange Rate Table"
+// @Success 201 {object} domain.ExchangeRateTable
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /exchangerates [post]
+func (h *ExchangeRate) Upload() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req domain.ExchangeRateTableRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		table := &domain.ExchangeRateTable{
+			Base:          strings.ToUpper(req.Base),
+			EffectiveDate: req.EffectiveDate,
+			Rates:         req.Rates,
+		}
+		if err := h.service.UploadTable(ctx.Request.Context(), table); err != nil {
+			currencyError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, table)
+	}
+}
+
+// @Summary Get exchange-rate tables
+// @Description Retrieves every uploaded exchange-rate table, most recent first
+// @Tags currency
+// @Produce  json
+// @Success 200 {array} domain.ExchangeRateTable
+// @Failure 500 {object} map[string]interface{}
+// @Router /exchangerates [get]
+func (h *ExchangeRate) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tables, err := h.service.GetTables(ctx.Request.Context())
+		if err != nil {
+			currencyError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, tables)
+	}
+}
+
+func currencyError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, currency.ErrInvalidCurrency), errors.Is(err, currency.ErrInvalidTable):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, currency.ErrRateNotFound):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
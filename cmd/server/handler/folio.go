@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/folio"
+	"clubhub-hotel-management/internal/housekeeping"
+	"clubhub-hotel-management/internal/invoice"
+	"clubhub-hotel-management/internal/rateplan"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Folio struct {
+	service folio.Service
+}
+
+func NewFolio(service folio.Service) *Folio {
+	return &Folio{service: service}
+}
+
+// @Summary Open a Folio
+// @Description Opens the folio of a stay (check-in), locking the nightly rates of the rate plan
+// @Tags folio
+// @Accept  json
+// @Produce  json
+// @Param   id            path  string               true  "Franquicia ID"
+// @Param   FolioRequest  body  domain.FolioRequest  true  "Folio Request"
+// @Success 201 {object} domain.Folio
+// @Failure 400,404,409,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios [post]
+func (h *Folio) Open() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.FolioRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		ratePlanID, err := primitive.ObjectIDFromHex(req.RatePlanID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid rate_plan_id"})
+			return
+		}
+
+		f := &domain.Folio{
+			FranquiciaID:  franquiciaID,
+			HotelID:       req.HotelID,
+			ReservationID: req.ReservationID,
+			GuestName:     req.GuestName,
+			RoomNumber:    req.RoomNumber,
+			RatePlanID:    ratePlanID,
+			CheckIn:       req.CheckIn,
+			CheckOut:      req.CheckOut,
+			Currency:      strings.ToUpper(req.Currency),
+		}
+		if err := h.service.OpenFolio(ctx.Request.Context(), f); err != nil {
+			folioError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, f)
+	}
+}
+
+// @Summary Get Folios
+// @Description Retrieves the folios of a franchise, optionally filtered by hotel and status
+// @Tags folio
+// @Produce  json
+// @Param   id        path   string  true   "Franquicia ID"
+// @Param   hotel_id  query  string  false  "Hotel ID"
+// @Param   status    query  string  false  "OPEN or CLOSED"
+// @Param   currency  query  string  false  "ISO-4217 currency to convert the amounts into"
+// @Success 200 {array} domain.Folio
+// @Failure 400,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios [get]
+func (h *Folio) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		status := domain.FolioStatus(strings.ToUpper(ctx.Query("status")))
+		folios, err := h.service.GetFolios(ctx.Request.Context(), franquiciaID, ctx.Query("hotel_id"), status)
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		if to := strings.ToUpper(ctx.Query("currency")); to != "" {
+			for i := range folios {
+				if folios[i], err = h.service.InCurrency(ctx.Request.Context(), folios[i], to); err != nil {
+					folioError(ctx, err)
+					return
+				}
+			}
+		}
+		ctx.JSON(http.StatusOK, folios)
+	}
+}
+
+// @Summary Get Folio by ID
+// @Description Retrieves a folio with its charges and payments
+// @Tags folio
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   folioId   path   string  true   "Folio ID"
+// @Param   currency  query  string  false  "ISO-4217 currency to convert the amounts into"
+// @Success 200 {object} domain.Folio
+// @Failure 400,404,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios/{folioId} [get]
+func (h *Folio) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "folioId")
+		if !ok {
+			return
+		}
+
+		f, err := h.service.GetFolio(ctx.Request.Context(), franquiciaID, id)
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		if to := strings.ToUpper(ctx.Query("currency")); to != "" {
+			if f, err = h.service.InCurrency(ctx.Request.Context(), f, to); err != nil {
+				folioError(ctx, err)
+				return
+			}
+		}
+		ctx.JSON(http.StatusOK, f)
+	}
+}
+
+// @Summary Post a charge
+// @Description Posts an extra (minibar, spa, parking, other) to an open folio, applying the hotel taxes
+// @Tags folio
+// @Accept  json
+// @Produce  json
+// @Param   id             path  string                true  "Franquicia ID"
+// @Param   folioId        path  string                true  "Folio ID"
+// @Param   ChargeRequest  body  domain.ChargeRequest  true  "Charge Request"
+// @Success 201 {object} domain.Charge
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios/{folioId}/charges [post]
+func (h *Folio) PostCharge() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "folioId")
+		if !ok {
+			return
+		}
+
+		var req domain.ChargeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		charge, err := h.service.PostCharge(ctx.Request.Context(), franquiciaID, id, req)
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, charge)
+	}
+}
+
+// @Summary Post a payment or refund
+// @Description Posts a payment or a refund to an open folio
+// @Tags folio
+// @Accept  json
+// @Produce  json
+// @Param   id              path  string                 true  "Franquicia ID"
+// @Param   folioId         path  string                 true  "Folio ID"
+// @Param   PaymentRequest  body  domain.PaymentRequest  true  "Payment Request"
+// @Success 201 {object} domain.Payment
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios/{folioId}/payments [post]
+func (h *Folio) PostPayment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "folioId")
+		if !ok {
+			return
+		}
+
+		var req domain.PaymentRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		p, err := h.service.PostPayment(ctx.Request.Context(), franquiciaID, id, req)
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, p)
+	}
+}
+
+// @Summary Run the night audit
+// @Description Posts the room charge of the given night to every open folio of the franchise
+// @Tags folio
+// @Produce  json
+// @Param   id    path   string  true   "Franquicia ID"
+// @Param   date  query  string  false  "Night (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios/night-audit [post]
+func (h *Folio) NightAudit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		date := ctx.DefaultQuery("date", time.Now().UTC().Format(domain.DateLayout))
+		posted, err := h.service.PostRoomCharges(ctx.Request.Context(), franquiciaID, date)
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"date": date, "posted": posted})
+	}
+}
+
+// @Summary Checkout
+// @Description Posts pending room nights, issues the invoice and closes the folio. Repeating the checkout of a folio left closing with an issued invoice finishes closing it and returns that invoice
+// @Tags folio
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   folioId  path  string  true  "Folio ID"
+// @Success 201 {object} domain.Invoice
+// @Failure 400,404,409,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/folios/{folioId}/checkout [post]
+func (h *Folio) Checkout() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "folioId")
+		if !ok {
+			return
+		}
+
+		inv, err := h.service.Checkout(ctx.Request.Context(), franquiciaID, id)
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, inv)
+	}
+}
+
+// @Summary Set hotel taxes
+// @Description Creates or replaces the tax configuration of a hotel's jurisdiction
+// @Tags folio
+// @Accept  json
+// @Produce  json
+// @Param   id                path  string                   true  "Franquicia ID"
+// @Param   hotelId           path  string                   true  "Hotel ID"
+// @Param   TaxConfigRequest  body  domain.TaxConfigRequest  true  "Tax Configuration"
+// @Success 200 {object} domain.TaxConfig
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/taxes [put]
+func (h *Folio) SaveTaxes() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.TaxConfigRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		cfg := &domain.TaxConfig{
+			FranquiciaID: franquiciaID,
+			HotelID:      ctx.Param("hotelId"),
+			Jurisdiction: req.Jurisdiction,
+			Rules:        req.Rules,
+		}
+		if err := h.service.SaveTaxConfig(ctx.Request.Context(), cfg); err != nil {
+			folioError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, cfg)
+	}
+}
+
+// @Summary Get hotel taxes
+// @Description Retrieves the tax configuration of a hotel
+// @Tags folio
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Success 200 {object} domain.TaxConfig
+// @Failure 400,404,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/taxes [get]
+func (h *Folio) GetTaxes() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		cfg, err := h.service.GetTaxConfig(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"))
+		if err != nil {
+			folioError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, cfg)
+	}
+}
+
+func folioError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, folio.ErrNotFound), errors.Is(err, folio.ErrTaxConfigNotFound),
+		errors.Is(err, rateplan.ErrNotFound), errors.Is(err, invoice.ErrNotFound),
+		errors.Is(err, housekeeping.ErrRoomNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, folio.ErrInvalidFolio), errors.Is(err, rateplan.ErrInvalidStay),
+		errors.Is(err, rateplan.ErrInvalidRatePlan), errors.Is(err, currency.ErrInvalidCurrency):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, folio.ErrFolioClosed), errors.Is(err, folio.ErrCheckoutInProgress),
+		errors.Is(err, folio.ErrFolioBusy), errors.Is(err, invoice.ErrAlreadyInvoiced),
+		errors.Is(err, housekeeping.ErrRoomNotReady), errors.Is(err, housekeeping.ErrRoomOccupied):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, folio.ErrOutstandingBalance), errors.Is(err, rateplan.ErrStayRestriction),
+		errors.Is(err, currency.ErrRateNotFound):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -2,8 +2,15 @@ package handler
 
 import (
 	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/domaininfo"
+	"clubhub-hotel-management/internal/fingerprint"
 	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/mailsec"
+	"clubhub-hotel-management/internal/website"
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,13 +25,13 @@ func NewUser(service franquicia.Service) *Franquicia {
 }
 
 // @Summary Create a new Franquicia
-// @Description Create a new Franquicia with the given details
+// @Description Create a new Franquicia with the given details. In best-effort mode (default) the franchise is saved with whatever the enrichment steps could fetch; in strict mode nothing is saved if any of the required steps (domain-info and ssl when none are listed) fails
 // @Tags franquicia
 // @Accept  json
 // @Produce  json
 // @Param   FranquiciaRequest  body  domain.FranquiciaRequest  true  "Franquicia Request"
 // @Success 201  {object}  map[string]interface{}
-// @Failure 400,500  {object}  map[string]interface{}
+// @Failure 400,502,504,500  {object}  map[string]interface{}
 // @Router /franquicia [post]
 func (f *Franquicia) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -35,21 +42,53 @@ func (f *Franquicia) Create() gin.HandlerFunc {
 		}
 
 		if req.URL == "" {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "name and URL are required"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "URL is required"})
 			return
 		}
 
 		franquicia := &domain.Franquicia{
-			URL: req.URL,
+			Name: strings.TrimSpace(req.Name),
+			URL:  req.URL,
 		}
 
-		err := f.service.CreateFranquicia(ctx, franquicia)
+		err := f.service.CreateFranquicia(ctx.Request.Context(), franquicia, domain.CreateOptions{Mode: req.Mode, Required: req.Required})
+		succeeded, failed := enrichmentSteps(franquicia.Enrichment)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			createError(ctx, err, gin.H{"error": err.Error(), "succeeded": succeeded, "failed": failed, "enrichment": franquicia.Enrichment})
 			return
 		}
 
-		ctx.JSON(http.StatusCreated, gin.H{"status": "ok"})
+		status := "ok"
+		if len(failed) > 0 {
+			status = "partial"
+		}
+		ctx.JSON(http.StatusCreated, gin.H{"status": status, "id": franquicia.ID, "succeeded": succeeded, "failed": failed, "enrichment": franquicia.Enrichment})
+	}
+}
+
+// enrichmentSteps separa los pasos que salieron bien de los que fallaron o se saltearon.
+func enrichmentSteps(results []domain.EnrichmentResult) ([]string, []string) {
+	succeeded, failed := []string{}, []string{}
+	for _, r := range results {
+		if r.Status == domain.EnrichmentOK {
+			succeeded = append(succeeded, r.Name)
+		} else {
+			failed = append(failed, r.Name)
+		}
+	}
+	return succeeded, failed
+}
+
+func createError(ctx *gin.Context, err error, body gin.H) {
+	switch {
+	case errors.Is(err, franquicia.ErrInvalidCreateOptions):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, franquicia.ErrEnrichmentFailed):
+		ctx.JSON(http.StatusBadGateway, body)
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, body)
+	default:
+		ctx.JSON(http.StatusInternalServerError, body)
 	}
 }
 
@@ -68,7 +107,7 @@ func (f *Franquicia) GetByLocation() gin.HandlerFunc {
 		city := ctx.Query("city")
 		country := ctx.Query("country")
 
-		franquicias, err := f.service.GetByLocation(ctx, city, country)
+		franquicias, err := f.service.GetByLocation(ctx.Request.Context(), city, country)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -78,21 +117,27 @@ func (f *Franquicia) GetByLocation() gin.HandlerFunc {
 }
 
 // @Summary Get Franquicias by Date Range
-// @Description Retrieves franquicias within a specified date range
+// @Description Retrieves franquicias whose domain created and/or expiry date falls within the range (ISO-8601, inclusive)
 // @Tags franquicia
 // @Accept  json
 // @Produce  json
-// @Param   start    query     string     true     "Start Date"
-// @Param   end      query     string     true     "End Date"
+// @Param   from     query     string     false    "Start date (ISO-8601); alias: start"
+// @Param   to       query     string     false    "End date (ISO-8601); alias: end"
+// @Param   field    query     string     false    "created (default), expiry, both or either"
 // @Success 200 {array} domain.Franquicia
 // @Failure 400,404,500 {object} map[string]interface{}
 // @Router /franquicia/daterange [get]
 func (f *Franquicia) GetFranquiciasByDateRange() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		startDate := ctx.Query("start")
-		endDate := ctx.Query("end")
+		from := ctx.DefaultQuery("from", ctx.Query("start"))
+		to := ctx.DefaultQuery("to", ctx.Query("end"))
+		field := domain.DateRangeField(strings.ToLower(ctx.Query("field")))
 
-		franquicias, err := f.service.GetByDateRange(ctx, startDate, endDate)
+		franquicias, err := f.service.GetByDateRange(ctx.Request.Context(), from, to, field)
+		if errors.Is(err, franquicia.ErrInvalidDateRange) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -108,14 +153,14 @@ func (f *Franquicia) GetFranquiciasByDateRange() gin.HandlerFunc {
 // @Produce  json
 // @Param   id       path      string     true     "Franquicia ID"
 // @Success 200 {object} domain.Franquicia
-// @Failure 400,404,500 {object} map[string]interface{}
+// @Failure 404,500,504 {object} map[string]interface{}
 // @Router /franquicia/{id} [get]
 func (f *Franquicia) GetFranquiciaByID() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		id := ctx.Param("id")
-		franquicia, err := f.service.GetFranquiciaByID(ctx, id)
+		franquicia, err := f.service.GetFranquiciaByID(ctx.Request.Context(), id)
 		if err != nil {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			getError(ctx, err)
 			return
 		}
 		ctx.JSON(http.StatusOK, franquicia)
@@ -135,7 +180,7 @@ func (h *Franquicia) GetFranquiciasByName() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		name := ctx.Query("name")
 
-		franquicias, err := h.service.GetByFranchiseName(ctx, name)
+		franquicias, err := h.service.GetByFranchiseName(ctx.Request.Context(), name)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -145,6 +190,37 @@ func (h *Franquicia) GetFranquiciasByName() gin.HandlerFunc {
 
 }
 
+// @Summary Get Franquicias by Technology
+// @Description Retrieves franquicias whose website uses a detected technology. With version_below only franquicias on an older detected version are returned
+// @Tags franquicia
+// @Produce  json
+// @Param   name           query  string  false  "Technology name, e.g. WordPress"
+// @Param   category       query  string  false  "Technology category, e.g. Booking engines"
+// @Param   version_below  query  string  false  "Only versions lower than this one (requires name)"
+// @Success 200 {array} domain.Franquicia
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/technology [get]
+func (f *Franquicia) GetFranquiciasByTechnology() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := domain.TechnologyFilter{
+			Name:         strings.TrimSpace(ctx.Query("name")),
+			Category:     strings.TrimSpace(ctx.Query("category")),
+			VersionBelow: strings.TrimSpace(ctx.Query("version_below")),
+		}
+
+		franquicias, err := f.service.GetByTechnology(ctx.Request.Context(), filter)
+		if errors.Is(err, franquicia.ErrInvalidTechnologyQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, franquicias)
+	}
+}
+
 // @Summary Get All Franquicias
 // @Description Retrieves all franquicias
 // @Tags franquicia
@@ -155,7 +231,7 @@ func (h *Franquicia) GetFranquiciasByName() gin.HandlerFunc {
 // @Router /franquicias [get]
 func (f *Franquicia) GetAllFranquicias() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		franquicias, err := f.service.GetAllFranquicias(ctx)
+		franquicias, err := f.service.GetAllFranquicias(ctx.Request.Context())
 		if err != nil {
 			ctx.Error(err)
 			return
@@ -202,7 +278,7 @@ func (f *Franquicia) UpdateFranquicia() gin.HandlerFunc {
 			Location: req.Location,
 		}
 
-		if err := f.service.UpdateFranquicia(ctx, fr); err != nil {
+		if err := f.service.UpdateFranquicia(ctx.Request.Context(), fr); err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -210,3 +286,141 @@ func (f *Franquicia) UpdateFranquicia() gin.HandlerFunc {
 		ctx.JSON(http.StatusOK, gin.H{"message": "Franquicia actualizada correctamente"})
 	}
 }
+
+// @Summary Get raw WHOIS/RDAP responses
+// @Description Retrieves the archived raw WHOIS text and RDAP JSON of a franquicia, newest first
+// @Tags franquicia
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 200 {array} domain.RawDomainResponse
+// @Failure 404,504,500 {object} map[string]interface{}
+// @Router /franchises/{id}/whois/raw [get]
+func (f *Franquicia) GetRawWhois() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		responses, err := f.service.GetRawDomainInfo(ctx.Request.Context(), ctx.Param("id"))
+		if err != nil {
+			rawWhoisError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, responses)
+	}
+}
+
+// @Summary Re-parse the archived WHOIS/RDAP response
+// @Description Parses the latest archived response again, without network calls, and updates the domain info
+// @Tags franquicia
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 200 {object} domain.Franquicia
+// @Failure 404,422,504,500 {object} map[string]interface{}
+// @Router /franchises/{id}/whois/reparse [post]
+func (f *Franquicia) ReparseWhois() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquicia, err := f.service.ReparseDomainInfo(ctx.Request.Context(), ctx.Param("id"))
+		if err != nil {
+			rawWhoisError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, franquicia)
+	}
+}
+
+// @Summary Audit the website security headers
+// @Description Fetches the franchise homepage, grades HSTS, CSP, X-Frame-Options, X-Content-Type-Options, Referrer-Policy, Permissions-Policy, cookie flags and mixed content, and stores the result in the domain info
+// @Tags franquicia
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 200 {object} domain.Franquicia
+// @Failure 404,502,504,500 {object} map[string]interface{}
+// @Router /franchises/{id}/security-audit [post]
+func (f *Franquicia) AuditSecurityHeaders() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquicia, err := f.service.AuditSecurityHeaders(ctx.Request.Context(), ctx.Param("id"))
+		if err != nil {
+			securityAuditError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, franquicia)
+	}
+}
+
+// @Summary Detect the website technologies
+// @Description Fetches the franchise homepage and detects its CMS, booking engine, analytics and CDN from headers, cookies, meta tags, script sources and DNS CNAMEs
+// @Tags franquicia
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 200 {object} domain.Franquicia
+// @Failure 404,502,504,500 {object} map[string]interface{}
+// @Router /franchises/{id}/technologies [post]
+func (f *Franquicia) DetectTechnologies() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquicia, err := f.service.DetectTechnologies(ctx.Request.Context(), ctx.Param("id"))
+		if err != nil {
+			securityAuditError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, franquicia)
+	}
+}
+
+// @Summary Check the email security posture
+// @Description Checks SPF (with include expansion and the 10-lookup limit), DMARC, common DKIM selectors, MTA-STS and BIMI of the franchise domain and stores the scored report in the domain info
+// @Tags franquicia
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 200 {object} domain.Franquicia
+// @Failure 404,502,504,500 {object} map[string]interface{}
+// @Router /franchises/{id}/email-security [post]
+func (f *Franquicia) CheckEmailSecurity() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquicia, err := f.service.CheckEmailSecurity(ctx.Request.Context(), ctx.Param("id"))
+		if err != nil {
+			securityAuditError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, franquicia)
+	}
+}
+
+// getError responde 404 solo si la franquicia no existe o el ID no es válido; una falla
+// de la base no es un 404.
+func getError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, franquicia.ErrNotFound), errors.Is(err, primitive.ErrInvalidHex):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// securityAuditError responde los errores de los análisis que consultan el sitio o el DNS
+// de la franquicia.
+func securityAuditError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, franquicia.ErrNotFound), errors.Is(err, primitive.ErrInvalidHex):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, website.ErrNoSecurityAudit), errors.Is(err, fingerprint.ErrNoHomepage),
+		errors.Is(err, mailsec.ErrDNSUnavailable):
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func rawWhoisError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, franquicia.ErrNotFound), errors.Is(err, primitive.ErrInvalidHex),
+		errors.Is(err, domaininfo.ErrRawResponseNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domaininfo.ErrDomainNotFound), errors.Is(err, domaininfo.ErrNotSupported):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
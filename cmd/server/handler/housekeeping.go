@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/housekeeping"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Housekeeping struct {
+	service housekeeping.Service
+}
+
+func NewHousekeeping(service housekeeping.Service) *Housekeeping {
+	return &Housekeeping{service: service}
+}
+
+// @Summary Get the room status board
+// @Description Retrieves the status of every room of the hotel and the open cleaning tasks
+// @Tags housekeeping
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Success 200 {object} domain.HousekeepingBoard
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/rooms/board [get]
+func (h *Housekeeping) GetBoard() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		board, err := h.service.GetBoard(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"))
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, board)
+	}
+}
+
+// @Summary Stream the room status board
+// @Description Server-sent events: a "board" snapshot followed by a "room" event on every status change
+// @Tags housekeeping
+// @Produce  text/event-stream
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Success 200 {object} domain.RoomStatusEvent
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/rooms/board/stream [get]
+func (h *Housekeeping) StreamBoard() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		hotelID := ctx.Param("hotelId")
+
+		// Suscribirse antes del snapshot para no perder cambios entre ambos.
+		events, cancel := h.service.Subscribe(franquiciaID, hotelID)
+		defer cancel()
+
+		board, err := h.service.GetBoard(ctx.Request.Context(), franquiciaID, hotelID)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+
+		ctx.SSEvent("board", board)
+		ctx.Stream(func(w io.Writer) bool {
+			select {
+			case evt, ok := <-events:
+				if !ok {
+					return false
+				}
+				ctx.SSEvent("room", evt)
+				return true
+			case <-ctx.Request.Context().Done():
+				return false
+			}
+		})
+	}
+}
+
+// @Summary Set room status
+// @Description Changes the housekeeping status of a registered room (DIRTY, IN_PROGRESS, CLEAN, INSPECTED)
+// @Tags housekeeping
+// @Accept  json
+// @Produce  json
+// @Param   id                 path  string                    true  "Franquicia ID"
+// @Param   hotelId            path  string                    true  "Hotel ID"
+// @Param   number             path  string                    true  "Room number"
+// @Param   RoomStatusRequest  body  domain.RoomStatusRequest  true  "Room Status"
+// @Success 200 {object} domain.Room
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/rooms/{number}/status [put]
+func (h *Housekeeping) SetRoomStatus() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.RoomStatusRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		status := domain.RoomStatus(strings.ToUpper(string(req.Status)))
+		room, err := h.service.SetRoomStatus(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), ctx.Param("number"), status)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, room)
+	}
+}
+
+// @Summary Set room type
+// @Description Sets the type of a room, used to group rooms in the room type calendar feeds. Registers the room if it doesn't exist
+// @Tags housekeeping
+// @Accept  json
+// @Produce  json
+// @Param   id               path  string                  true  "Franquicia ID"
+// @Param   hotelId          path  string                  true  "Hotel ID"
+// @Param   number           path  string                  true  "Room number"
+// @Param   RoomTypeRequest  body  domain.RoomTypeRequest  true  "Room Type"
+// @Success 200 {object} domain.Room
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/rooms/{number}/type [put]
+func (h *Housekeeping) SetRoomType() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.RoomTypeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		room, err := h.service.SetRoomType(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), ctx.Param("number"), req.Type)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, room)
+	}
+}
+
+// @Summary Get housekeeping tasks
+// @Description Retrieves the cleaning tasks of a hotel
+// @Tags housekeeping
+// @Produce  json
+// @Param   id           path   string  true   "Franquicia ID"
+// @Param   hotelId      path   string  true   "Hotel ID"
+// @Param   status       query  string  false  "PENDING, IN_PROGRESS, DONE or INSPECTED"
+// @Param   assigned_to  query  string  false  "Staff member"
+// @Success 200 {array} domain.HousekeepingTask
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/housekeeping/tasks [get]
+func (h *Housekeeping) GetTasks() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		status := domain.TaskStatus(strings.ToUpper(ctx.Query("status")))
+		tasks, err := h.service.GetTasks(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), status, ctx.Query("assigned_to"))
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, tasks)
+	}
+}
+
+// @Summary Assign a housekeeping task
+// @Description Assigns a cleaning task to a staff member
+// @Tags housekeeping
+// @Accept  json
+// @Produce  json
+// @Param   id                 path  string                    true  "Franquicia ID"
+// @Param   hotelId            path  string                    true  "Hotel ID"
+// @Param   taskId             path  string                    true  "Task ID"
+// @Param   TaskAssignRequest  body  domain.TaskAssignRequest  true  "Assignment"
+// @Success 200 {object} domain.HousekeepingTask
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/housekeeping/tasks/{taskId}/assign [post]
+func (h *Housekeeping) AssignTask() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, id, ok := taskParams(ctx)
+		if !ok {
+			return
+		}
+
+		var req domain.TaskAssignRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		task, err := h.service.AssignTask(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), id, req.Staff)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, task)
+	}
+}
+
+// @Summary Start a housekeeping task
+// @Description Marks the task and its room as in progress
+// @Tags housekeeping
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Param   taskId   path  string  true  "Task ID"
+// @Success 200 {object} domain.HousekeepingTask
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/housekeeping/tasks/{taskId}/start [post]
+func (h *Housekeeping) StartTask() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, id, ok := taskParams(ctx)
+		if !ok {
+			return
+		}
+
+		task, err := h.service.StartTask(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), id)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, task)
+	}
+}
+
+// @Summary Complete a housekeeping task
+// @Description Marks the task as done and its room as clean
+// @Tags housekeeping
+// @Produce  json
+// @Param   id       path  string  true  "Franquicia ID"
+// @Param   hotelId  path  string  true  "Hotel ID"
+// @Param   taskId   path  string  true  "Task ID"
+// @Success 200 {object} domain.HousekeepingTask
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/housekeeping/tasks/{taskId}/complete [post]
+func (h *Housekeeping) CompleteTask() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, id, ok := taskParams(ctx)
+		if !ok {
+			return
+		}
+
+		task, err := h.service.CompleteTask(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), id)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, task)
+	}
+}
+
+// @Summary Inspect a housekeeping task
+// @Description Marks the task and its room as inspected
+// @Tags housekeeping
+// @Accept  json
+// @Produce  json
+// @Param   id                  path  string                     true  "Franquicia ID"
+// @Param   hotelId             path  string                     true  "Hotel ID"
+// @Param   taskId              path  string                     true  "Task ID"
+// @Param   TaskInspectRequest  body  domain.TaskInspectRequest  false "Inspection"
+// @Success 200 {object} domain.HousekeepingTask
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/hotels/{hotelId}/housekeeping/tasks/{taskId}/inspect [post]
+func (h *Housekeeping) InspectTask() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, id, ok := taskParams(ctx)
+		if !ok {
+			return
+		}
+
+		var req domain.TaskInspectRequest
+		if ctx.Request.ContentLength > 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+				return
+			}
+		}
+
+		task, err := h.service.InspectTask(ctx.Request.Context(), franquiciaID, ctx.Param("hotelId"), id, req.Inspector)
+		if err != nil {
+			housekeepingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, task)
+	}
+}
+
+func taskParams(ctx *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	franquiciaID, ok := objectIDParam(ctx, "id")
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	id, ok := objectIDParam(ctx, "taskId")
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return franquiciaID, id, true
+}
+
+func housekeepingError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, housekeeping.ErrTaskNotFound), errors.Is(err, housekeeping.ErrRoomNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, housekeeping.ErrInvalidTask), errors.Is(err, housekeeping.ErrInvalidRoom):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, housekeeping.ErrInvalidTransition), errors.Is(err, housekeeping.ErrRoomNotReady):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
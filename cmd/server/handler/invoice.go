@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/invoice"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Invoice struct {
+	service invoice.Service
+}
+
+func NewInvoice(service invoice.Service) *Invoice {
+	return &Invoice{service: service}
+}
+
+// @Summary Get Invoices
+// @Description Retrieves the invoices of a franchise ordered by hotel and number
+// @Tags invoice
+// @Produce  json
+// @Param   id        path   string  true   "Franquicia ID"
+// @Param   hotel_id  query  string  false  "Hotel ID"
+// @Param   currency  query  string  false  "ISO-4217 currency to convert the amounts into"
+// @Success 200 {array} domain.Invoice
+// @Failure 400,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/invoices [get]
+func (h *Invoice) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		invoices, err := h.service.GetInvoices(ctx.Request.Context(), franquiciaID, ctx.Query("hotel_id"))
+		if err != nil {
+			invoiceError(ctx, err)
+			return
+		}
+		if to := strings.ToUpper(ctx.Query("currency")); to != "" {
+			for i := range invoices {
+				if invoices[i], err = h.service.InCurrency(ctx.Request.Context(), invoices[i], to); err != nil {
+					invoiceError(ctx, err)
+					return
+				}
+			}
+		}
+		ctx.JSON(http.StatusOK, invoices)
+	}
+}
+
+// @Summary Get Invoice by ID
+// @Description Retrieves an invoice as structured JSON
+// @Tags invoice
+// @Produce  json
+// @Param   id         path  string  true  "Franquicia ID"
+// @Param   invoiceId  path   string  true   "Invoice ID"
+// @Param   currency   query  string  false  "ISO-4217 currency to convert the amounts into"
+// @Success 200 {object} domain.Invoice
+// @Failure 400,404,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/invoices/{invoiceId} [get]
+func (h *Invoice) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "invoiceId")
+		if !ok {
+			return
+		}
+
+		inv, err := h.service.GetInvoice(ctx.Request.Context(), franquiciaID, id)
+		if err != nil {
+			invoiceError(ctx, err)
+			return
+		}
+		if to := strings.ToUpper(ctx.Query("currency")); to != "" {
+			if inv, err = h.service.InCurrency(ctx.Request.Context(), inv, to); err != nil {
+				invoiceError(ctx, err)
+				return
+			}
+		}
+		ctx.JSON(http.StatusOK, inv)
+	}
+}
+
+// @Summary Download Invoice PDF
+// @Description Renders an invoice as PDF
+// @Tags invoice
+// @Produce  application/pdf
+// @Param   id         path  string  true  "Franquicia ID"
+// @Param   invoiceId  path  string  true  "Invoice ID"
+// @Success 200 {file} file
+// @Failure 400,404,500 {object} map[string]interface{}
+// @Router /franchises/{id}/invoices/{invoiceId}/pdf [get]
+func (h *Invoice) GetPDF() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "invoiceId")
+		if !ok {
+			return
+		}
+
+		inv, err := h.service.GetInvoice(ctx.Request.Context(), franquiciaID, id)
+		if err != nil {
+			invoiceError(ctx, err)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := h.service.RenderPDF(ctx.Request.Context(), inv, &buf); err != nil {
+			invoiceError(ctx, err)
+			return
+		}
+
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": "factura-" + inv.Number + ".pdf"})
+		ctx.Header("Content-Disposition", disposition)
+		ctx.Data(http.StatusOK, "application/pdf", buf.Bytes())
+	}
+}
+
+func invoiceError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, invoice.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, currency.ErrInvalidCurrency):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, currency.ErrRateNotFound):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/media"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mediaCacheControl: el contenido de /media/:id?size=... no cambia nunca, así que
+// los clientes y CDNs pueden guardarlo indefinidamente.
+const mediaCacheControl = "public, max-age=31536000, immutable"
+
+// mediaCSP impide que un SVG abierto directamente ejecute scripts o cargue recursos;
+// no afecta a las imágenes mostradas con <img>.
+const mediaCSP = "default-src 'none'; style-src 'unsafe-inline'; sandbox"
+
+type Media struct {
+	service     media.Service
+	franquicias franquicia.Service
+}
+
+func NewMedia(service media.Service, franquicias franquicia.Service) *Media {
+	return &Media{service: service, franquicias: franquicias}
+}
+
+// @Summary Upload an image
+// @Description Uploads a logo, favicon or hotel photo and generates its thumbnails
+// @Tags media
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   id        path      string  true   "Franquicia ID"
+// @Param   file      formData  file    true   "Image"
+// @Param   kind      formData  string  false  "logo, favicon or photo (default photo)"
+// @Param   hotel_id  formData  string  false  "Hotel ID"
+// @Success 201 {object} domain.MediaAsset
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/media [post]
+func (h *Media) Upload() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		if _, err := h.franquicias.GetFranquiciaByID(ctx.Request.Context(), franquiciaID.Hex()); err != nil {
+			mediaError(ctx, err)
+			return
+		}
+
+		file, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		data, err := io.ReadAll(io.LimitReader(f, media.MaxUploadSize+1))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		kind := domain.MediaKind(ctx.DefaultPostForm("kind", string(domain.MediaKindPhoto)))
+		asset, err := h.service.Store(ctx.Request.Context(), franquiciaID, ctx.PostForm("hotel_id"), kind, "", data)
+		if err != nil {
+			mediaError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, asset)
+	}
+}
+
+// @Summary Get images
+// @Description Retrieves the logos, favicons and photos of a franchise, newest first
+// @Tags media
+// @Produce  json
+// @Param   id        path   string  true   "Franquicia ID"
+// @Param   hotel_id  query  string  false  "Hotel ID"
+// @Success 200 {array} domain.MediaAsset
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/media [get]
+func (h *Media) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		assets, err := h.service.GetAssets(ctx.Request.Context(), franquiciaID, ctx.Query("hotel_id"))
+		if err != nil {
+			mediaError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, assets)
+	}
+}
+
+// @Summary Serve an image
+// @Description Serves an image or one of its thumbnails, with a content-hash ETag and long-lived cache headers. SVGs are served as attachments under a restrictive CSP
+// @Tags media
+// @Produce  image/png,image/jpeg,image/gif,image/webp,image/svg+xml,image/x-icon
+// @Param   id    path   string  true   "Media ID"
+// @Param   size  query  string  false  "original, thumb, small or medium"
+// @Success 200 {file} file
+// @Success 304
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /media/{id} [get]
+func (h *Media) Serve() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		variant, data, err := h.service.Open(ctx.Request.Context(), id, ctx.Query("size"))
+		if err != nil {
+			mediaError(ctx, err)
+			return
+		}
+
+		etag := `"` + variant.Hash + `"`
+		ctx.Header("ETag", etag)
+		ctx.Header("Cache-Control", mediaCacheControl)
+		ctx.Header("X-Content-Type-Options", "nosniff")
+		ctx.Header("Content-Security-Policy", mediaCSP)
+		// Un SVG puede tener scripts: se descarga en lugar de abrirse en el origen de la API.
+		if variant.ContentType == "image/svg+xml" {
+			ctx.Header("Content-Disposition", "attachment")
+		}
+		if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+		ctx.Data(http.StatusOK, variant.ContentType, data)
+	}
+}
+
+// etagMatches compara If-None-Match con el ETag; acepta listas, "*" y validadores débiles.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func mediaError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, media.ErrNotFound), errors.Is(err, media.ErrBlobNotFound), errors.Is(err, franquicia.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrInvalidMedia), errors.Is(err, media.ErrUnknownSize):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
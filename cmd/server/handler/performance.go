@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/performance"
+	"clubhub-hotel-management/internal/period"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Performance struct {
+	service performance.Service
+}
+
+func NewPerformance(service performance.Service) *Performance {
+	return &Performance{service: service}
+}
+
+// @Summary Measure the franchise website
+// @Description Measures the homepage: availability, DNS, TCP connect, TLS handshake, TTFB and download times, protocol (HTTP/2, HTTP/3 via Alt-Svc), compression, page weight and number of requests. The sample is stored even when the site is down
+// @Tags performance
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 201 {object} domain.PerformanceSample
+// @Failure 400,404,502,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/performance [post]
+func (h *Performance) Run() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		sample, err := h.service.Run(ctx.Request.Context(), franquiciaID)
+		if err != nil {
+			performanceError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, sample)
+	}
+}
+
+// @Summary Get performance samples
+// @Description Retrieves the performance time series of a franchise website, oldest first
+// @Tags performance
+// @Produce  json
+// @Param   id    path   string  true   "Franquicia ID"
+// @Param   from  query  string  false  "From date (YYYY-MM-DD)"
+// @Param   to    query  string  false  "To date (YYYY-MM-DD, inclusive)"
+// @Success 200 {array} domain.PerformanceSample
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/performance [get]
+func (h *Performance) GetSamples() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		samples, err := h.service.GetSamples(ctx.Request.Context(), franquiciaID, ctx.Query("from"), ctx.Query("to"))
+		if err != nil {
+			performanceError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, samples)
+	}
+}
+
+// @Summary Get the performance summary
+// @Description Retrieves the uptime, the share of samples served over HTTP/2 and min, mean, p50, p75, p90, p95, p99 and max of every timing and size metric over the period
+// @Tags performance
+// @Produce  json
+// @Param   id    path   string  true   "Franquicia ID"
+// @Param   from  query  string  false  "From date (YYYY-MM-DD)"
+// @Param   to    query  string  false  "To date (YYYY-MM-DD, inclusive)"
+// @Success 200 {object} domain.PerformanceSummary
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/performance/summary [get]
+func (h *Performance) GetSummary() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		summary, err := h.service.GetSummary(ctx.Request.Context(), franquiciaID, ctx.Query("from"), ctx.Query("to"))
+		if err != nil {
+			performanceError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, summary)
+	}
+}
+
+func performanceError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, franquicia.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, period.ErrInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, crawler.ErrDisallowedByRobots):
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
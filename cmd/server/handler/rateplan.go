@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/rateplan"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatePlan struct {
+	service rateplan.Service
+}
+
+func NewRatePlan(service rateplan.Service) *RatePlan {
+	return &RatePlan{service: service}
+}
+
+// @Summary Create a Rate Plan
+// @Description Creates a rate plan (BAR, non-refundable, corporate, package) for a hotel of the franchise
+// @Tags rateplan
+// @Accept  json
+// @Produce  json
+// @Param   id               path  string                  true  "Franquicia ID"
+// @Param   RatePlanRequest  body  domain.RatePlanRequest  true  "Rate Plan Request"
+// @Success 201 {object} domain.RatePlan
+// @Failure 400,404,500 {object} map[string]interface{}
+// @Router /franchises/{id}/rateplans [post]
+func (h *RatePlan) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.RatePlanRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		rp := ratePlanFromRequest(req)
+		rp.FranquiciaID = franquiciaID
+		if err := h.service.CreateRatePlan(ctx.Request.Context(), &rp); err != nil {
+			ratePlanError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, rp)
+	}
+}
+
+// @Summary Get Rate Plans
+// @Description Retrieves the rate plans of a franchise, optionally filtered by hotel
+// @Tags rateplan
+// @Produce  json
+// @Param   id        path   string  true   "Franquicia ID"
+// @Param   hotel_id  query  string  false  "Hotel ID"
+// @Success 200 {array} domain.RatePlan
+// @Failure 400,500 {object} map[string]interface{}
+// @Router /franchises/{id}/rateplans [get]
+func (h *RatePlan) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		plans, err := h.service.GetRatePlans(ctx.Request.Context(), franquiciaID, ctx.Query("hotel_id"))
+		if err != nil {
+			ratePlanError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, plans)
+	}
+}
+
+// @Summary Get Rate Plan by ID
+// @Description Retrieves a rate plan of the franchise
+// @Tags rateplan
+// @Produce  json
+// @Param   id      path  string  true  "Franquicia ID"
+// @Param   planId  path  string  true  "Rate Plan ID"
+// @Success 200 {object} domain.RatePlan
+// @Failure 400,404,500 {object} map[string]interface{}
+// @Router /franchises/{id}/rateplans/{planId} [get]
+func (h *RatePlan) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "planId")
+		if !ok {
+			return
+		}
+
+		rp, err := h.service.GetRatePlan(ctx.Request.Context(), franquiciaID, id)
+		if err != nil {
+			ratePlanError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, rp)
+	}
+}
+
+// @Summary Update Rate Plan
+// @Description Replaces a rate plan of the franchise
+// @Tags rateplan
+// @Accept  json
+// @Produce  json
+// @Param   id               path  string                  true  "Franquicia ID"
+// @Param   planId           path  string                  true  "Rate Plan ID"
+// @Param   RatePlanRequest  body  domain.RatePlanRequest  true  "Rate Plan Request"
+// @Success 200 {object} domain.RatePlan
+// @Failure 400,404,500 {object} map[string]interface{}
+// @Router /franchises/{id}/rateplans/{planId} [put]
+func (h *RatePlan) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "planId")
+		if !ok {
+			return
+		}
+
+		var req domain.RatePlanRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+
+		rp := ratePlanFromRequest(req)
+		rp.ID = id
+		rp.FranquiciaID = franquiciaID
+		if err := h.service.UpdateRatePlan(ctx.Request.Context(), rp); err != nil {
+			ratePlanError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, rp)
+	}
+}
+
+// @Summary Delete Rate Plan
+// @Description Deletes a rate plan of the franchise. Plans with derived plans can't be deleted
+// @Tags rateplan
+// @Produce  json
+// @Param   id      path  string  true  "Franquicia ID"
+// @Param   planId  path  string  true  "Rate Plan ID"
+// @Success 204
+// @Failure 400,404,409,500 {object} map[string]interface{}
+// @Router /franchises/{id}/rateplans/{planId} [delete]
+func (h *RatePlan) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "planId")
+		if !ok {
+			return
+		}
+
+		if err := h.service.DeleteRatePlan(ctx.Request.Context(), franquiciaID, id); err != nil {
+			ratePlanError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Quote a stay
+// @Description Computes the price of a stay with a per-night breakdown
+// @Tags rateplan
+// @Produce  json
+// @Param   id         path   string  true  "Franquicia ID"
+// @Param   planId     path   string  true  "Rate Plan ID"
+// @Param   check_in   query  string  true  "Check-in date (YYYY-MM-DD)"
+// @Param   check_out  query  string  true  "Check-out date (YYYY-MM-DD)"
+// @Param   currency   query  string  false "ISO-4217 currency to convert the quote into"
+// @Success 200 {object} domain.RateQuote
+// @Failure 400,404,422,500 {object} map[string]interface{}
+// @Router /franchises/{id}/rateplans/{planId}/quote [get]
+func (h *RatePlan) Quote() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "planId")
+		if !ok {
+			return
+		}
+
+		toCurrency := strings.ToUpper(ctx.Query("currency"))
+		quote, err := h.service.Quote(ctx.Request.Context(), franquiciaID, id, ctx.Query("check_in"), ctx.Query("check_out"), toCurrency)
+		if err != nil {
+			ratePlanError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, quote)
+	}
+}
+
+func ratePlanFromRequest(req domain.RatePlanRequest) domain.RatePlan {
+	return domain.RatePlan{
+		HotelID:              req.HotelID,
+		Code:                 req.Code,
+		Name:                 req.Name,
+		Type:                 req.Type,
+		BaseRate:             req.BaseRate,
+		Seasons:              req.Seasons,
+		DayOfWeekAdjustments: req.DayOfWeekAdjustments,
+		MinLOS:               req.MinLOS,
+		MaxLOS:               req.MaxLOS,
+		Derived:              req.Derived,
+	}
+}
+
+func ratePlanError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, rateplan.ErrNotFound), errors.Is(err, rateplan.ErrFranchiseNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, rateplan.ErrInvalidRatePlan), errors.Is(err, rateplan.ErrInvalidStay):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, rateplan.ErrRatePlanInUse):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, rateplan.ErrStayRestriction):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, currency.ErrInvalidCurrency):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, currency.ErrRateNotFound):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/scraping"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Scraping struct {
+	service scraping.Service
+}
+
+func NewScraping(service scraping.Service) *Scraping {
+	return &Scraping{service: service}
+}
+
+// @Summary Create a scrape profile
+// @Description Stores CSS/XPath scraping rules for a page of the franchise site. Selectors and regexes are validated
+// @Tags scraping
+// @Accept  json
+// @Produce  json
+// @Param   id                    path  string                       true  "Franquicia ID"
+// @Param   ScrapeProfileRequest  body  domain.ScrapeProfileRequest  true  "Scrape Profile"
+// @Success 201 {object} domain.ScrapeProfile
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/scrape-profiles [post]
+func (h *Scraping) CreateProfile() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		h.createProfile(ctx, &franquiciaID)
+	}
+}
+
+// @Summary Create a scrape template
+// @Description Stores scraping rules shared by every site built with the same template or CMS
+// @Tags scraping
+// @Accept  json
+// @Produce  json
+// @Param   ScrapeProfileRequest  body  domain.ScrapeProfileRequest  true  "Scrape Profile"
+// @Success 201 {object} domain.ScrapeProfile
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /scrape-templates [post]
+func (h *Scraping) CreateTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.createProfile(ctx, nil)
+	}
+}
+
+func (h *Scraping) createProfile(ctx *gin.Context, franquiciaID *primitive.ObjectID) {
+	var req domain.ScrapeProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	profile := &domain.ScrapeProfile{
+		FranquiciaID: franquiciaID,
+		Template:     strings.TrimSpace(req.Template),
+		Name:         strings.TrimSpace(req.Name),
+		Path:         strings.TrimSpace(req.Path),
+		Rules:        req.Rules,
+	}
+	if err := h.service.CreateProfile(ctx.Request.Context(), profile); err != nil {
+		scrapingError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, profile)
+}
+
+// @Summary Get scrape profiles
+// @Description Retrieves the scrape profiles of a franchise
+// @Tags scraping
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 200 {array} domain.ScrapeProfile
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/scrape-profiles [get]
+func (h *Scraping) GetProfiles() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		profiles, err := h.service.GetProfiles(ctx.Request.Context(), franquiciaID)
+		if err != nil {
+			scrapingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, profiles)
+	}
+}
+
+// @Summary Get scrape templates
+// @Description Retrieves the scrape profiles shared between franchises
+// @Tags scraping
+// @Produce  json
+// @Success 200 {array} domain.ScrapeProfile
+// @Failure 500,504 {object} map[string]interface{}
+// @Router /scrape-templates [get]
+func (h *Scraping) GetTemplates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		profiles, err := h.service.GetTemplates(ctx.Request.Context())
+		if err != nil {
+			scrapingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, profiles)
+	}
+}
+
+// @Summary Delete a scrape profile
+// @Description Deletes a scrape profile of the franchise
+// @Tags scraping
+// @Param   id         path  string  true  "Franquicia ID"
+// @Param   profileId  path  string  true  "Profile ID"
+// @Success 204
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/scrape-profiles/{profileId} [delete]
+func (h *Scraping) DeleteProfile() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+		id, ok := objectIDParam(ctx, "profileId")
+		if !ok {
+			return
+		}
+
+		if err := h.service.DeleteProfile(ctx.Request.Context(), &franquiciaID, id); err != nil {
+			scrapingError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Delete a scrape template
+// @Description Deletes a shared scrape profile
+// @Tags scraping
+// @Param   profileId  path  string  true  "Profile ID"
+// @Success 204
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /scrape-templates/{profileId} [delete]
+func (h *Scraping) DeleteTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := objectIDParam(ctx, "profileId")
+		if !ok {
+			return
+		}
+
+		if err := h.service.DeleteProfile(ctx.Request.Context(), nil, id); err != nil {
+			scrapingError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Run scrape profiles
+// @Description Runs a stored profile, a template, inline rules or every profile of the franchise. With preview the extracted values are returned without saving them
+// @Tags scraping
+// @Accept  json
+// @Produce  json
+// @Param   id             path   string                true   "Franquicia ID"
+// @Param   preview        query  bool                  false  "Preview only"
+// @Param   ScrapeRequest  body   domain.ScrapeRequest  false  "Scrape Request"
+// @Success 200 {object} domain.ScrapeResponse
+// @Failure 400,404,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/scrape [post]
+func (h *Scraping) Scrape() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		var req domain.ScrapeRequest
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+				return
+			}
+		}
+		if ctx.Query("preview") == "true" {
+			req.Preview = true
+		}
+
+		resp, err := h.service.Scrape(ctx.Request.Context(), franquiciaID, req)
+		if err != nil {
+			scrapingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+func scrapingError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, scraping.ErrProfileNotFound), errors.Is(err, franquicia.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, scraping.ErrInvalidProfile), errors.Is(err, scraping.ErrNoProfile):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/period"
+	"clubhub-hotel-management/internal/sitehealth"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SiteHealth struct {
+	service sitehealth.Service
+}
+
+func NewSiteHealth(service sitehealth.Service) *SiteHealth {
+	return &SiteHealth{service: service}
+}
+
+// @Summary Run a site health report
+// @Description Audits the franchise homepage for SEO (title, meta description, canonical, hreflang, robots.txt, sitemap.xml, broken internal links) and accessibility (lang, image alt, form labels, link text) and stores a dated report
+// @Tags sitehealth
+// @Produce  json
+// @Param   id  path  string  true  "Franquicia ID"
+// @Success 201 {object} domain.SiteHealthReport
+// @Failure 400,404,502,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/site-health [post]
+func (h *SiteHealth) Run() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		report, err := h.service.Run(ctx.Request.Context(), franquiciaID)
+		if err != nil {
+			siteHealthError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, report)
+	}
+}
+
+// @Summary Get site health reports
+// @Description Retrieves the dated site health reports of a franchise, oldest first
+// @Tags sitehealth
+// @Produce  json
+// @Param   id    path   string  true   "Franquicia ID"
+// @Param   from  query  string  false  "From date (YYYY-MM-DD)"
+// @Param   to    query  string  false  "To date (YYYY-MM-DD, inclusive)"
+// @Success 200 {array} domain.SiteHealthReport
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/site-health [get]
+func (h *SiteHealth) GetReports() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		reports, err := h.service.GetReports(ctx.Request.Context(), franquiciaID, ctx.Query("from"), ctx.Query("to"))
+		if err != nil {
+			siteHealthError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, reports)
+	}
+}
+
+// @Summary Get the site health trend
+// @Description Retrieves the SEO and accessibility scores, broken links and alt coverage over time, with the change between the first and last report of the period
+// @Tags sitehealth
+// @Produce  json
+// @Param   id    path   string  true   "Franquicia ID"
+// @Param   from  query  string  false  "From date (YYYY-MM-DD)"
+// @Param   to    query  string  false  "To date (YYYY-MM-DD, inclusive)"
+// @Success 200 {object} domain.SiteHealthTrend
+// @Failure 400,500,504 {object} map[string]interface{}
+// @Router /franchises/{id}/site-health/trend [get]
+func (h *SiteHealth) GetTrend() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		franquiciaID, ok := objectIDParam(ctx, "id")
+		if !ok {
+			return
+		}
+
+		trend, err := h.service.GetTrend(ctx.Request.Context(), franquiciaID, ctx.Query("from"), ctx.Query("to"))
+		if err != nil {
+			siteHealthError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, trend)
+	}
+}
+
+func siteHealthError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, franquicia.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, period.ErrInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, sitehealth.ErrSiteUnreachable):
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -2,8 +2,29 @@ package routes
 
 import (
 	"clubhub-hotel-management/cmd/server/handler"
+	"clubhub-hotel-management/internal/calendar"
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/db"
+	"clubhub-hotel-management/internal/domaininfo"
+	"clubhub-hotel-management/internal/env"
+	"clubhub-hotel-management/internal/fingerprint"
+	"clubhub-hotel-management/internal/folio"
 	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/housekeeping"
+	"clubhub-hotel-management/internal/invoice"
+	"clubhub-hotel-management/internal/mailsec"
+	"clubhub-hotel-management/internal/media"
+	"clubhub-hotel-management/internal/performance"
+	"clubhub-hotel-management/internal/rateplan"
+	"clubhub-hotel-management/internal/scheduler"
+	"clubhub-hotel-management/internal/scraping"
+	"clubhub-hotel-management/internal/sitehealth"
+	"context"
+	"database/sql"
+	"log"
 	"os"
+	"time"
 
 	_ "clubhub-hotel-management/docs"
 
@@ -15,14 +36,19 @@ import (
 
 type Router interface {
 	MapRoutes()
+	RunJobs(ctx context.Context)
 }
 
 type router struct {
 	r       *gin.Engine
 	rg      *gin.RouterGroup
 	mongodb *mongo.Client
+	// jobs son las tareas periódicas de los servicios; las arranca RunJobs.
+	jobs []func(context.Context)
 }
 
+// NewRouter crea el router. mongoDB es nil si no hay MongoDB configurado; entonces solo
+// se publican las rutas de franquicias.
 func NewRouter(r *gin.Engine, mongoDB *mongo.Client) Router {
 	return &router{r: r, mongodb: mongoDB}
 }
@@ -32,14 +58,41 @@ func (r *router) MapRoutes() {
 	r.buildRoutes()
 }
 
+// RunJobs arranca las tareas periódicas en segundo plano. Terminan cuando se cancela ctx.
+func (r *router) RunJobs(ctx context.Context) {
+	for _, job := range r.jobs {
+		go job(ctx)
+	}
+}
+
 func (r *router) setGroup() {
 	r.r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.rg = r.r.Group("/api/hotelmagnament/v1")
 }
 
+// buildRoutes publica las rutas de franquicias con el repositorio elegido. Las demás
+// funciones guardan sus datos en MongoDB y solo se publican si hay conexión.
 func (r *router) buildRoutes() {
-	repository := franquicia.NewRepository(r.mongodb.Database(os.Getenv("MONGODB_DATABASE_NAME")).Collection("franchises"))
-	service := franquicia.NewService(repository)
+	var database *mongo.Database
+	if r.mongodb != nil {
+		database = r.mongodb.Database(os.Getenv("MONGODB_DATABASE_NAME"))
+	}
+
+	repository := franchiseRepository(database)
+	domainInfo := domaininfo.NewChain(
+		domaininfo.NewRDAPProvider(os.Getenv("RDAP_BOOTSTRAP_URL")),
+		domaininfo.NewWhoisProvider(os.Getenv("WHOIS_SERVER")),
+	)
+	var archiveRepository domaininfo.ArchiveRepository
+	var mediaService media.Service
+	if database != nil {
+		archiveRepository = domaininfo.NewArchiveRepository(database.Collection("domain_raw_responses"))
+		mediaService = media.NewService(media.NewRepository(database.Collection("media")), mediaStore())
+	}
+	crawlerFactory := crawler.NewFactory(crawler.ConfigFromEnv())
+	techRules := fingerprint.NewRuleSource(os.Getenv("TECH_RULES_FILE"))
+	mailChecker := mailsec.NewChecker(mailsec.NewResolver(os.Getenv("DNS_RESOLVER")), crawlerFactory)
+	service := franquicia.NewService(repository, domainInfo, archiveRepository, mediaService, crawlerFactory, techRules, mailChecker, franquicia.ConfigFromEnv())
 	fHandler := handler.NewUser(service)
 	franchises := r.rg.Group("/franchises")
 	franchises.POST("/new", fHandler.Create())
@@ -49,4 +102,206 @@ func (r *router) buildRoutes() {
 	franchises.GET("/location", fHandler.GetByLocation())
 	franchises.GET("/daterange", fHandler.GetFranquiciasByDateRange())
 	franchises.GET("/name", fHandler.GetFranquiciasByName())
+	franchises.GET("/technology", fHandler.GetFranquiciasByTechnology())
+	franchises.POST("/:id/security-audit", fHandler.AuditSecurityHeaders())
+	franchises.POST("/:id/technologies", fHandler.DetectTechnologies())
+	franchises.POST("/:id/email-security", fHandler.CheckEmailSecurity())
+
+	if database == nil {
+		log.Print("MongoDB no está configurado: solo se publican las rutas de franquicias")
+		return
+	}
+
+	franchises.GET("/:id/whois/raw", fHandler.GetRawWhois())
+	franchises.POST("/:id/whois/reparse", fHandler.ReparseWhois())
+	mediaHandler := handler.NewMedia(mediaService, service)
+	r.rg.GET("/media/:id", mediaHandler.Serve())
+	franchises.POST("/:id/media", mediaHandler.Upload())
+	franchises.GET("/:id/media", mediaHandler.GetAll())
+
+	scrapingService := scraping.NewService(scraping.NewRepository(database.Collection("scrape_profiles")), repository, crawlerFactory)
+	scrapingHandler := handler.NewScraping(scrapingService)
+	franchises.POST("/:id/scrape", scrapingHandler.Scrape())
+	franchises.POST("/:id/scrape-profiles", scrapingHandler.CreateProfile())
+	franchises.GET("/:id/scrape-profiles", scrapingHandler.GetProfiles())
+	franchises.DELETE("/:id/scrape-profiles/:profileId", scrapingHandler.DeleteProfile())
+	scrapeTemplates := r.rg.Group("/scrape-templates")
+	scrapeTemplates.POST("", scrapingHandler.CreateTemplate())
+	scrapeTemplates.GET("", scrapingHandler.GetTemplates())
+	scrapeTemplates.DELETE("/:profileId", scrapingHandler.DeleteTemplate())
+
+	siteHealthService := sitehealth.NewService(sitehealth.NewRepository(database.Collection("site_health_reports")), repository, crawlerFactory)
+	shHandler := handler.NewSiteHealth(siteHealthService)
+	franchises.POST("/:id/site-health", shHandler.Run())
+	franchises.GET("/:id/site-health", shHandler.GetReports())
+	franchises.GET("/:id/site-health/trend", shHandler.GetTrend())
+	r.jobs = append(r.jobs, scheduler.New("generar los reportes de los sitios", siteHealthService.RunAll, interval("SITE_HEALTH_INTERVAL", 7*24*time.Hour)).Run)
+
+	performanceService := performance.NewService(performance.NewRepository(database.Collection("performance_samples")), repository, crawlerFactory)
+	perfHandler := handler.NewPerformance(performanceService)
+	franchises.POST("/:id/performance", perfHandler.Run())
+	franchises.GET("/:id/performance", perfHandler.GetSamples())
+	franchises.GET("/:id/performance/summary", perfHandler.GetSummary())
+	r.jobs = append(r.jobs, scheduler.New("medir los sitios", performanceService.RunAll, interval("PERFORMANCE_INTERVAL", time.Hour)).Run)
+
+	currencyRepository := currency.NewRepository(database.Collection("exchange_rates"))
+	currencyService := currency.NewService(currencyRepository)
+	erHandler := handler.NewExchangeRate(currencyService)
+	exchangeRates := r.rg.Group("/exchangerates")
+	exchangeRates.POST("", erHandler.Upload())
+	exchangeRates.GET("", erHandler.GetAll())
+
+	rpRepository := rateplan.NewRepository(database.Collection("rate_plans"))
+	rpService := rateplan.NewService(rpRepository, currencyService, repository)
+	rpHandler := handler.NewRatePlan(rpService)
+	franchises.POST("/:id/rateplans", rpHandler.Create())
+	franchises.GET("/:id/rateplans", rpHandler.GetAll())
+	franchises.GET("/:id/rateplans/:planId", rpHandler.GetByID())
+	franchises.PUT("/:id/rateplans/:planId", rpHandler.Update())
+	franchises.DELETE("/:id/rateplans/:planId", rpHandler.Delete())
+	franchises.GET("/:id/rateplans/:planId/quote", rpHandler.Quote())
+
+	invoiceRepository := invoice.NewRepository(database.Collection("invoices"), database.Collection("counters"))
+	indexCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := invoice.EnsureIndexes(indexCtx, database.Collection("invoices")); err != nil {
+		log.Printf("Error al crear los índices de facturas: %v", err)
+	}
+	cancel()
+	invoiceService := invoice.NewService(invoiceRepository, currencyService, mediaService)
+	invHandler := handler.NewInvoice(invoiceService)
+	franchises.GET("/:id/invoices", invHandler.GetAll())
+	franchises.GET("/:id/invoices/:invoiceId", invHandler.GetByID())
+	franchises.GET("/:id/invoices/:invoiceId/pdf", invHandler.GetPDF())
+
+	folioRepository := folio.NewRepository(database.Collection("folios"))
+	hkRepository := housekeeping.NewRepository(database.Collection("rooms"), database.Collection("housekeeping_tasks"))
+	hkService := housekeeping.NewService(hkRepository, folioRepository)
+	hkHandler := handler.NewHousekeeping(hkService)
+	franchises.GET("/:id/hotels/:hotelId/rooms/board", hkHandler.GetBoard())
+	franchises.GET("/:id/hotels/:hotelId/rooms/board/stream", hkHandler.StreamBoard())
+	franchises.PUT("/:id/hotels/:hotelId/rooms/:number/status", hkHandler.SetRoomStatus())
+	franchises.PUT("/:id/hotels/:hotelId/rooms/:number/type", hkHandler.SetRoomType())
+	franchises.GET("/:id/hotels/:hotelId/housekeeping/tasks", hkHandler.GetTasks())
+	franchises.POST("/:id/hotels/:hotelId/housekeeping/tasks/:taskId/assign", hkHandler.AssignTask())
+	franchises.POST("/:id/hotels/:hotelId/housekeeping/tasks/:taskId/start", hkHandler.StartTask())
+	franchises.POST("/:id/hotels/:hotelId/housekeeping/tasks/:taskId/complete", hkHandler.CompleteTask())
+	franchises.POST("/:id/hotels/:hotelId/housekeeping/tasks/:taskId/inspect", hkHandler.InspectTask())
+
+	taxRepository := folio.NewTaxRepository(database.Collection("tax_configs"))
+	folioService := folio.NewService(folioRepository, taxRepository, rpService, invoiceService, service, hkService, currencyService)
+	folioHandler := handler.NewFolio(folioService)
+	franchises.POST("/:id/folios", folioHandler.Open())
+	franchises.GET("/:id/folios", folioHandler.GetAll())
+	franchises.POST("/:id/folios/night-audit", folioHandler.NightAudit())
+	franchises.GET("/:id/folios/:folioId", folioHandler.GetByID())
+	franchises.POST("/:id/folios/:folioId/charges", folioHandler.PostCharge())
+	franchises.POST("/:id/folios/:folioId/payments", folioHandler.PostPayment())
+	franchises.POST("/:id/folios/:folioId/checkout", folioHandler.Checkout())
+	franchises.PUT("/:id/hotels/:hotelId/taxes", folioHandler.SaveTaxes())
+	franchises.GET("/:id/hotels/:hotelId/taxes", folioHandler.GetTaxes())
+
+	calendarRepository := calendar.NewRepository(database.Collection("calendar_sources"), database.Collection("calendar_blocks"))
+	calendarService := calendar.NewService(calendarRepository, folioRepository, hkRepository)
+	calHandler := handler.NewCalendar(calendarService)
+	franchises.GET("/:id/hotels/:hotelId/rooms/:number/calendar.ics", calHandler.RoomFeed())
+	franchises.GET("/:id/hotels/:hotelId/room-types/:type/calendar.ics", calHandler.RoomTypeFeed())
+	franchises.POST("/:id/hotels/:hotelId/calendar/sources", calHandler.CreateSource())
+	franchises.GET("/:id/hotels/:hotelId/calendar/sources", calHandler.GetSources())
+	franchises.DELETE("/:id/hotels/:hotelId/calendar/sources/:sourceId", calHandler.DeleteSource())
+	franchises.POST("/:id/hotels/:hotelId/calendar/sources/:sourceId/sync", calHandler.Sync())
+	franchises.GET("/:id/hotels/:hotelId/calendar/conflicts", calHandler.GetConflicts())
+	r.jobs = append(r.jobs, scheduler.New("importar los calendarios", calendarService.SyncAll, interval("CALENDAR_SYNC_INTERVAL", 30*time.Minute)).Run)
+}
+
+// interval lee el intervalo de una tarea periódica de la variable name (por ejemplo
+// "15m"); si falta o es inválido usa def, y cero desactiva la tarea.
+func interval(name string, def time.Duration) time.Duration {
+	env.Duration(name, &def)
+	return def
+}
+
+// franchiseRepository elige dónde se guardan las franquicias con FRANCHISE_STORE: "mongo"
+// (por defecto), "memory", "file" (en FRANCHISE_FILE, por defecto data/franchises.json),
+// "sqlite" o "postgres" (con FRANCHISE_SQL_DSN; en SQLite es la ruta del archivo, por
+// defecto data/franchises.db).
+func franchiseRepository(database *mongo.Database) franquicia.Repository {
+	switch store := os.Getenv("FRANCHISE_STORE"); store {
+	case "", "mongo":
+		if database == nil {
+			log.Fatal("FRANCHISE_STORE=mongo requiere una conexión a MongoDB")
+		}
+	case "sqlite", "postgres":
+		return sqlFranchiseRepository(franquicia.Dialect(store), os.Getenv("FRANCHISE_SQL_DSN"))
+	case "memory":
+		return franquicia.NewMemoryRepository()
+	case "file":
+		path := os.Getenv("FRANCHISE_FILE")
+		if path == "" {
+			path = "data/franchises.json"
+		}
+		repository, err := franquicia.NewFileRepository(path)
+		if err != nil {
+			log.Fatalf("Error al abrir el archivo de franquicias: %v", err)
+		}
+		return repository
+	default:
+		log.Fatalf("FRANCHISE_STORE desconocido %q", store)
+	}
+
+	return franquicia.NewRepository(database.Collection("franchises"))
+}
+
+func sqlFranchiseRepository(dialect franquicia.Dialect, dsn string) franquicia.Repository {
+	var database *sql.DB
+	var err error
+	if dialect == franquicia.DialectSQLite {
+		if dsn == "" {
+			dsn = "data/franchises.db"
+		}
+		database, err = db.OpenSQLite(dsn)
+	} else {
+		database, err = db.OpenPostgres(dsn)
+	}
+	if err != nil {
+		log.Fatalf("Error al abrir la base %s de franquicias: %v", dialect, err)
+	}
+
+	migrationCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	n, err := franquicia.MigrateSQL(migrationCtx, database, dialect)
+	if err != nil {
+		log.Fatalf("Error al migrar el esquema de franquicias: %v", err)
+	}
+	if n > 0 {
+		log.Printf("Migraciones del esquema de franquicias aplicadas: %d", n)
+	}
+	return franquicia.NewSQLRepository(database, dialect)
+}
+
+// mediaStore elige el blob store de imágenes con MEDIA_STORE: "local" (por defecto, en
+// MEDIA_DIR) o "s3" (S3_ENDPOINT, S3_BUCKET, S3_REGION, S3_ACCESS_KEY, S3_SECRET_KEY).
+func mediaStore() media.BlobStore {
+	if os.Getenv("MEDIA_STORE") == "s3" {
+		store, err := media.NewS3Store(media.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+		if err != nil {
+			log.Fatalf("Error al configurar el almacenamiento S3: %v", err)
+		}
+		return store
+	}
+
+	dir := os.Getenv("MEDIA_DIR")
+	if dir == "" {
+		dir = "data/media"
+	}
+	store, err := media.NewLocalStore(dir)
+	if err != nil {
+		log.Fatalf("Error al crear el directorio de imágenes %s: %v", dir, err)
+	}
+	return store
 }
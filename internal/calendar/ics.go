@@ -0,0 +1,275 @@
+package calendar
+
+import (
+	"bufio"
+	"clubhub-hotel-management/internal/domain"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+const icsDateLayout = "20060102"
+
+// icsEvent es un VEVENT reducido a lo que usa la sincronización: fechas de día completo.
+type icsEvent struct {
+	UID     string
+	Summary string
+	Start   string
+	End     string
+}
+
+// vevent es un VEVENT leído con los datos de recurrencia que parseICS expande.
+type vevent struct {
+	icsEvent
+	rrule        string
+	exdates      map[string]bool
+	recurrenceID string
+}
+
+// parseICS lee los VEVENT de un calendario iCalendar (RFC 5545). Las fechas con hora
+// se reducen al día en su zona horaria; sin DTEND el evento dura un día. Los eventos
+// con RRULE se expanden hasta feedHorizonDays después de now, sin las fechas de EXDATE
+// ni las ocurrencias que otro VEVENT reemplaza con RECURRENCE-ID.
+func parseICS(r io.Reader, now time.Time) ([]icsEvent, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []vevent
+	var current *vevent
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &vevent{exdates: map[string]bool{}}
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				continue
+			}
+			if current.Start == "" {
+				return nil, fmt.Errorf("event %q without DTSTART", current.UID)
+			}
+			if current.End == "" || current.End <= current.Start {
+				start, _ := time.Parse(domain.DateLayout, current.Start)
+				current.End = start.AddDate(0, 0, 1).Format(domain.DateLayout)
+			}
+			parsed = append(parsed, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescapeText(value)
+		case name == "DTSTART", name == "DTEND", name == "RECURRENCE-ID":
+			day, err := parseICSDate(value, params["TZID"])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			switch name {
+			case "DTSTART":
+				current.Start = day
+			case "DTEND":
+				current.End = day
+			default:
+				current.recurrenceID = day
+			}
+		case name == "RRULE":
+			current.rrule = value
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				day, err := parseICSDate(v, params["TZID"])
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				current.exdates[day] = true
+			}
+		}
+	}
+
+	// Las ocurrencias reemplazadas no se expanden: el VEVENT con RECURRENCE-ID trae sus fechas.
+	replaced := map[string]bool{}
+	for _, e := range parsed {
+		if e.recurrenceID != "" {
+			replaced[e.UID+"/"+e.recurrenceID] = true
+		}
+	}
+
+	today := startOfDay(now)
+	horizon := today.AddDate(0, 0, feedHorizonDays)
+	var events []icsEvent
+	for _, e := range parsed {
+		switch {
+		case e.recurrenceID != "":
+			events = append(events, occurrence(e.icsEvent, e.recurrenceID, e.Start, e.End))
+		case e.rrule == "":
+			events = append(events, e.icsEvent)
+		default:
+			expanded, err := expand(e, replaced, today, horizon)
+			if err != nil {
+				log.Printf("Evento %q con RRULE no soportada, se importa solo la primera ocurrencia: %v", e.UID, err)
+				expanded = []icsEvent{occurrence(e.icsEvent, e.Start, e.Start, e.End)}
+			}
+			events = append(events, expanded...)
+		}
+	}
+	return events, nil
+}
+
+// expand devuelve las ocurrencias de e que terminan después de today y empiezan antes de horizon.
+func expand(e vevent, replaced map[string]bool, today, horizon time.Time) ([]icsEvent, error) {
+	rule, err := parseRRule(e.rrule)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(domain.DateLayout, e.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(domain.DateLayout, e.End)
+	if err != nil {
+		return nil, err
+	}
+	nights := int(end.Sub(start).Hours() / 24)
+
+	var events []icsEvent
+	rule.each(start, horizon, func(t time.Time) {
+		day := t.Format(domain.DateLayout)
+		last := t.AddDate(0, 0, nights)
+		if e.exdates[day] || replaced[e.UID+"/"+day] || !last.After(today) {
+			return
+		}
+		events = append(events, occurrence(e.icsEvent, day, day, last.Format(domain.DateLayout)))
+	})
+	return events, nil
+}
+
+// occurrence copia e con otras fechas. El UID lleva el día de la ocurrencia para que cada
+// una sea un bloqueo distinto de la misma fuente.
+func occurrence(e icsEvent, day, start, end string) icsEvent {
+	if e.UID != "" {
+		e.UID += "/" + day
+	}
+	e.Start, e.End = start, end
+	return e
+}
+
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func splitProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseICSDate(value, tzid string) (string, error) {
+	if len(value) == len(icsDateLayout) {
+		t, err := time.Parse(icsDateLayout, value)
+		if err != nil {
+			return "", err
+		}
+		return t.Format(domain.DateLayout), nil
+	}
+
+	loc := time.UTC
+	if tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return "", err
+		}
+		return t.Format(domain.DateLayout), nil
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(domain.DateLayout), nil
+}
+
+func unescapeText(s string) string {
+	r := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
+
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// writeICS genera un VCALENDAR con un VEVENT de día completo por evento.
+func writeICS(w io.Writer, name string, events []icsEvent, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	write := func(line string) {
+		// RFC 5545: líneas de hasta 75 octetos, las continuaciones empiezan con espacio.
+		for len(line) > 75 {
+			cut := 75
+			for cut > 0 && !isRuneStart(line[cut]) {
+				cut--
+			}
+			bw.WriteString(line[:cut] + "\r\n")
+			line = " " + line[cut:]
+		}
+		bw.WriteString(line + "\r\n")
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//ClubHub//Hotel Management//ES")
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	write("X-WR-CALNAME:" + escapeText(name))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		start, err := time.Parse(domain.DateLayout, e.Start)
+		if err != nil {
+			return err
+		}
+		end, err := time.Parse(domain.DateLayout, e.End)
+		if err != nil {
+			return err
+		}
+		write("BEGIN:VEVENT")
+		write("UID:" + e.UID)
+		write("DTSTAMP:" + stamp)
+		write("DTSTART;VALUE=DATE:" + start.Format(icsDateLayout))
+		write("DTEND;VALUE=DATE:" + end.Format(icsDateLayout))
+		write("SUMMARY:" + escapeText(e.Summary))
+		write("TRANSP:OPAQUE")
+		write("END:VEVENT")
+	}
+	write("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
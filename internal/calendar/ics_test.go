@@ -0,0 +1,124 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vcalendar arma un calendario con un VEVENT por cada grupo de propiedades.
+func vcalendar(events ...string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n")
+	for _, e := range events {
+		b.WriteString("BEGIN:VEVENT\r\n" + strings.ReplaceAll(strings.TrimSpace(e), "\n", "\r\n") + "\r\nEND:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
+
+func TestParseICS(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		events []string
+		want   []icsEvent
+	}{
+		{
+			name: "single event",
+			events: []string{`UID:a
+SUMMARY:Airbnb (Not available)
+DTSTART;VALUE=DATE:20260110
+DTEND;VALUE=DATE:20260113`},
+			want: []icsEvent{{UID: "a", Summary: "Airbnb (Not available)", Start: "2026-01-10", End: "2026-01-13"}},
+		},
+		{
+			name: "daily count",
+			events: []string{`UID:a
+DTSTART;VALUE=DATE:20260105
+RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3`},
+			want: []icsEvent{
+				{UID: "a/2026-01-05", Start: "2026-01-05", End: "2026-01-06"},
+				{UID: "a/2026-01-07", Start: "2026-01-07", End: "2026-01-08"},
+				{UID: "a/2026-01-09", Start: "2026-01-09", End: "2026-01-10"},
+			},
+		},
+		{
+			// 2026-01-05 es lunes; las ocurrencias ya terminadas no se devuelven.
+			name: "weekly by day until",
+			events: []string{`UID:a
+DTSTART;VALUE=DATE:20251229
+DTEND;VALUE=DATE:20251231
+RRULE:FREQ=WEEKLY;BYDAY=FR,MO;UNTIL=20260109`},
+			want: []icsEvent{
+				{UID: "a/2026-01-02", Start: "2026-01-02", End: "2026-01-04"},
+				{UID: "a/2026-01-05", Start: "2026-01-05", End: "2026-01-07"},
+				{UID: "a/2026-01-09", Start: "2026-01-09", End: "2026-01-11"},
+			},
+		},
+		{
+			name: "monthly skips missing days",
+			events: []string{`UID:a
+DTSTART;VALUE=DATE:20260131
+RRULE:FREQ=MONTHLY;COUNT=3`},
+			want: []icsEvent{
+				{UID: "a/2026-01-31", Start: "2026-01-31", End: "2026-02-01"},
+				{UID: "a/2026-03-31", Start: "2026-03-31", End: "2026-04-01"},
+				{UID: "a/2026-05-31", Start: "2026-05-31", End: "2026-06-01"},
+			},
+		},
+		{
+			name: "exdate and recurrence id",
+			events: []string{`UID:a
+DTSTART;VALUE=DATE:20260105
+RRULE:FREQ=WEEKLY;COUNT=3
+EXDATE;VALUE=DATE:20260112`, `UID:a
+RECURRENCE-ID;VALUE=DATE:20260119
+DTSTART;VALUE=DATE:20260120
+DTEND;VALUE=DATE:20260122`},
+			want: []icsEvent{
+				{UID: "a/2026-01-05", Start: "2026-01-05", End: "2026-01-06"},
+				{UID: "a/2026-01-19", Start: "2026-01-20", End: "2026-01-22"},
+			},
+		},
+		{
+			name: "stops at the horizon",
+			events: []string{`UID:a
+DTSTART;VALUE=DATE:20260101
+RRULE:FREQ=YEARLY`},
+			want: []icsEvent{
+				{UID: "a/2026-01-01", Start: "2026-01-01", End: "2026-01-02"},
+				{UID: "a/2027-01-01", Start: "2027-01-01", End: "2027-01-02"},
+			},
+		},
+		{
+			name: "unsupported rule keeps the first occurrence",
+			events: []string{`UID:a
+DTSTART;VALUE=DATE:20260105
+RRULE:FREQ=MONTHLY;BYDAY=1MO`},
+			want: []icsEvent{{UID: "a/2026-01-05", Start: "2026-01-05", End: "2026-01-06"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseICS(strings.NewReader(vcalendar(tt.events...)), now)
+			if err != nil {
+				t.Fatalf("parseICS: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseICS =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRRuleRejects(t *testing.T) {
+	for _, value := range []string{"FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=MONTHLY;BYMONTHDAY=1", "FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;BYDAY=1MO"} {
+		if _, err := parseRRule(value); err == nil {
+			t.Errorf("parseRRule(%q): want error", value)
+		}
+	}
+}
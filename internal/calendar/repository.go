@@ -0,0 +1,143 @@
+package calendar
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrSourceNotFound = errors.New("calendar source not found")
+
+type Repository interface {
+	CreateSource(ctx context.Context, src *domain.CalendarSource) error
+	UpdateSource(ctx context.Context, src domain.CalendarSource) error
+	DeleteSource(ctx context.Context, id primitive.ObjectID) error
+	GetSource(ctx context.Context, id primitive.ObjectID) (domain.CalendarSource, error)
+	GetSources(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.CalendarSource, error)
+	GetAllSources(ctx context.Context) ([]domain.CalendarSource, error)
+
+	ReplaceBlocks(ctx context.Context, sourceID primitive.ObjectID, blocks []domain.CalendarBlock) error
+	GetBlocks(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string) ([]domain.CalendarBlock, error)
+}
+
+type repository struct {
+	sources *mongo.Collection
+	blocks  *mongo.Collection
+}
+
+func NewRepository(sources, blocks *mongo.Collection) Repository {
+	return &repository{
+		sources: sources,
+		blocks:  blocks,
+	}
+}
+
+func (r *repository) CreateSource(ctx context.Context, src *domain.CalendarSource) error {
+	_, err := r.sources.InsertOne(ctx, src)
+	return err
+}
+
+func (r *repository) UpdateSource(ctx context.Context, src domain.CalendarSource) error {
+	res, err := r.sources.ReplaceOne(ctx, bson.M{"_id": src.ID}, src)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrSourceNotFound
+	}
+	return nil
+}
+
+// DeleteSource elimina el calendario y los bloqueos que había importado.
+func (r *repository) DeleteSource(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.sources.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrSourceNotFound
+	}
+	_, err = r.blocks.DeleteMany(ctx, bson.M{"source_id": id})
+	return err
+}
+
+func (r *repository) GetSource(ctx context.Context, id primitive.ObjectID) (domain.CalendarSource, error) {
+	var src domain.CalendarSource
+	err := r.sources.FindOne(ctx, bson.M{"_id": id}).Decode(&src)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return src, ErrSourceNotFound
+	}
+	return src, err
+}
+
+func (r *repository) GetSources(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.CalendarSource, error) {
+	return r.findSources(ctx, bson.M{"franquicia_id": franquiciaID, "hotel_id": hotelID})
+}
+
+func (r *repository) GetAllSources(ctx context.Context) ([]domain.CalendarSource, error) {
+	return r.findSources(ctx, bson.M{})
+}
+
+func (r *repository) findSources(ctx context.Context, filter bson.M) ([]domain.CalendarSource, error) {
+	var sources []domain.CalendarSource
+	cursor, err := r.sources.Find(ctx, filter, options.Find().SetSort(bson.M{"room_number": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var src domain.CalendarSource
+		if err := cursor.Decode(&src); err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+
+	return sources, nil
+}
+
+// ReplaceBlocks reemplaza todos los bloqueos de un calendario por los de la última importación.
+func (r *repository) ReplaceBlocks(ctx context.Context, sourceID primitive.ObjectID, blocks []domain.CalendarBlock) error {
+	if _, err := r.blocks.DeleteMany(ctx, bson.M{"source_id": sourceID}); err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(blocks))
+	for i := range blocks {
+		docs[i] = blocks[i]
+	}
+	_, err := r.blocks.InsertMany(ctx, docs)
+	return err
+}
+
+func (r *repository) GetBlocks(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string) ([]domain.CalendarBlock, error) {
+	var blocks []domain.CalendarBlock
+	filter := bson.M{"franquicia_id": franquiciaID, "hotel_id": hotelID}
+	if roomNumber != "" {
+		filter["room_number"] = roomNumber
+	}
+	cursor, err := r.blocks.Find(ctx, filter, options.Find().SetSort(bson.M{"start": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var b domain.CalendarBlock
+		if err := cursor.Decode(&b); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+
+	return blocks, nil
+}
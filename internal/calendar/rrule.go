@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errUnsupportedRule = errors.New("unsupported RRULE")
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// rrule es el subconjunto de RRULE (RFC 5545 §3.3.10) que publican los calendarios de
+// reservas: FREQ, INTERVAL, COUNT, UNTIL y, en reglas semanales, BYDAY y WKST.
+type rrule struct {
+	freq      string
+	interval  int
+	count     int
+	until     string
+	weekdays  []time.Weekday
+	weekStart time.Weekday
+}
+
+func parseRRule(value string) (rrule, error) {
+	r := rrule{interval: 1, weekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		key, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL", "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("%w: %s", errUnsupportedRule, part)
+			}
+			if strings.EqualFold(key, "INTERVAL") {
+				r.interval = n
+			} else {
+				r.count = n
+			}
+		case "UNTIL":
+			day, err := parseICSDate(v, "")
+			if err != nil {
+				return r, fmt.Errorf("%w: %s", errUnsupportedRule, part)
+			}
+			r.until = day
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := icsWeekdays[strings.ToUpper(d)]
+				if !ok {
+					return r, fmt.Errorf("%w: %s", errUnsupportedRule, part)
+				}
+				r.weekdays = append(r.weekdays, wd)
+			}
+		case "WKST":
+			wd, ok := icsWeekdays[strings.ToUpper(v)]
+			if !ok {
+				return r, fmt.Errorf("%w: %s", errUnsupportedRule, part)
+			}
+			r.weekStart = wd
+		default:
+			return r, fmt.Errorf("%w: %s", errUnsupportedRule, part)
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return r, fmt.Errorf("%w: FREQ=%s", errUnsupportedRule, r.freq)
+	}
+	if len(r.weekdays) > 0 && r.freq != "WEEKLY" {
+		return r, fmt.Errorf("%w: BYDAY with FREQ=%s", errUnsupportedRule, r.freq)
+	}
+	return r, nil
+}
+
+// each llama a fn con el inicio de cada ocurrencia en orden, desde start (que siempre es la
+// primera) hasta COUNT, UNTIL o horizon. Las fechas que no existen, como el 31 en un mes de
+// 30 días, se saltean como indica el RFC.
+func (r rrule) each(start, horizon time.Time, fn func(time.Time)) {
+	n := 0
+	emit := func(t time.Time) bool {
+		if !t.Before(horizon) || (r.count > 0 && n >= r.count) || (r.until != "" && t.Format(domain.DateLayout) > r.until) {
+			return false
+		}
+		n++
+		fn(t)
+		return true
+	}
+	if !emit(start) {
+		return
+	}
+
+	if r.freq == "WEEKLY" && len(r.weekdays) > 0 {
+		offsets := r.weekOffsets()
+		week := start.AddDate(0, 0, -int((start.Weekday()-r.weekStart+7)%7))
+		for ; ; week = week.AddDate(0, 0, 7*r.interval) {
+			for _, offset := range offsets {
+				t := week.AddDate(0, 0, offset)
+				if !t.After(start) {
+					continue
+				}
+				if !emit(t) {
+					return
+				}
+			}
+		}
+	}
+
+	for k := 1; ; k++ {
+		var t time.Time
+		switch r.freq {
+		case "DAILY":
+			t = start.AddDate(0, 0, k*r.interval)
+		case "WEEKLY":
+			t = start.AddDate(0, 0, 7*k*r.interval)
+		case "MONTHLY":
+			t = start.AddDate(0, k*r.interval, 0)
+		case "YEARLY":
+			t = start.AddDate(k*r.interval, 0, 0)
+		}
+		if (r.freq == "MONTHLY" || r.freq == "YEARLY") && t.Before(horizon) && t.Day() != start.Day() {
+			continue
+		}
+		if !emit(t) {
+			return
+		}
+	}
+}
+
+// weekOffsets devuelve los días de BYDAY como distancia al inicio de la semana, ordenados.
+func (r rrule) weekOffsets() []int {
+	seen := map[int]bool{}
+	var offsets []int
+	for _, wd := range r.weekdays {
+		offset := int((wd - r.weekStart + 7) % 7)
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Ints(offsets)
+	return offsets
+}
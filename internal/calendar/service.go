@@ -0,0 +1,439 @@
+package calendar
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/folio"
+	"clubhub-hotel-management/internal/housekeeping"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidSource    = errors.New("invalid calendar source")
+	ErrFetchFailed      = errors.New("calendar import failed")
+	ErrRoomNotFound     = errors.New("room not found")
+	ErrRoomTypeNotFound = errors.New("room type not found")
+)
+
+const (
+	maxCalendarSize = 5 << 20
+	// feedHorizonDays limita hasta dónde se calculan las fechas bloqueadas por tipo de habitación.
+	feedHorizonDays = 730
+)
+
+// Service publica los feeds iCal de disponibilidad e importa calendarios externos.
+// Recibe context.Context porque la importación también corre desde el scheduler,
+// fuera de una petición HTTP.
+type Service interface {
+	RoomFeed(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string) ([]byte, error)
+	RoomTypeFeed(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomType string) ([]byte, error)
+
+	CreateSource(ctx context.Context, src *domain.CalendarSource) error
+	GetSources(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.CalendarSource, error)
+	DeleteSource(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) error
+	Sync(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (*domain.CalendarSyncResult, error)
+	SyncAll(ctx context.Context) error
+	GetConflicts(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.CalendarConflict, error)
+}
+
+type service struct {
+	repo   Repository
+	folios folio.Repository
+	rooms  housekeeping.Repository
+	client *http.Client
+}
+
+// NewService crea un nuevo servicio de calendarios.
+func NewService(r Repository, folios folio.Repository, rooms housekeeping.Repository) Service {
+	return &service{
+		repo:   r,
+		folios: folios,
+		rooms:  rooms,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// stay es un rango ocupado de una habitación: una estadía interna o un bloqueo importado.
+type stay struct {
+	room  string
+	start string
+	end   string
+	event icsEvent
+}
+
+// RoomFeed genera el .ics de una habitación con sus estadías y los bloqueos importados.
+func (s *service) RoomFeed(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string) ([]byte, error) {
+	if _, err := s.rooms.GetRoom(ctx, franquiciaID, hotelID, roomNumber); err != nil {
+		if errors.Is(err, housekeeping.ErrRoomNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrRoomNotFound, roomNumber)
+		}
+		return nil, err
+	}
+
+	stays, err := s.stays(ctx, franquiciaID, hotelID, roomNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]icsEvent, 0, len(stays))
+	for _, st := range stays {
+		events = append(events, st.event)
+	}
+
+	var buf bytes.Buffer
+	name := fmt.Sprintf("%s - %s", hotelID, roomNumber)
+	if err := writeICS(&buf, name, events, time.Now()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RoomTypeFeed genera el .ics de un tipo de habitación: una fecha se bloquea cuando
+// todas las habitaciones de ese tipo están ocupadas.
+func (s *service) RoomTypeFeed(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomType string) ([]byte, error) {
+	rooms, err := s.rooms.GetRooms(ctx, franquiciaID, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []string
+	for _, room := range rooms {
+		if strings.EqualFold(room.Type, roomType) {
+			numbers = append(numbers, room.Number)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRoomTypeNotFound, roomType)
+	}
+
+	stays, err := s.stays(ctx, franquiciaID, hotelID, "")
+	if err != nil {
+		return nil, err
+	}
+	byRoom := map[string][]stay{}
+	for _, st := range stays {
+		byRoom[st.room] = append(byRoom[st.room], st)
+	}
+
+	var events []icsEvent
+	var current *icsEvent
+	today := startOfDay(time.Now())
+	for i := 0; i < feedHorizonDays; i++ {
+		day := today.AddDate(0, 0, i).Format(domain.DateLayout)
+		blocked := true
+		for _, number := range numbers {
+			if !occupied(byRoom[number], day) {
+				blocked = false
+				break
+			}
+		}
+
+		switch {
+		case blocked && current == nil:
+			current = &icsEvent{
+				UID:     fmt.Sprintf("%s-%s-%s@clubhub", hotelID, roomType, day),
+				Summary: "No disponible",
+				Start:   day,
+			}
+		case !blocked && current != nil:
+			current.End = day
+			events = append(events, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		current.End = today.AddDate(0, 0, feedHorizonDays).Format(domain.DateLayout)
+		events = append(events, *current)
+	}
+
+	var buf bytes.Buffer
+	name := fmt.Sprintf("%s - %s", hotelID, roomType)
+	if err := writeICS(&buf, name, events, time.Now()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *service) CreateSource(ctx context.Context, src *domain.CalendarSource) error {
+	src.RoomNumber = strings.TrimSpace(src.RoomNumber)
+	if src.RoomNumber == "" {
+		return fmt.Errorf("%w: room_number is required", ErrInvalidSource)
+	}
+	normalized, err := normalizeURL(src.URL)
+	if err != nil {
+		return err
+	}
+	src.URL = normalized
+	if src.Name == "" {
+		src.Name = src.URL
+	}
+
+	src.ID = primitive.NewObjectID()
+	return s.repo.CreateSource(ctx, src)
+}
+
+func (s *service) GetSources(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.CalendarSource, error) {
+	return s.repo.GetSources(ctx, franquiciaID, hotelID)
+}
+
+func (s *service) DeleteSource(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) error {
+	if _, err := s.getSource(ctx, franquiciaID, hotelID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteSource(ctx, id)
+}
+
+func (s *service) Sync(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (*domain.CalendarSyncResult, error) {
+	src, err := s.getSource(ctx, franquiciaID, hotelID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.sync(ctx, src)
+}
+
+// SyncAll importa todos los calendarios registrados. Un calendario que falla no
+// detiene al resto; el error queda guardado en la fuente.
+func (s *service) SyncAll(ctx context.Context) error {
+	sources, err := s.repo.GetAllSources(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, src := range sources {
+		result, err := s.sync(ctx, src)
+		if err != nil {
+			log.Printf("Error al importar el calendario %s: %v", src.ID.Hex(), err)
+			continue
+		}
+		for _, c := range result.Conflicts {
+			log.Printf("Conflicto de calendario: %s habitación %s bloqueada por %q del %s al %s, folio %s",
+				src.HotelID, src.RoomNumber, src.Name, c.Block.Start, c.Block.End, c.FolioID.Hex())
+		}
+	}
+	return nil
+}
+
+func (s *service) GetConflicts(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.CalendarConflict, error) {
+	blocks, err := s.repo.GetBlocks(ctx, franquiciaID, hotelID, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.conflicts(ctx, franquiciaID, hotelID, blocks)
+}
+
+func (s *service) getSource(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (domain.CalendarSource, error) {
+	src, err := s.repo.GetSource(ctx, id)
+	if err != nil {
+		return src, err
+	}
+	if src.FranquiciaID != franquiciaID || src.HotelID != hotelID {
+		return src, ErrSourceNotFound
+	}
+	return src, nil
+}
+
+func (s *service) sync(ctx context.Context, src domain.CalendarSource) (*domain.CalendarSyncResult, error) {
+	now := time.Now().UTC()
+	src.LastSyncAt = &now
+
+	events, err := s.fetch(ctx, src.URL)
+	if err != nil {
+		src.LastError = err.Error()
+		if uerr := s.repo.UpdateSource(ctx, src); uerr != nil {
+			log.Printf("Error al actualizar el calendario %s: %v", src.ID.Hex(), uerr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	// Solo interesan los bloqueos que todavía no terminaron.
+	today := startOfDay(now).Format(domain.DateLayout)
+	blocks := make([]domain.CalendarBlock, 0, len(events))
+	for _, e := range events {
+		if e.End <= today {
+			continue
+		}
+		uid := e.UID
+		if uid == "" {
+			uid = e.Start + "/" + e.End
+		}
+		blocks = append(blocks, domain.CalendarBlock{
+			ID:           primitive.NewObjectID(),
+			SourceID:     src.ID,
+			FranquiciaID: src.FranquiciaID,
+			HotelID:      src.HotelID,
+			RoomNumber:   src.RoomNumber,
+			UID:          uid,
+			Summary:      e.Summary,
+			Start:        e.Start,
+			End:          e.End,
+			ImportedAt:   now,
+		})
+	}
+	if err := s.repo.ReplaceBlocks(ctx, src.ID, blocks); err != nil {
+		return nil, err
+	}
+
+	src.LastError = ""
+	src.BlockCount = len(blocks)
+	if err := s.repo.UpdateSource(ctx, src); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := s.conflicts(ctx, src.FranquiciaID, src.HotelID, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CalendarSyncResult{
+		Source:    src,
+		Blocks:    len(blocks),
+		Conflicts: conflicts,
+	}, nil
+}
+
+func (s *service) fetch(ctx context.Context, rawURL string) ([]icsEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return parseICS(io.LimitReader(resp.Body, maxCalendarSize), time.Now())
+}
+
+// conflicts cruza los bloqueos importados con las estadías internas de la misma habitación.
+func (s *service) conflicts(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, blocks []domain.CalendarBlock) ([]domain.CalendarConflict, error) {
+	conflicts := []domain.CalendarConflict{}
+	if len(blocks) == 0 {
+		return conflicts, nil
+	}
+
+	folios, err := s.folios.GetByHotel(ctx, franquiciaID, hotelID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	today := startOfDay(time.Now()).Format(domain.DateLayout)
+	for _, b := range blocks {
+		if b.End <= today {
+			continue
+		}
+		for _, f := range folios {
+			end := stayEnd(f)
+			if f.RoomNumber != b.RoomNumber || end <= f.CheckIn || !overlaps(b.Start, b.End, f.CheckIn, end) {
+				continue
+			}
+			conflicts = append(conflicts, domain.CalendarConflict{
+				Block:         b,
+				FolioID:       f.ID,
+				ReservationID: f.ReservationID,
+				CheckIn:       f.CheckIn,
+				CheckOut:      end,
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// stays devuelve las ocupaciones vigentes del hotel; con roomNumber vacío, de todas las habitaciones.
+func (s *service) stays(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string) ([]stay, error) {
+	folios, err := s.folios.GetByHotel(ctx, franquiciaID, hotelID, "")
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.repo.GetBlocks(ctx, franquiciaID, hotelID, roomNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	today := startOfDay(time.Now()).Format(domain.DateLayout)
+	var stays []stay
+	for _, f := range folios {
+		end := stayEnd(f)
+		if end <= today || end <= f.CheckIn || (roomNumber != "" && f.RoomNumber != roomNumber) {
+			continue
+		}
+		stays = append(stays, stay{
+			room:  f.RoomNumber,
+			start: f.CheckIn,
+			end:   end,
+			event: icsEvent{UID: f.ID.Hex() + "@clubhub", Summary: "Reservado", Start: f.CheckIn, End: end},
+		})
+	}
+	for _, b := range blocks {
+		if b.End <= today {
+			continue
+		}
+		stays = append(stays, stay{
+			room:  b.RoomNumber,
+			start: b.Start,
+			end:   b.End,
+			event: icsEvent{UID: b.SourceID.Hex() + "-" + b.UID, Summary: "Bloqueado", Start: b.Start, End: b.End},
+		})
+	}
+	return stays, nil
+}
+
+// stayEnd es el día en que se libera la habitación: el check-out previsto o, si el folio se
+// cerró antes (salida anticipada), el día del cierre.
+func stayEnd(f domain.Folio) string {
+	if f.Status == domain.FolioClosed && f.ClosedAt != nil {
+		if closed := f.ClosedAt.UTC().Format(domain.DateLayout); closed < f.CheckOut {
+			return closed
+		}
+	}
+	return f.CheckOut
+}
+
+func occupied(stays []stay, day string) bool {
+	for _, st := range stays {
+		if st.start <= day && day < st.end {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps compara rangos [start, end) con fechas YYYY-MM-DD.
+func overlaps(aStart, aEnd, bStart, bEnd string) bool {
+	return aStart < bEnd && bStart < aEnd
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// normalizeURL acepta http, https y webcal (que se descarga por https).
+func normalizeURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("%w: invalid url %q", ErrInvalidSource, raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+	case "webcal":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("%w: unsupported scheme %q", ErrInvalidSource, u.Scheme)
+	}
+	return u.String(), nil
+}
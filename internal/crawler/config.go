@@ -0,0 +1,64 @@
+package crawler
+
+import (
+	"clubhub-hotel-management/internal/env"
+	"os"
+	"time"
+)
+
+// DefaultUserAgent identifica al crawler ante los sitios de las franquicias.
+const DefaultUserAgent = "ClubHubBot/1.0 (+https://github.com/nictes1/ClubHub-Hotel-Management-Go)"
+
+// Config define cómo se comportan todas las visitas a sitios externos.
+type Config struct {
+	UserAgent string
+	// RequestTimeout limita cada petición y Timeout el total de una visita, reintentos incluidos.
+	RequestTimeout time.Duration
+	Timeout        time.Duration
+	MaxBodySize    int
+	// Delay es la pausa mínima entre peticiones al mismo dominio y Parallelism cuántas
+	// peticiones simultáneas se le hacen.
+	Delay       time.Duration
+	Parallelism int
+	// IgnoreRobots desactiva el respeto de robots.txt; solo para pruebas locales.
+	IgnoreRobots bool
+	Retries      int
+	RetryBackoff time.Duration
+	// CacheDir guarda las respuestas en disco para no repetir peticiones en desarrollo.
+	CacheDir string
+	// InsecureTLS acepta certificados inválidos; por defecto se verifican.
+	InsecureTLS bool
+}
+
+// DefaultConfig es una configuración conservadora para sitios de terceros.
+func DefaultConfig() Config {
+	return Config{
+		UserAgent:      DefaultUserAgent,
+		RequestTimeout: 10 * time.Second,
+		Timeout:        30 * time.Second,
+		MaxBodySize:    5 << 20,
+		Delay:          500 * time.Millisecond,
+		Parallelism:    2,
+		Retries:        2,
+		RetryBackoff:   time.Second,
+	}
+}
+
+// ConfigFromEnv parte de DefaultConfig y aplica las variables CRAWLER_*.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if v := os.Getenv("CRAWLER_USER_AGENT"); v != "" {
+		cfg.UserAgent = v
+	}
+	env.Duration("CRAWLER_REQUEST_TIMEOUT", &cfg.RequestTimeout)
+	env.Duration("CRAWLER_TIMEOUT", &cfg.Timeout)
+	env.Int("CRAWLER_MAX_BODY_SIZE", &cfg.MaxBodySize)
+	env.Duration("CRAWLER_DELAY", &cfg.Delay)
+	env.Int("CRAWLER_PARALLELISM", &cfg.Parallelism)
+	env.Int("CRAWLER_RETRIES", &cfg.Retries)
+	env.Duration("CRAWLER_RETRY_BACKOFF", &cfg.RetryBackoff)
+	cfg.IgnoreRobots = os.Getenv("CRAWLER_IGNORE_ROBOTS") == "true"
+	cfg.InsecureTLS = os.Getenv("CRAWLER_INSECURE_TLS") == "true"
+	cfg.CacheDir = os.Getenv("CRAWLER_CACHE_DIR")
+	return cfg
+}
@@ -0,0 +1,123 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Factory crea los collectors y hace las descargas de todas las funciones de scraping,
+// para que compartan user agent, plazos, límites por dominio, robots.txt y reintentos.
+type Factory interface {
+	// NewCollector devuelve un collector de una sola página cuyas peticiones terminan
+	// cuando se cancela ctx o vence el plazo total de la configuración.
+	NewCollector(ctx context.Context) *colly.Collector
+	// Fetch descarga un recurso (imagen, manifest, ...) con las mismas políticas.
+	Fetch(ctx context.Context, url string) (*Response, error)
+	// NewProbeClient devuelve un cliente para medir tiempos de respuesta: respeta el user
+	// agent, robots.txt y el límite por dominio pero no reutiliza conexiones, no reintenta
+	// ni descomprime las respuestas.
+	NewProbeClient() *http.Client
+}
+
+// Response es el resultado de Fetch.
+type Response struct {
+	URL         string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+type factory struct {
+	cfg      Config
+	base     http.RoundTripper
+	robots   *robotsCache
+	mu       sync.Mutex
+	limiters map[string]*hostLimiter
+}
+
+// NewFactory crea la fábrica de collectors; los campos vacíos de cfg toman los valores
+// de DefaultConfig.
+func NewFactory(cfg Config) Factory {
+	defaults := DefaultConfig()
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = defaults.UserAgent
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.RequestTimeout <= 0 || cfg.RequestTimeout > cfg.Timeout {
+		cfg.RequestTimeout = cfg.Timeout
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaults.MaxBodySize
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.InsecureTLS {
+		base.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &factory{
+		cfg:      cfg,
+		base:     base,
+		robots:   newRobotsCache(&http.Client{Transport: base, Timeout: cfg.RequestTimeout}, cfg.UserAgent),
+		limiters: map[string]*hostLimiter{},
+	}
+}
+
+func (f *factory) NewCollector(ctx context.Context) *colly.Collector {
+	options := []colly.CollectorOption{
+		colly.MaxDepth(1),
+		colly.UserAgent(f.cfg.UserAgent),
+		colly.MaxBodySize(f.cfg.MaxBodySize),
+	}
+	if f.cfg.CacheDir != "" {
+		options = append(options, colly.CacheDir(f.cfg.CacheDir))
+	}
+	c := colly.NewCollector(options...)
+	// robots.txt lo resuelve el transport con un cache compartido.
+	c.IgnoreRobotsTxt = true
+	c.SetRequestTimeout(f.cfg.Timeout)
+	c.WithTransport(&transport{
+		factory:  f,
+		ctx:      ctx,
+		deadline: time.Now().Add(f.cfg.Timeout),
+	})
+	return c
+}
+
+func (f *factory) Fetch(ctx context.Context, url string) (*Response, error) {
+	var resp *Response
+	c := f.NewCollector(ctx)
+	c.OnResponse(func(r *colly.Response) {
+		resp = &Response{
+			URL:         r.Request.URL.String(),
+			StatusCode:  r.StatusCode,
+			ContentType: r.Headers.Get("Content-Type"),
+			Body:        r.Body,
+		}
+	})
+	if err := c.Visit(url); err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", url, err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("fetch %s: empty response", url)
+	}
+	return resp, nil
+}
+
+func (f *factory) limiter(host string) *hostLimiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.limiters[host]
+	if !ok {
+		l = newHostLimiter(f.cfg.Parallelism, f.cfg.Delay)
+		f.limiters[host] = l
+	}
+	return l
+}
@@ -0,0 +1,53 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+)
+
+func (f *factory) NewProbeClient() *http.Client {
+	base := f.base.(*http.Transport).Clone()
+	base.DisableKeepAlives = true
+	base.DisableCompression = true
+	return &http.Client{
+		Transport: &probeTransport{factory: f, base: base},
+		Timeout:   f.cfg.Timeout,
+	}
+}
+
+// probeTransport aplica solo las políticas que no alteran la medición: robots.txt y el
+// límite por dominio, sin reintentos.
+type probeTransport struct {
+	factory *factory
+	base    http.RoundTripper
+}
+
+func (t *probeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.factory
+	if !f.cfg.IgnoreRobots {
+		// robots.txt se lee con un contexto propio para que no dispare el httptrace de la
+		// medición, pero se cancela junto con la petición.
+		ctx, cancel := context.WithTimeout(context.Background(), f.cfg.RequestTimeout)
+		stop := context.AfterFunc(req.Context(), cancel)
+		allowed := f.robots.allowed(ctx, req.URL)
+		stop()
+		cancel()
+		if !allowed {
+			return nil, ErrDisallowedByRobots
+		}
+	}
+
+	release, err := f.limiter(req.URL.Host).wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", f.cfg.UserAgent)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+const robotsTTL = time.Hour
+
+// robotsCache guarda el robots.txt de cada sitio por una hora y lo comparte entre todas
+// las visitas.
+type robotsCache struct {
+	client    *http.Client
+	userAgent string
+	mu        sync.Mutex
+	entries   map[string]robotsEntry
+}
+
+type robotsEntry struct {
+	data      *robotstxt.RobotsData
+	fetchedAt time.Time
+}
+
+func newRobotsCache(client *http.Client, userAgent string) *robotsCache {
+	return &robotsCache{
+		client:    client,
+		userAgent: userAgent,
+		entries:   map[string]robotsEntry{},
+	}
+}
+
+// allowed indica si el user agent puede visitar u. Si robots.txt no se puede leer por un
+// error de red se permite la visita; las respuestas 4xx/5xx se interpretan según la RFC 9309.
+func (c *robotsCache) allowed(ctx context.Context, u *url.URL) bool {
+	if u.Path == "/robots.txt" {
+		return true
+	}
+	data := c.get(ctx, u.Scheme+"://"+u.Host)
+	if data == nil {
+		return true
+	}
+	path := u.EscapedPath()
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return data.TestAgent(path, c.userAgent)
+}
+
+func (c *robotsCache) get(ctx context.Context, origin string) *robotstxt.RobotsData {
+	c.mu.Lock()
+	entry, ok := c.entries[origin]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < robotsTTL {
+		return entry.data
+	}
+
+	data, err := c.fetch(ctx, origin)
+	if err != nil {
+		// No se guarda: se vuelve a intentar en la próxima visita.
+		log.Printf("No se pudo leer robots.txt de %s: %v", origin, err)
+		return nil
+	}
+	c.mu.Lock()
+	c.entries[origin] = robotsEntry{data: data, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return data
+}
+
+func (c *robotsCache) fetch(ctx context.Context, origin string) (*robotstxt.RobotsData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	resp.Body = io.NopCloser(io.LimitReader(resp.Body, 512<<10))
+	return robotstxt.FromResponse(resp)
+}
@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// maxRetryAfter acota la espera pedida por un 429/503 con Retry-After.
+const maxRetryAfter = 10 * time.Second
+
+// transport aplica a cada petición las políticas compartidas: robots.txt, límite por
+// dominio, reintentos y el plazo total de la visita.
+type transport struct {
+	factory  *factory
+	ctx      context.Context
+	deadline time.Time
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.factory
+	ctx, cancel := context.WithDeadline(t.ctx, t.deadline)
+
+	if !f.cfg.IgnoreRobots && !f.robots.allowed(ctx, req.URL) {
+		cancel()
+		return nil, ErrDisallowedByRobots
+	}
+
+	retries := f.cfg.Retries
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		release, err := f.limiter(req.URL.Host).wait(ctx)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		attemptCtx, attemptCancel := context.WithTimeout(ctx, f.cfg.RequestTimeout)
+		resp, err := f.base.RoundTrip(req.Clone(attemptCtx))
+		if attempt < retries && retryable(resp, err) && ctx.Err() == nil {
+			wait := backoff(f.cfg.RetryBackoff, attempt, resp)
+			if resp != nil {
+				io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+				resp.Body.Close()
+			}
+			attemptCancel()
+			release()
+			if !sleep(ctx, wait) {
+				cancel()
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		if err != nil {
+			attemptCancel()
+			release()
+			cancel()
+			return nil, err
+		}
+		// El límite y los contextos se liberan recién cuando se terminó de leer la respuesta.
+		resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() {
+			attemptCancel()
+			release()
+			cancel()
+		}}
+		return resp, nil
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// Incluye el vencimiento de un intento; el del plazo total se revisa aparte.
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff duplica la espera en cada intento, salvo que el servidor pida otra con Retry-After.
+func backoff(base time.Duration, attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, maxRetryAfter)
+		}
+	}
+	return base << attempt
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// hostLimiter limita las peticiones simultáneas a un dominio y espaciadas por delay.
+type hostLimiter struct {
+	slots chan struct{}
+	delay time.Duration
+	mu    sync.Mutex
+	next  time.Time
+}
+
+func newHostLimiter(parallelism int, delay time.Duration) *hostLimiter {
+	return &hostLimiter{
+		slots: make(chan struct{}, max(parallelism, 1)),
+		delay: delay,
+	}
+}
+
+// wait bloquea hasta que se puede hacer la próxima petición al dominio y devuelve la
+// función que libera el lugar.
+func (l *hostLimiter) wait(ctx context.Context) (func(), error) {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if l.next.After(now) {
+		start = l.next
+	}
+	l.next = start.Add(l.delay)
+	l.mu.Unlock()
+
+	release := func() { <-l.slots }
+	if !sleep(ctx, start.Sub(now)) {
+		release()
+		return nil, ctx.Err()
+	}
+	return release, nil
+}
@@ -0,0 +1,66 @@
+package currency
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+type Repository interface {
+	Create(ctx context.Context, t *domain.ExchangeRateTable) error
+	GetAll(ctx context.Context) ([]domain.ExchangeRateTable, error)
+	GetEffective(ctx context.Context, date string) (domain.ExchangeRateTable, error)
+}
+
+type repository struct {
+	db *mongo.Collection
+}
+
+func NewRepository(db *mongo.Collection) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, t *domain.ExchangeRateTable) error {
+	_, err := r.db.InsertOne(ctx, t)
+	return err
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.ExchangeRateTable, error) {
+	var tables []domain.ExchangeRateTable
+	opts := options.Find().SetSort(bson.D{{Key: "effective_date", Value: -1}, {Key: "uploaded_at", Value: -1}})
+	cursor, err := r.db.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var t domain.ExchangeRateTable
+		if err := cursor.Decode(&t); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+
+	return tables, nil
+}
+
+// GetEffective devuelve la última tabla cargada con fecha efectiva menor o igual a date.
+func (r *repository) GetEffective(ctx context.Context, date string) (domain.ExchangeRateTable, error) {
+	var t domain.ExchangeRateTable
+	filter := bson.M{"effective_date": bson.M{"$lte": date}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_date", Value: -1}, {Key: "uploaded_at", Value: -1}})
+	err := r.db.FindOne(ctx, filter, opts).Decode(&t)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return t, ErrRateNotFound
+	}
+	return t, err
+}
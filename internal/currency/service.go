@@ -0,0 +1,109 @@
+package currency
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidCurrency = errors.New("invalid currency")
+	ErrInvalidTable    = errors.New("invalid exchange rate table")
+)
+
+type Service interface {
+	UploadTable(ctx context.Context, t *domain.ExchangeRateTable) error
+	GetTables(ctx context.Context) ([]domain.ExchangeRateTable, error)
+	Convert(ctx context.Context, m domain.Money, to, date string) (domain.Money, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService crea un nuevo servicio de conversión de monedas.
+func NewService(r Repository) Service {
+	return &service{
+		repo: r,
+	}
+}
+
+func (s *service) UploadTable(ctx context.Context, t *domain.ExchangeRateTable) error {
+	if !domain.IsValidCurrency(t.Base) {
+		return fmt.Errorf("%w: base %q is not an ISO-4217 code", ErrInvalidTable, t.Base)
+	}
+	if _, err := time.Parse(domain.DateLayout, t.EffectiveDate); err != nil {
+		return fmt.Errorf("%w: effective_date: %v", ErrInvalidTable, err)
+	}
+	if len(t.Rates) == 0 {
+		return fmt.Errorf("%w: rates are required", ErrInvalidTable)
+	}
+	for code, rate := range t.Rates {
+		if !domain.IsValidCurrency(code) {
+			return fmt.Errorf("%w: %q is not an ISO-4217 code", ErrInvalidTable, code)
+		}
+		if !rate.IsPositive() {
+			return fmt.Errorf("%w: rate for %s must be positive", ErrInvalidTable, code)
+		}
+	}
+
+	t.ID = primitive.NewObjectID()
+	t.UploadedAt = time.Now().UTC()
+	if err := s.repo.Create(ctx, t); err != nil {
+		log.Printf("Error al guardar tabla de cambio: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) GetTables(ctx context.Context) ([]domain.ExchangeRateTable, error) {
+	result, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return []domain.ExchangeRateTable{}, err
+	}
+	return result, nil
+}
+
+// Convert convierte m a la moneda to usando la tabla vigente en date (domain.DateLayout).
+func (s *service) Convert(ctx context.Context, m domain.Money, to, date string) (domain.Money, error) {
+	if !domain.IsValidCurrency(to) {
+		return domain.Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, to)
+	}
+	if m.Currency == to {
+		return m, nil
+	}
+
+	table, err := s.repo.GetEffective(ctx, date)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	fromRate, err := rateFor(table, m.Currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	toRate, err := rateFor(table, to)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	converted := domain.NewMoney(m.Amount.Div(fromRate).Mul(toRate), to)
+	return converted.Round(), nil
+}
+
+func rateFor(table domain.ExchangeRateTable, code string) (decimal.Decimal, error) {
+	if code == table.Base {
+		return decimal.NewFromInt(1), nil
+	}
+	rate, ok := table.Rates[code]
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("%w: no %s rate effective %s", ErrRateNotFound, code, table.EffectiveDate)
+	}
+	return rate, nil
+}
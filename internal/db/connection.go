@@ -13,8 +13,6 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-var MongoClient = ConnectionMongodb()
-
 // conexion a mongodb, recibe el contexto, URI de mongo, nombre DB, nombre de Colecion. Retona *Colletion.
 func ConnectionMongodb() *mongo.Client {
 
@@ -26,7 +24,7 @@ func ConnectionMongodb() *mongo.Client {
 	defer cancel()
 
 	uri := fmt.Sprintf("mongodb+srv://%s:%s@%s/", os.Getenv("MONGODB_USERNAME"), os.Getenv("MONGODB_PASSWORD"), os.Getenv("MONGODB_CLUSTER_URI"))
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(NewRegistry()))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -41,6 +39,11 @@ func ConnectionMongodb() *mongo.Client {
 	return client
 }
 
+// MongoConfigured indica si el entorno tiene los datos de conexión a MongoDB.
+func MongoConfigured() bool {
+	return os.Getenv("MONGODB_CLUSTER_URI") != ""
+}
+
 func GetCollection(database, collectionName string, client *mongo.Client) *mongo.Collection {
 	return client.Database(database).Collection(collectionName)
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CalendarSourceRequest struct {
+	RoomNumber string `json:"room_number"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+}
+
+// CalendarSource es un calendario iCal externo (marketplace) que bloquea una habitación.
+type CalendarSource struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string             `json:"hotel_id" bson:"hotel_id"`
+	RoomNumber   string             `json:"room_number" bson:"room_number"`
+	Name         string             `json:"name" bson:"name"`
+	URL          string             `json:"url" bson:"url"`
+	LastSyncAt   *time.Time         `json:"last_sync_at,omitempty" bson:"last_sync_at,omitempty"`
+	LastError    string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	BlockCount   int                `json:"block_count" bson:"block_count"`
+}
+
+// CalendarBlock es un rango de fechas bloqueado por un calendario importado. End no se incluye.
+type CalendarBlock struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	SourceID     primitive.ObjectID `json:"source_id" bson:"source_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string             `json:"hotel_id" bson:"hotel_id"`
+	RoomNumber   string             `json:"room_number" bson:"room_number"`
+	UID          string             `json:"uid" bson:"uid"`
+	Summary      string             `json:"summary,omitempty" bson:"summary,omitempty"`
+	Start        string             `json:"start" bson:"start"`
+	End          string             `json:"end" bson:"end"`
+	ImportedAt   time.Time          `json:"imported_at" bson:"imported_at"`
+}
+
+// CalendarConflict es un bloqueo importado que se superpone con una estadía interna.
+type CalendarConflict struct {
+	Block         CalendarBlock      `json:"block"`
+	FolioID       primitive.ObjectID `json:"folio_id"`
+	ReservationID string             `json:"reservation_id,omitempty"`
+	CheckIn       string             `json:"check_in"`
+	CheckOut      string             `json:"check_out"`
+}
+
+type CalendarSyncResult struct {
+	Source    CalendarSource     `json:"source"`
+	Blocks    int                `json:"blocks"`
+	Conflicts []CalendarConflict `json:"conflicts"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// EmailSecurity es la evaluación de la protección contra suplantación del dominio de la
+// franquicia, desde el que salen las confirmaciones de reserva.
+type EmailSecurity struct {
+	Domain    string          `json:"domain" bson:"domain"`
+	Score     int             `json:"score" bson:"score"`
+	Grade     string          `json:"grade" bson:"grade"`
+	Checks    []SecurityCheck `json:"checks" bson:"checks"`
+	SPF       *SPFReport      `json:"spf,omitempty" bson:"spf,omitempty"`
+	DMARC     *DMARCReport    `json:"dmarc,omitempty" bson:"dmarc,omitempty"`
+	DKIM      []DKIMSelector  `json:"dkim,omitempty" bson:"dkim,omitempty"`
+	MTASTS    *MTASTSReport   `json:"mta_sts,omitempty" bson:"mta_sts,omitempty"`
+	BIMI      *BIMIReport     `json:"bimi,omitempty" bson:"bimi,omitempty"`
+	CheckedAt time.Time       `json:"checked_at" bson:"checked_at"`
+}
+
+// SPFReport es el registro SPF con sus includes expandidos. Lookups cuenta las consultas
+// DNS que requiere evaluarlo; más de 10 es un error permanente (RFC 7208).
+type SPFReport struct {
+	Record   string   `json:"record" bson:"record"`
+	All      string   `json:"all,omitempty" bson:"all,omitempty"`
+	Lookups  int      `json:"lookups" bson:"lookups"`
+	Includes []string `json:"includes,omitempty" bson:"includes,omitempty"`
+	Errors   []string `json:"errors,omitempty" bson:"errors,omitempty"`
+}
+
+// DMARCReport es la política DMARC publicada en _dmarc.
+type DMARCReport struct {
+	Record          string   `json:"record" bson:"record"`
+	Policy          string   `json:"policy,omitempty" bson:"policy,omitempty"`
+	SubdomainPolicy string   `json:"subdomain_policy,omitempty" bson:"subdomain_policy,omitempty"`
+	Percent         int      `json:"percent" bson:"percent"`
+	AlignmentDKIM   string   `json:"alignment_dkim,omitempty" bson:"alignment_dkim,omitempty"`
+	AlignmentSPF    string   `json:"alignment_spf,omitempty" bson:"alignment_spf,omitempty"`
+	AggregateReport []string `json:"aggregate_report,omitempty" bson:"aggregate_report,omitempty"`
+	ForensicReport  []string `json:"forensic_report,omitempty" bson:"forensic_report,omitempty"`
+	Errors          []string `json:"errors,omitempty" bson:"errors,omitempty"`
+}
+
+// DKIMSelector es una clave DKIM encontrada entre los selectores habituales. Una clave
+// vacía (p=) significa que fue revocada.
+type DKIMSelector struct {
+	Selector string `json:"selector" bson:"selector"`
+	KeyType  string `json:"key_type" bson:"key_type"`
+	KeyBits  int    `json:"key_bits,omitempty" bson:"key_bits,omitempty"`
+	Revoked  bool   `json:"revoked,omitempty" bson:"revoked,omitempty"`
+}
+
+// MTASTSReport es el registro _mta-sts y la política publicada en mta-sts.<dominio>.
+type MTASTSReport struct {
+	Record string   `json:"record" bson:"record"`
+	ID     string   `json:"id,omitempty" bson:"id,omitempty"`
+	Mode   string   `json:"mode,omitempty" bson:"mode,omitempty"`
+	MX     []string `json:"mx,omitempty" bson:"mx,omitempty"`
+	MaxAge int      `json:"max_age,omitempty" bson:"max_age,omitempty"`
+	Error  string   `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// BIMIReport es el registro BIMI con el logo que muestran los clientes de correo.
+type BIMIReport struct {
+	Record    string `json:"record" bson:"record"`
+	Logo      string `json:"logo,omitempty" bson:"logo,omitempty"`
+	Authority string `json:"authority,omitempty" bson:"authority,omitempty"`
+}
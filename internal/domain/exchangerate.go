@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExchangeRateTableRequest struct {
+	Base          string                     `json:"base"`
+	EffectiveDate string                     `json:"effective_date"`
+	Rates         map[string]decimal.Decimal `json:"rates"`
+}
+
+// ExchangeRateTable contiene cuántas unidades de cada moneda equivalen a 1 unidad de Base,
+// vigente desde EffectiveDate hasta la próxima tabla cargada.
+type ExchangeRateTable struct {
+	ID            primitive.ObjectID         `json:"id" bson:"_id"`
+	Base          string                     `json:"base" bson:"base"`
+	EffectiveDate string                     `json:"effective_date" bson:"effective_date"`
+	Rates         map[string]decimal.Decimal `json:"rates" bson:"rates"`
+	UploadedAt    time.Time                  `json:"uploaded_at" bson:"uploaded_at"`
+}
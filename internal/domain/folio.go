@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FolioStatus string
+
+const (
+	FolioOpen    FolioStatus = "OPEN"
+	FolioClosing FolioStatus = "CLOSING" // checkout en curso: la factura se está emitiendo
+	FolioClosed  FolioStatus = "CLOSED"
+)
+
+type ChargeType string
+
+const (
+	ChargeRoom    ChargeType = "ROOM"
+	ChargeMinibar ChargeType = "MINIBAR"
+	ChargeSpa     ChargeType = "SPA"
+	ChargeParking ChargeType = "PARKING"
+	ChargeOther   ChargeType = "OTHER"
+)
+
+func (t ChargeType) IsValid() bool {
+	switch t {
+	case ChargeRoom, ChargeMinibar, ChargeSpa, ChargeParking, ChargeOther:
+		return true
+	}
+	return false
+}
+
+type PaymentKind string
+
+const (
+	PaymentKindPayment PaymentKind = "PAYMENT"
+	PaymentKindRefund  PaymentKind = "REFUND"
+)
+
+// FolioRequest abre el folio de una estadía (check-in).
+type FolioRequest struct {
+	HotelID       string `json:"hotel_id"`
+	ReservationID string `json:"reservation_id"`
+	GuestName     string `json:"guest_name"`
+	RoomNumber    string `json:"room_number"`
+	RatePlanID    string `json:"rate_plan_id"`
+	CheckIn       string `json:"check_in"`
+	CheckOut      string `json:"check_out"`
+	Currency      string `json:"currency"`
+}
+
+// Folio acumula los cargos y pagos de una estadía. Rates guarda la tarifa cotizada
+// para cada noche al abrir el folio, que se postea en la auditoría nocturna.
+type Folio struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id"`
+	FranquiciaID  primitive.ObjectID  `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID       string              `json:"hotel_id" bson:"hotel_id"`
+	ReservationID string              `json:"reservation_id,omitempty" bson:"reservation_id,omitempty"`
+	GuestName     string              `json:"guest_name" bson:"guest_name"`
+	RoomNumber    string              `json:"room_number" bson:"room_number"`
+	RatePlanID    primitive.ObjectID  `json:"rate_plan_id" bson:"rate_plan_id"`
+	CheckIn       string              `json:"check_in" bson:"check_in"`
+	CheckOut      string              `json:"check_out" bson:"check_out"`
+	Currency      string              `json:"currency" bson:"currency"`
+	Rates         []QuoteNight        `json:"rates" bson:"rates"`
+	Status        FolioStatus         `json:"status" bson:"status"`
+	Charges       []Charge            `json:"charges" bson:"charges"`
+	Payments      []Payment           `json:"payments" bson:"payments"`
+	InvoiceID     *primitive.ObjectID `json:"invoice_id,omitempty" bson:"invoice_id,omitempty"`
+	OpenedAt      time.Time           `json:"opened_at" bson:"opened_at"`
+	ClosedAt      *time.Time          `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+	// OriginalCurrency es la moneda del folio cuando la respuesta se convirtió con ?currency=.
+	OriginalCurrency string `json:"original_currency,omitempty" bson:"-"`
+}
+
+type ChargeRequest struct {
+	Type        ChargeType      `json:"type"`
+	Description string          `json:"description"`
+	Date        string          `json:"date"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// Charge es un cargo del folio. Taxes se calcula al postear según la configuración del hotel.
+type Charge struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Type        ChargeType         `json:"type" bson:"type"`
+	Description string             `json:"description" bson:"description"`
+	Date        string             `json:"date" bson:"date"`
+	Amount      Money              `json:"amount" bson:"amount"`
+	Taxes       []TaxLine          `json:"taxes,omitempty" bson:"taxes,omitempty"`
+	PostedAt    time.Time          `json:"posted_at" bson:"posted_at"`
+}
+
+type PaymentRequest struct {
+	Kind      PaymentKind     `json:"kind"`
+	Method    string          `json:"method"`
+	Reference string          `json:"reference"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+type Payment struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Kind      PaymentKind        `json:"kind" bson:"kind"`
+	Method    string             `json:"method" bson:"method"`
+	Reference string             `json:"reference,omitempty" bson:"reference,omitempty"`
+	Amount    Money              `json:"amount" bson:"amount"`
+	PostedAt  time.Time          `json:"posted_at" bson:"posted_at"`
+}
+
+type TaxLine struct {
+	Name   string          `json:"name" bson:"name"`
+	Rate   decimal.Decimal `json:"rate" bson:"rate"`
+	Amount Money           `json:"amount" bson:"amount"`
+}
+
+// TaxRule aplica Rate (porcentaje) a los cargos de los tipos indicados; sin tipos aplica a todos.
+type TaxRule struct {
+	Name      string          `json:"name" bson:"name"`
+	Rate      decimal.Decimal `json:"rate" bson:"rate"`
+	AppliesTo []ChargeType    `json:"applies_to,omitempty" bson:"applies_to,omitempty"`
+}
+
+func (r TaxRule) Applies(t ChargeType) bool {
+	if len(r.AppliesTo) == 0 {
+		return true
+	}
+	for _, ct := range r.AppliesTo {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+type TaxConfigRequest struct {
+	Jurisdiction string    `json:"jurisdiction"`
+	Rules        []TaxRule `json:"rules"`
+}
+
+type TaxConfig struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string             `json:"hotel_id" bson:"hotel_id"`
+	Jurisdiction string             `json:"jurisdiction" bson:"jurisdiction"`
+	Rules        []TaxRule          `json:"rules" bson:"rules"`
+}
+
+// Balance devuelve cargos + impuestos - pagos + reembolsos.
+func (f Folio) Balance() Money {
+	balance := decimal.Zero
+	for _, c := range f.Charges {
+		balance = balance.Add(c.Amount.Amount)
+		for _, t := range c.Taxes {
+			balance = balance.Add(t.Amount.Amount)
+		}
+	}
+	for _, p := range f.Payments {
+		if p.Kind == PaymentKindRefund {
+			balance = balance.Add(p.Amount.Amount)
+			continue
+		}
+		balance = balance.Sub(p.Amount.Amount)
+	}
+	return NewMoney(balance, f.Currency)
+}
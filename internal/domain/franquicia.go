@@ -1,37 +1,214 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type FranquiciaRequest struct {
 	ID       string   `json:"id,omitempty" bson:"_id,omitempty"`
 	URL      string   `json:"url" bson:"url"`
 	Name     string   `json:"name" bson:"name"`
 	Location Location `json:"location" bson:"location"`
+	// Mode y Required solo se usan al crear; ver CreateOptions.
+	Mode     string   `json:"mode,omitempty" bson:"-"`
+	Required []string `json:"required,omitempty" bson:"-"`
+}
+
+// Modos de creación de una franquicia.
+const (
+	CreateBestEffort = "best-effort"
+	CreateStrict     = "strict"
+)
+
+// CreateOptions elige qué pasa si fallan fuentes de datos al crear la franquicia. En
+// best-effort (por defecto) se guarda lo que se pudo obtener; en strict no se guarda nada
+// si falla alguna de Required. Required tiene que nombrar enrichers registrados; si está
+// vacío se exigen solo domain-info y ssl, porque las fuentes que dependen del sitio fallan
+// seguido por motivos ajenos a la franquicia.
+type CreateOptions struct {
+	Mode     string
+	Required []string
 }
 
 type Franquicia struct {
-	ID            primitive.ObjectID `json:"id" bson:"_id"`
-	Name          string             `json:"name" bson:"name"`
-	URL           string             `json:"url" bson:"url"`
-	Location      Location           `json:"location" bson:"location"`
-	LogoURL       string             `json:"logo_url,omitempty" bson:"logo_url,omitempty"`
-	IsWebsiteLive bool               `json:"is_website_live" bson:"is_website_live"`
-	DomainInfo    DomainInfo         `json:"domain_info,omitempty" bson:"domain_info,omitempty"`
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	Name           string             `json:"name" bson:"name"`
+	NameSource     string             `json:"name_source,omitempty" bson:"name_source,omitempty"`
+	URL            string             `json:"url" bson:"url"`
+	Location       Location           `json:"location" bson:"location"`
+	LocationSource string             `json:"location_source,omitempty" bson:"location_source,omitempty"`
+	Contact        *Contact           `json:"contact,omitempty" bson:"contact,omitempty"`
+	Lodging        *LodgingDetails    `json:"lodging,omitempty" bson:"lodging,omitempty"`
+	LogoURL        string             `json:"logo_url,omitempty" bson:"logo_url,omitempty"`
+	Logo           *BrandAsset        `json:"logo,omitempty" bson:"logo,omitempty"`
+	Favicon        *BrandAsset        `json:"favicon,omitempty" bson:"favicon,omitempty"`
+	IsWebsiteLive  bool               `json:"is_website_live" bson:"is_website_live"`
+	DomainInfo     DomainInfo         `json:"domain_info,omitempty" bson:"domain_info,omitempty"`
+	Technologies   []Technology       `json:"technologies,omitempty" bson:"technologies,omitempty"`
+	Enrichment     []EnrichmentResult `json:"enrichment,omitempty" bson:"enrichment,omitempty"`
+}
+
+// Estados del resultado de un enricher.
+const (
+	EnrichmentOK      = "ok"
+	EnrichmentFailed  = "failed"
+	EnrichmentSkipped = "skipped"
+)
+
+// EnrichmentResult registra cómo le fue a cada fuente de datos al crear la franquicia.
+type EnrichmentResult struct {
+	Name       string `json:"name" bson:"name"`
+	Status     string `json:"status" bson:"status"`
+	Error      string `json:"error,omitempty" bson:"error,omitempty"`
+	Attempts   int    `json:"attempts" bson:"attempts"`
+	DurationMs int64  `json:"duration_ms" bson:"duration_ms"`
+}
+
+// Technology es una tecnología (CMS, motor de reservas, analítica, CDN, ...) detectada en
+// el sitio de la franquicia. Confidence va de 0 a 100 y Sources dice de qué señales salió.
+type Technology struct {
+	Name       string   `json:"name" bson:"name"`
+	Categories []string `json:"categories,omitempty" bson:"categories,omitempty"`
+	Version    string   `json:"version,omitempty" bson:"version,omitempty"`
+	Confidence int      `json:"confidence" bson:"confidence"`
+	Sources    []string `json:"sources,omitempty" bson:"sources,omitempty"`
+}
+
+// TechnologyFilter busca franquicias por tecnología. VersionBelow deja solo las que usan
+// una versión detectada menor a la indicada.
+type TechnologyFilter struct {
+	Name         string
+	Category     string
+	VersionBelow string
+}
+
+// Contact son los datos de contacto publicados por el hotel en su sitio.
+type Contact struct {
+	Telephone string `json:"telephone,omitempty" bson:"telephone,omitempty"`
+	Email     string `json:"email,omitempty" bson:"email,omitempty"`
+	Fax       string `json:"fax,omitempty" bson:"fax,omitempty"`
+}
+
+// LodgingDetails son los datos de schema.org Hotel/LodgingBusiness del sitio.
+type LodgingDetails struct {
+	Type         string   `json:"type,omitempty" bson:"type,omitempty"`
+	StarRating   float64  `json:"star_rating,omitempty" bson:"star_rating,omitempty"`
+	CheckinTime  string   `json:"checkin_time,omitempty" bson:"checkin_time,omitempty"`
+	CheckoutTime string   `json:"checkout_time,omitempty" bson:"checkout_time,omitempty"`
+	PriceRange   string   `json:"price_range,omitempty" bson:"price_range,omitempty"`
+	Amenities    []string `json:"amenities,omitempty" bson:"amenities,omitempty"`
+}
+
+// BrandAsset es una imagen de marca (logo o favicon) descargada del sitio. Data solo se
+// usa durante la extracción y no se persiste con la franquicia; la copia guardada se
+// sirve en /media/:media_id.
+type BrandAsset struct {
+	MediaID     *primitive.ObjectID `json:"media_id,omitempty" bson:"media_id,omitempty"`
+	URL         string              `json:"url" bson:"url"`
+	ContentType string              `json:"content_type" bson:"content_type"`
+	Width       int                 `json:"width" bson:"width"`
+	Height      int                 `json:"height" bson:"height"`
+	Size        int                 `json:"size" bson:"size"`
+	Source      string              `json:"source" bson:"source"`
+	Score       int                 `json:"score" bson:"score"`
+	Data        []byte              `json:"-" bson:"-"`
+}
+
+// NameCandidate es un nombre posible de la franquicia con la fuente de donde salió.
+type NameCandidate struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Score  int    `json:"score"`
 }
 
 type DomainInfo struct {
-	CreatedDate      string        `json:"created_date,omitempty" bson:"created_date,omitempty"`
-	ExpiryDate       string        `json:"expiry_date,omitempty" bson:"expiry_date,omitempty"`
-	RegistrarName    string        `json:"registrar_name,omitempty" bson:"registrar_name,omitempty"`
-	ContactEmail     string        `json:"contact_email,omitempty" bson:"contact_email,omitempty"`
-	Protocol         string        `json:"protocol,omitempty" bson:"protocol,omitempty"`
-	IsProtocolSecure bool          `json:"is_protocol_secure,omitempty" bson:"is_protocol_secure,omitempty"`
-	ServerHops       []string      `json:"server_hops,omitempty" bson:"server_hops,omitempty"`
-	SSLGrade         string        `json:"ssl_grade,omitempty" bson:"ssl_grade,omitempty"`
-	DNSRecords       []DNSRecord   `json:"dns_records,omitempty" bson:"dns_records,omitempty"`
-	RegistrarInfo    RegistrarInfo `json:"registrar_info,omitempty" bson:"registrar_info,omitempty"`
-	TechnicalInfo    TechnicalInfo `json:"technical_info,omitempty" bson:"technical_info,omitempty"`
+	CreatedDate      *time.Time     `json:"created_date,omitempty" bson:"created_date,omitempty"`
+	ExpiryDate       *time.Time     `json:"expiry_date,omitempty" bson:"expiry_date,omitempty"`
+	RegistrarName    string         `json:"registrar_name,omitempty" bson:"registrar_name,omitempty"`
+	ContactEmail     string         `json:"contact_email,omitempty" bson:"contact_email,omitempty"`
+	Protocol         string         `json:"protocol,omitempty" bson:"protocol,omitempty"`
+	IsProtocolSecure bool           `json:"is_protocol_secure,omitempty" bson:"is_protocol_secure,omitempty"`
+	ServerHops       []string       `json:"server_hops,omitempty" bson:"server_hops,omitempty"`
+	SSLGrade         string         `json:"ssl_grade,omitempty" bson:"ssl_grade,omitempty"`
+	DNSRecords       []DNSRecord    `json:"dns_records,omitempty" bson:"dns_records,omitempty"`
+	RegistrarInfo    RegistrarInfo  `json:"registrar_info,omitempty" bson:"registrar_info,omitempty"`
+	TechnicalInfo    TechnicalInfo  `json:"technical_info,omitempty" bson:"technical_info,omitempty"`
+	Source           string         `json:"source,omitempty" bson:"source,omitempty"`
+	CreatedDateRaw   *RawDate       `json:"created_date_raw,omitempty" bson:"created_date_raw,omitempty"`
+	ExpiryDateRaw    *RawDate       `json:"expiry_date_raw,omitempty" bson:"expiry_date_raw,omitempty"`
+	SecurityHeaders  *SecurityAudit `json:"security_headers,omitempty" bson:"security_headers,omitempty"`
+	EmailSecurity    *EmailSecurity `json:"email_security,omitempty" bson:"email_security,omitempty"`
 }
+
+// SecurityAudit es la evaluación de las cabeceras de seguridad de la home del sitio.
+// Score va de 0 a 100 según el peso de los controles aprobados.
+type SecurityAudit struct {
+	URL          string          `json:"url" bson:"url"`
+	Score        int             `json:"score" bson:"score"`
+	Grade        string          `json:"grade" bson:"grade"`
+	Checks       []SecurityCheck `json:"checks" bson:"checks"`
+	MixedContent []string        `json:"mixed_content,omitempty" bson:"mixed_content,omitempty"`
+	CheckedAt    time.Time       `json:"checked_at" bson:"checked_at"`
+}
+
+// SecurityGrade convierte un puntaje de 0 a 100 en una nota de A a F.
+func SecurityGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 65:
+		return "C"
+	case score >= 50:
+		return "D"
+	}
+	return "F"
+}
+
+// SecurityCheck es el resultado de un control: Value es la cabecera tal como llegó y
+// Detail explica por qué no aprobó.
+type SecurityCheck struct {
+	Name   string `json:"name" bson:"name"`
+	Pass   bool   `json:"pass" bson:"pass"`
+	Value  string `json:"value,omitempty" bson:"value,omitempty"`
+	Detail string `json:"detail,omitempty" bson:"detail,omitempty"`
+}
+
+// RawDate conserva la fecha tal como la publicó el registro y el formato reconocido
+// (vacío si no se pudo interpretar).
+type RawDate struct {
+	Value  string `json:"value" bson:"value"`
+	Format string `json:"format,omitempty" bson:"format,omitempty"`
+}
+
+// DateRangeField indica a qué fecha del dominio se aplica un filtro por rango.
+type DateRangeField string
+
+const (
+	DateFieldCreated DateRangeField = "created"
+	DateFieldExpiry  DateRangeField = "expiry"
+	DateFieldBoth    DateRangeField = "both"
+	DateFieldEither  DateRangeField = "either"
+)
+
+func (f DateRangeField) IsValid() bool {
+	switch f {
+	case DateFieldCreated, DateFieldExpiry, DateFieldBoth, DateFieldEither:
+		return true
+	}
+	return false
+}
+
+// DateRange es un rango [From, To] sobre las fechas del dominio; cualquiera de los extremos puede faltar.
+type DateRange struct {
+	From  *time.Time
+	To    *time.Time
+	Field DateRangeField
+}
+
 type DNSRecord struct {
 	Type     string `json:"type" bson:"type"`
 	Value    string `json:"value" bson:"value"`
@@ -89,8 +266,11 @@ type TechnicalInfo struct {
 	Email        string `json:"email,omitempty" bson:"email,omitempty"`
 }
 
+// Location es la ubicación de la franquicia. Franquicia.LocationSource indica de dónde salió:
+// el marcado schema.org del sitio (jsonld, microdata, rdfa) o el registro del dominio (rdap, whois).
 type Location struct {
 	City      string  `json:"city" bson:"city"`
+	Region    string  `json:"region,omitempty" bson:"region,omitempty"`
 	Country   string  `json:"country" bson:"country"`
 	Address   string  `json:"address" bson:"address"`
 	ZipCode   string  `json:"zip_code" bson:"zip_code"`
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RoomStatus string
+
+const (
+	RoomDirty      RoomStatus = "DIRTY"
+	RoomInProgress RoomStatus = "IN_PROGRESS"
+	RoomClean      RoomStatus = "CLEAN"
+	RoomInspected  RoomStatus = "INSPECTED"
+)
+
+// roomTransitions son los cambios de estado permitidos. Cualquier estado puede volver a DIRTY.
+var roomTransitions = map[RoomStatus][]RoomStatus{
+	RoomDirty:      {RoomInProgress},
+	RoomInProgress: {RoomClean},
+	RoomClean:      {RoomInspected},
+	RoomInspected:  {},
+}
+
+func (s RoomStatus) IsValid() bool {
+	_, ok := roomTransitions[s]
+	return ok
+}
+
+func (s RoomStatus) CanTransitionTo(next RoomStatus) bool {
+	if next == RoomDirty {
+		return true
+	}
+	for _, allowed := range roomTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssignable indica si la habitación puede asignarse en un check-in.
+func (s RoomStatus) IsAssignable() bool {
+	return s == RoomClean || s == RoomInspected
+}
+
+type Room struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string             `json:"hotel_id" bson:"hotel_id"`
+	Number       string             `json:"number" bson:"number"`
+	Type         string             `json:"type,omitempty" bson:"type,omitempty"`
+	Status       RoomStatus         `json:"status" bson:"status"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type RoomStatusRequest struct {
+	Status RoomStatus `json:"status"`
+}
+
+type RoomTypeRequest struct {
+	Type string `json:"type"`
+}
+
+type TaskType string
+
+const (
+	TaskCheckout TaskType = "CHECKOUT"
+	TaskStayover TaskType = "STAYOVER"
+)
+
+type TaskStatus string
+
+const (
+	TaskPending    TaskStatus = "PENDING"
+	TaskInProgress TaskStatus = "IN_PROGRESS"
+	TaskDone       TaskStatus = "DONE"
+	TaskInspected  TaskStatus = "INSPECTED"
+)
+
+type HousekeepingTask struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID  `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string              `json:"hotel_id" bson:"hotel_id"`
+	RoomNumber   string              `json:"room_number" bson:"room_number"`
+	FolioID      *primitive.ObjectID `json:"folio_id,omitempty" bson:"folio_id,omitempty"`
+	Type         TaskType            `json:"type" bson:"type"`
+	Status       TaskStatus          `json:"status" bson:"status"`
+	Date         string              `json:"date" bson:"date"`
+	AssignedTo   string              `json:"assigned_to,omitempty" bson:"assigned_to,omitempty"`
+	InspectedBy  string              `json:"inspected_by,omitempty" bson:"inspected_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+	StartedAt    *time.Time          `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	CompletedAt  *time.Time          `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	InspectedAt  *time.Time          `json:"inspected_at,omitempty" bson:"inspected_at,omitempty"`
+}
+
+type TaskAssignRequest struct {
+	Staff string `json:"staff"`
+}
+
+type TaskInspectRequest struct {
+	Inspector string `json:"inspector"`
+}
+
+// HousekeepingBoard es el tablero de estado de habitaciones de un hotel.
+type HousekeepingBoard struct {
+	HotelID string             `json:"hotel_id"`
+	Rooms   []Room             `json:"rooms"`
+	Tasks   []HousekeepingTask `json:"tasks"`
+}
+
+// RoomStatusEvent se publica en el tablero en tiempo real cada vez que cambia una habitación.
+type RoomStatusEvent struct {
+	Room Room              `json:"room"`
+	Task *HousekeepingTask `json:"task,omitempty"`
+}
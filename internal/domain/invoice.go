@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvoiceIssuer es la identidad de la franquicia al momento de emitir la factura.
+type InvoiceIssuer struct {
+	Name    string `json:"name" bson:"name"`
+	Address string `json:"address,omitempty" bson:"address,omitempty"`
+	City    string `json:"city,omitempty" bson:"city,omitempty"`
+	Country string `json:"country,omitempty" bson:"country,omitempty"`
+	ZipCode string `json:"zip_code,omitempty" bson:"zip_code,omitempty"`
+	LogoURL string `json:"logo_url,omitempty" bson:"logo_url,omitempty"`
+	// LogoMediaID es el logo guardado en el blob store, el que se imprime en el PDF.
+	LogoMediaID *primitive.ObjectID `json:"logo_media_id,omitempty" bson:"logo_media_id,omitempty"`
+}
+
+type InvoiceLine struct {
+	Date        string     `json:"date" bson:"date"`
+	Type        ChargeType `json:"type" bson:"type"`
+	Description string     `json:"description" bson:"description"`
+	Amount      Money      `json:"amount" bson:"amount"`
+}
+
+type InvoiceTax struct {
+	Name   string          `json:"name" bson:"name"`
+	Rate   decimal.Decimal `json:"rate" bson:"rate"`
+	Amount Money           `json:"amount" bson:"amount"`
+}
+
+type Invoice struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string             `json:"hotel_id" bson:"hotel_id"`
+	FolioID      primitive.ObjectID `json:"folio_id" bson:"folio_id"`
+	Sequence     int64              `json:"sequence" bson:"sequence"`
+	Number       string             `json:"number" bson:"number"`
+	IssuedAt     time.Time          `json:"issued_at" bson:"issued_at"`
+	Issuer       InvoiceIssuer      `json:"issuer" bson:"issuer"`
+	GuestName    string             `json:"guest_name" bson:"guest_name"`
+	RoomNumber   string             `json:"room_number" bson:"room_number"`
+	CheckIn      string             `json:"check_in" bson:"check_in"`
+	CheckOut     string             `json:"check_out" bson:"check_out"`
+	Lines        []InvoiceLine      `json:"lines" bson:"lines"`
+	Taxes        []InvoiceTax       `json:"taxes" bson:"taxes"`
+	Subtotal     Money              `json:"subtotal" bson:"subtotal"`
+	TaxTotal     Money              `json:"tax_total" bson:"tax_total"`
+	Total        Money              `json:"total" bson:"total"`
+	Paid         Money              `json:"paid" bson:"paid"`
+	Balance      Money              `json:"balance" bson:"balance"`
+	// OriginalCurrency es la moneda de la factura cuando la respuesta se convirtió con ?currency=.
+	OriginalCurrency string `json:"original_currency,omitempty" bson:"-"`
+}
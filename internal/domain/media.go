@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MediaKind string
+
+const (
+	MediaKindLogo    MediaKind = "logo"
+	MediaKindFavicon MediaKind = "favicon"
+	MediaKindPhoto   MediaKind = "photo"
+)
+
+func (k MediaKind) IsValid() bool {
+	switch k {
+	case MediaKindLogo, MediaKindFavicon, MediaKindPhoto:
+		return true
+	}
+	return false
+}
+
+// MediaOriginal es el nombre de la variante con la imagen tal como se recibió.
+const MediaOriginal = "original"
+
+// MediaVariant es una versión guardada de la imagen: la original o una miniatura.
+// Key es la clave en el blob store, derivada del hash del contenido.
+type MediaVariant struct {
+	Size        string `json:"size" bson:"size"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Width       int    `json:"width" bson:"width"`
+	Height      int    `json:"height" bson:"height"`
+	Bytes       int    `json:"bytes" bson:"bytes"`
+	Hash        string `json:"hash" bson:"hash"`
+	Key         string `json:"-" bson:"key"`
+}
+
+// MediaAsset es un logo, favicon o foto de hotel guardado en el blob store.
+type MediaAsset struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID      string             `json:"hotel_id,omitempty" bson:"hotel_id,omitempty"`
+	Kind         MediaKind          `json:"kind" bson:"kind"`
+	SourceURL    string             `json:"source_url,omitempty" bson:"source_url,omitempty"`
+	Hash         string             `json:"hash" bson:"hash"`
+	Variants     []MediaVariant     `json:"variants" bson:"variants"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PerformanceSample es una medición de la home de una franquicia. Se guarda una por
+// corrida y la serie permite ver la disponibilidad y los tiempos a lo largo del tiempo.
+// Los tiempos están en milisegundos y los tamaños son los bytes transferidos.
+type PerformanceSample struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	URL          string             `json:"url" bson:"url"`
+	Up           bool               `json:"up" bson:"up"`
+	StatusCode   int                `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error        string             `json:"error,omitempty" bson:"error,omitempty"`
+	Redirects    int                `json:"redirects" bson:"redirects"`
+	// Las fases corresponden a la última petición, después de seguir las redirecciones;
+	// TotalMs incluye las redirecciones.
+	DNSMs      int64  `json:"dns_ms" bson:"dns_ms"`
+	ConnectMs  int64  `json:"connect_ms" bson:"connect_ms"`
+	TLSMs      int64  `json:"tls_ms" bson:"tls_ms"`
+	TTFBMs     int64  `json:"ttfb_ms" bson:"ttfb_ms"`
+	DownloadMs int64  `json:"download_ms" bson:"download_ms"`
+	TotalMs    int64  `json:"total_ms" bson:"total_ms"`
+	Protocol   string `json:"protocol,omitempty" bson:"protocol,omitempty"`
+	HTTP2      bool   `json:"http2" bson:"http2"`
+	// HTTP3 indica que el sitio anuncia h3 en Alt-Svc; no se prueba la conexión QUIC.
+	HTTP3       bool   `json:"http3" bson:"http3"`
+	Compression string `json:"compression,omitempty" bson:"compression,omitempty"`
+	HTMLBytes   int64  `json:"html_bytes" bson:"html_bytes"`
+	// PageWeight suma la home y los recursos que referencia; Requests los cuenta a todos.
+	PageWeight int64     `json:"page_weight" bson:"page_weight"`
+	Requests   int       `json:"requests" bson:"requests"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+// PerformanceSummary resume las mediciones de un período. Uptime y HTTP2 son porcentajes;
+// los percentiles se calculan solo con las mediciones en las que el sitio respondió.
+type PerformanceSummary struct {
+	Samples int                      `json:"samples"`
+	Uptime  float64                  `json:"uptime"`
+	HTTP2   float64                  `json:"http2"`
+	Metrics map[string]MetricSummary `json:"metrics"`
+}
+
+type MetricSummary struct {
+	Min  int64 `json:"min"`
+	Mean int64 `json:"mean"`
+	P50  int64 `json:"p50"`
+	P75  int64 `json:"p75"`
+	P90  int64 `json:"p90"`
+	P95  int64 `json:"p95"`
+	P99  int64 `json:"p99"`
+	Max  int64 `json:"max"`
+}
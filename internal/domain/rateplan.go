@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Formato de fecha usado en temporadas y cotizaciones (día de estadía).
+const DateLayout = "2006-01-02"
+
+type RatePlanType string
+
+const (
+	RatePlanBAR           RatePlanType = "BAR"
+	RatePlanNonRefundable RatePlanType = "NON_REFUNDABLE"
+	RatePlanCorporate     RatePlanType = "CORPORATE"
+	RatePlanPackage       RatePlanType = "PACKAGE"
+)
+
+func (t RatePlanType) IsValid() bool {
+	switch t {
+	case RatePlanBAR, RatePlanNonRefundable, RatePlanCorporate, RatePlanPackage:
+		return true
+	}
+	return false
+}
+
+type RatePlanRequest struct {
+	HotelID              string                `json:"hotel_id"`
+	Code                 string                `json:"code"`
+	Name                 string                `json:"name"`
+	Type                 RatePlanType          `json:"type"`
+	BaseRate             Money                 `json:"base_rate"`
+	Seasons              []Season              `json:"seasons"`
+	DayOfWeekAdjustments []DayOfWeekAdjustment `json:"day_of_week_adjustments"`
+	MinLOS               int                   `json:"min_los"`
+	MaxLOS               int                   `json:"max_los"`
+	Derived              *DerivedRate          `json:"derived"`
+}
+
+type RatePlan struct {
+	ID                   primitive.ObjectID    `json:"id" bson:"_id"`
+	FranquiciaID         primitive.ObjectID    `json:"franquicia_id" bson:"franquicia_id"`
+	HotelID              string                `json:"hotel_id" bson:"hotel_id"`
+	Code                 string                `json:"code" bson:"code"`
+	Name                 string                `json:"name" bson:"name"`
+	Type                 RatePlanType          `json:"type" bson:"type"`
+	BaseRate             Money                 `json:"base_rate" bson:"base_rate"`
+	Seasons              []Season              `json:"seasons,omitempty" bson:"seasons,omitempty"`
+	DayOfWeekAdjustments []DayOfWeekAdjustment `json:"day_of_week_adjustments,omitempty" bson:"day_of_week_adjustments,omitempty"`
+	MinLOS               int                   `json:"min_los,omitempty" bson:"min_los,omitempty"`
+	MaxLOS               int                   `json:"max_los,omitempty" bson:"max_los,omitempty"`
+	Derived              *DerivedRate          `json:"derived,omitempty" bson:"derived,omitempty"`
+}
+
+// Season reemplaza la tarifa base entre StartDate y EndDate (ambas inclusive).
+type Season struct {
+	Name      string `json:"name" bson:"name"`
+	StartDate string `json:"start_date" bson:"start_date"`
+	EndDate   string `json:"end_date" bson:"end_date"`
+	Rate      Money  `json:"rate" bson:"rate"`
+}
+
+// DayOfWeekAdjustment aplica un porcentaje sobre la tarifa de la noche (ej: 15 = +15%, -10 = -10%).
+type DayOfWeekAdjustment struct {
+	Weekday time.Weekday    `json:"weekday" bson:"weekday"`
+	Percent decimal.Decimal `json:"percent" bson:"percent"`
+}
+
+// DerivedRate calcula la tarifa a partir de otro plan: parent * (1 + Percent/100) + Amount.
+// Ej: corporate = BAR - 10% -> Percent: -10.
+type DerivedRate struct {
+	ParentID primitive.ObjectID `json:"parent_id" bson:"parent_id"`
+	Percent  decimal.Decimal    `json:"percent" bson:"percent,omitempty"`
+	Amount   Money              `json:"amount" bson:"amount,omitempty"`
+}
+
+type QuoteNight struct {
+	Date   string `json:"date" bson:"date"`
+	Season string `json:"season,omitempty" bson:"season,omitempty"`
+	Rate   Money  `json:"rate" bson:"rate"`
+	// OriginalRate es la tarifa en la moneda del plan cuando se pidió otra moneda.
+	OriginalRate *Money `json:"original_rate,omitempty" bson:"original_rate,omitempty"`
+}
+
+type RateQuote struct {
+	RatePlanID primitive.ObjectID `json:"rate_plan_id"`
+	HotelID    string             `json:"hotel_id"`
+	CheckIn    string             `json:"check_in"`
+	CheckOut   string             `json:"check_out"`
+	Nights     []QuoteNight       `json:"nights"`
+	Total      Money              `json:"total"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RawDomainResponse es la respuesta cruda de una consulta WHOIS/RDAP, guardada comprimida
+// (gzip) para poder revisarla o volver a interpretarla sin consultar la red. Error indica
+// por qué no se pudo usar (dominio no encontrado, respuesta inválida, ...).
+type RawDomainResponse struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	Domain       string             `json:"domain" bson:"domain"`
+	Source       string             `json:"source" bson:"source"`
+	Compression  string             `json:"compression" bson:"compression"`
+	Size         int                `json:"size" bson:"size"`
+	Data         []byte             `json:"-" bson:"data"`
+	Body         string             `json:"body,omitempty" bson:"-"`
+	Error        string             `json:"error,omitempty" bson:"error,omitempty"`
+	FetchedAt    time.Time          `json:"fetched_at" bson:"fetched_at"`
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SelectorType string
+
+const (
+	SelectorCSS   SelectorType = "css"
+	SelectorXPath SelectorType = "xpath"
+)
+
+// ScrapeRule extrae un campo de la página. Sin Attr se toma el texto del elemento; Regex
+// es opcional y, si tiene un grupo, se queda con el primero.
+type ScrapeRule struct {
+	Field    string       `json:"field" bson:"field"`
+	Type     SelectorType `json:"type,omitempty" bson:"type,omitempty"`
+	Selector string       `json:"selector" bson:"selector"`
+	Attr     string       `json:"attr,omitempty" bson:"attr,omitempty"`
+	Regex    string       `json:"regex,omitempty" bson:"regex,omitempty"`
+}
+
+// ScrapeProfile es un conjunto de reglas para una página. Pertenece a una franquicia o,
+// con Template, es una plantilla reutilizable para sitios hechos con el mismo tema o CMS.
+// Path es la página a visitar, relativa a la URL de la franquicia ("/contacto").
+type ScrapeProfile struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id"`
+	FranquiciaID *primitive.ObjectID `json:"franquicia_id,omitempty" bson:"franquicia_id,omitempty"`
+	Template     string              `json:"template,omitempty" bson:"template,omitempty"`
+	Name         string              `json:"name" bson:"name"`
+	Path         string              `json:"path,omitempty" bson:"path,omitempty"`
+	Rules        []ScrapeRule        `json:"rules" bson:"rules"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+type ScrapeProfileRequest struct {
+	Template string       `json:"template"`
+	Name     string       `json:"name"`
+	Path     string       `json:"path"`
+	Rules    []ScrapeRule `json:"rules"`
+}
+
+// ScrapeRequest elige qué reglas correr: un perfil guardado, una plantilla, reglas sin
+// guardar (Profile) o, si no se indica nada, todos los perfiles de la franquicia.
+// Con Preview se devuelven los valores sin modificar la franquicia.
+type ScrapeRequest struct {
+	ProfileID string                `json:"profile_id"`
+	Template  string                `json:"template"`
+	Profile   *ScrapeProfileRequest `json:"profile"`
+	Preview   bool                  `json:"preview"`
+}
+
+// ScrapeResult son los valores extraídos por un perfil.
+type ScrapeResult struct {
+	ProfileID *primitive.ObjectID `json:"profile_id,omitempty"`
+	Name      string              `json:"name"`
+	URL       string              `json:"url"`
+	Values    map[string]string   `json:"values"`
+	Missing   []string            `json:"missing,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+type ScrapeResponse struct {
+	Preview    bool           `json:"preview"`
+	Results    []ScrapeResult `json:"results"`
+	Franquicia *Franquicia    `json:"franquicia,omitempty"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Categorías de los controles del reporte de salud del sitio.
+const (
+	HealthSEO           = "seo"
+	HealthAccessibility = "accessibility"
+)
+
+// SiteHealthReport es el reporte fechado de SEO y accesibilidad de la home de una
+// franquicia. Se guarda uno por corrida para poder ver la evolución.
+type SiteHealthReport struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id"`
+	FranquiciaID       primitive.ObjectID `json:"franquicia_id" bson:"franquicia_id"`
+	URL                string             `json:"url" bson:"url"`
+	SEOScore           int                `json:"seo_score" bson:"seo_score"`
+	AccessibilityScore int                `json:"accessibility_score" bson:"accessibility_score"`
+	Checks             []HealthCheck      `json:"checks" bson:"checks"`
+	Title              string             `json:"title,omitempty" bson:"title,omitempty"`
+	MetaDescription    string             `json:"meta_description,omitempty" bson:"meta_description,omitempty"`
+	Canonical          string             `json:"canonical,omitempty" bson:"canonical,omitempty"`
+	Lang               string             `json:"lang,omitempty" bson:"lang,omitempty"`
+	Hreflang           []HreflangLink     `json:"hreflang,omitempty" bson:"hreflang,omitempty"`
+	HasRobotsTxt       bool               `json:"has_robots_txt" bson:"has_robots_txt"`
+	SitemapURL         string             `json:"sitemap_url,omitempty" bson:"sitemap_url,omitempty"`
+	LinksChecked       int                `json:"links_checked" bson:"links_checked"`
+	BrokenLinks        []BrokenLink       `json:"broken_links,omitempty" bson:"broken_links,omitempty"`
+	Images             int                `json:"images" bson:"images"`
+	ImagesWithAlt      int                `json:"images_with_alt" bson:"images_with_alt"`
+	UnlabeledFields    []string           `json:"unlabeled_fields,omitempty" bson:"unlabeled_fields,omitempty"`
+	CreatedAt          time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// HealthCheck es un control de SEO o accesibilidad aprobado o no, con el motivo.
+type HealthCheck struct {
+	Name     string `json:"name" bson:"name"`
+	Category string `json:"category" bson:"category"`
+	Pass     bool   `json:"pass" bson:"pass"`
+	Value    string `json:"value,omitempty" bson:"value,omitempty"`
+	Detail   string `json:"detail,omitempty" bson:"detail,omitempty"`
+}
+
+type HreflangLink struct {
+	Lang string `json:"lang" bson:"lang"`
+	URL  string `json:"url" bson:"url"`
+}
+
+// BrokenLink es un enlace interno de la home que no respondió o respondió con error.
+type BrokenLink struct {
+	URL        string `json:"url" bson:"url"`
+	StatusCode int    `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error      string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// SiteHealthTrend es la evolución de los reportes de una franquicia en un período. Los
+// cambios comparan el último reporte con el primero del período.
+type SiteHealthTrend struct {
+	Points              []SiteHealthPoint `json:"points"`
+	SEOChange           int               `json:"seo_change"`
+	AccessibilityChange int               `json:"accessibility_change"`
+	BrokenLinksChange   int               `json:"broken_links_change"`
+}
+
+type SiteHealthPoint struct {
+	Date               time.Time `json:"date"`
+	SEOScore           int       `json:"seo_score"`
+	AccessibilityScore int       `json:"accessibility_score"`
+	BrokenLinks        int       `json:"broken_links"`
+	AltCoverage        int       `json:"alt_coverage"`
+}
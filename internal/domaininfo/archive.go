@@ -0,0 +1,119 @@
+package domaininfo
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/domain"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrRawResponseNotFound = errors.New("raw domain response not found")
+
+// ArchiveRepository guarda las respuestas crudas de WHOIS/RDAP de cada franquicia.
+type ArchiveRepository interface {
+	Save(ctx context.Context, res *domain.RawDomainResponse) error
+	GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID) ([]domain.RawDomainResponse, error)
+	GetLatest(ctx context.Context, franquiciaID primitive.ObjectID) (domain.RawDomainResponse, error)
+}
+
+type archiveRepository struct {
+	db *mongo.Collection
+}
+
+func NewArchiveRepository(db *mongo.Collection) ArchiveRepository {
+	return &archiveRepository{
+		db: db,
+	}
+}
+
+func (r *archiveRepository) Save(ctx context.Context, res *domain.RawDomainResponse) error {
+	_, err := r.db.InsertOne(ctx, res)
+	return err
+}
+
+// GetByFranquicia devuelve las respuestas de la franquicia, la más reciente primero.
+func (r *archiveRepository) GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID) ([]domain.RawDomainResponse, error) {
+	var responses []domain.RawDomainResponse
+	opts := options.Find().SetSort(bson.M{"fetched_at": -1})
+	cursor, err := r.db.Find(ctx, bson.M{"franquicia_id": franquiciaID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var res domain.RawDomainResponse
+		if err := cursor.Decode(&res); err != nil {
+			return nil, err
+		}
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
+func (r *archiveRepository) GetLatest(ctx context.Context, franquiciaID primitive.ObjectID) (domain.RawDomainResponse, error) {
+	var res domain.RawDomainResponse
+	opts := options.FindOne().SetSort(bson.M{"fetched_at": -1})
+	err := r.db.FindOne(ctx, bson.M{"franquicia_id": franquiciaID}, opts).Decode(&res)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return res, ErrRawResponseNotFound
+	}
+	return res, err
+}
+
+// NewRawResponse comprime una respuesta cruda para archivarla.
+func NewRawResponse(franquiciaID primitive.ObjectID, domainName string, resp Response) (*domain.RawDomainResponse, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(resp.Raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	res := &domain.RawDomainResponse{
+		ID:           primitive.NewObjectID(),
+		FranquiciaID: franquiciaID,
+		Domain:       domainName,
+		Source:       resp.Source,
+		Compression:  "gzip",
+		Size:         len(resp.Raw),
+		Data:         buf.Bytes(),
+		FetchedAt:    resp.FetchedAt,
+	}
+	if resp.Err != nil {
+		res.Error = resp.Err.Error()
+	}
+	return res, nil
+}
+
+// Decompress devuelve el contenido original de una respuesta archivada.
+func Decompress(res domain.RawDomainResponse) ([]byte, error) {
+	if res.Compression != "gzip" {
+		return res.Data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(res.Data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// Reparse vuelve a interpretar una respuesta archivada.
+func Reparse(res domain.RawDomainResponse) (*Record, error) {
+	raw, err := Decompress(res)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(res.Source, res.Domain, raw)
+}
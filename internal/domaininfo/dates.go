@@ -0,0 +1,208 @@
+package domaininfo
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownDateFormat = errors.New("unknown registry date format")
+	ErrAmbiguousDate     = errors.New("ambiguous registry date")
+)
+
+// dateOrder indica el orden de día y mes en los formatos numéricos con / o -, que los
+// registros escriben de las dos maneras.
+type dateOrder int
+
+const (
+	anyOrder dateOrder = iota
+	dayFirst
+	monthFirst
+)
+
+// registryLayout es un formato de fecha usado por algún registro. Name es lo que se
+// guarda como formato visto; los layouts sin zona horaria se interpretan en UTC salvo
+// que la fecha traiga una abreviatura conocida.
+type registryLayout struct {
+	Name   string
+	Layout string
+	Order  dateOrder
+}
+
+// Orden: primero los formatos con zona horaria explícita, después los ambiguos. Los
+// formatos con puntos (DD.MM.YYYY) siempre son día primero.
+var registryLayouts = []registryLayout{
+	{"RFC3339", time.RFC3339Nano, anyOrder},
+	{"ISO8601 sin separador de zona", "2006-01-02T15:04:05.999999999Z0700", anyOrder},
+	{"ISO8601 sin zona", "2006-01-02T15:04:05.999999999", anyOrder},
+	{"YYYY-MM-DD hh:mm:ss ±hh:mm", "2006-01-02 15:04:05.999999999 -07:00", anyOrder},
+	{"YYYY-MM-DD hh:mm:ss±hh:mm", "2006-01-02 15:04:05.999999999-07:00", anyOrder},
+	{"YYYY-MM-DD hh:mm:ss", "2006-01-02 15:04:05.999999999", anyOrder},
+	{"YYYY-MM-DD", "2006-01-02", anyOrder},
+	{"YYYY/MM/DD hh:mm:ss", "2006/01/02 15:04:05", anyOrder},
+	{"YYYY/MM/DD", "2006/01/02", anyOrder},
+	{"YYYY.MM.DD hh:mm:ss", "2006.01.02 15:04:05", anyOrder},
+	{"YYYY.MM.DD", "2006.01.02", anyOrder},
+	{"YYYY. MM. DD.", "2006. 01. 02.", anyOrder},
+	{"DD-Mon-YYYY hh:mm:ss", "02-Jan-2006 15:04:05", anyOrder},
+	{"DD-Mon-YYYY", "02-Jan-2006", anyOrder},
+	{"DD-Mon-YY", "02-Jan-06", anyOrder},
+	{"DD Mon YYYY", "02 Jan 2006", anyOrder},
+	{"DD.MM.YYYY hh:mm:ss", "2.1.2006 15:04:05", anyOrder},
+	{"DD.MM.YYYY", "2.1.2006", anyOrder},
+	{"DD/MM/YYYY hh:mm:ss", "02/01/2006 15:04:05", dayFirst},
+	{"DD/MM/YYYY", "02/01/2006", dayFirst},
+	{"DD-MM-YYYY", "02-01-2006", dayFirst},
+	{"MM/DD/YYYY hh:mm:ss", "01/02/2006 15:04:05", monthFirst},
+	{"MM/DD/YYYY", "01/02/2006", monthFirst},
+	{"MM-DD-YYYY", "01-02-2006", monthFirst},
+	{"YYYYMMDD", "20060102", anyOrder},
+	{"Month DD YYYY", "January 2 2006", anyOrder},
+	{"Weekday Mon DD hh:mm:ss YYYY", "Mon Jan 2 15:04:05 2006", anyOrder},
+	{"Weekday Mon DD hh:mm:ss TZ YYYY", time.UnixDate, anyOrder},
+	{"Mon-YYYY", "Jan-2006", anyOrder},
+}
+
+// zoneOffsets son las abreviaturas que publican los registros en lugar de un desplazamiento.
+// Las que significan cosas distintas según el país (CST, IST, BST) van en registryConventions.
+var zoneOffsets = map[string]int{
+	"UTC":  0,
+	"GMT":  0,
+	"WET":  0,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+	"MSK":  3 * 3600,
+	"HKT":  8 * 3600,
+	"SGT":  8 * 3600,
+	"AWST": 8 * 3600,
+	"JST":  9 * 3600,
+	"KST":  9 * 3600,
+	"AEST": 10 * 3600,
+	"AEDT": 11 * 3600,
+	"NZST": 12 * 3600,
+	"ART":  -3 * 3600,
+	"BRT":  -3 * 3600,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+}
+
+// registryConvention es lo que un registro concreto publica de forma ambigua: el orden de
+// día y mes en las fechas numéricas, las abreviaturas de zona locales y la zona de las
+// fechas que no traen ninguna (nil es UTC).
+type registryConvention struct {
+	Order dateOrder
+	Zones map[string]int
+	Local *time.Location
+}
+
+// registryConventions, por TLD. Una fecha DD/MM que también se puede leer como MM/DD solo
+// se acepta si el registro del dominio tiene un orden conocido.
+var registryConventions = map[string]registryConvention{
+	"cn": {Zones: map[string]int{"CST": 8 * 3600}, Local: time.FixedZone("CST", 8*3600)},
+	"tw": {Zones: map[string]int{"CST": 8 * 3600}},
+	"in": {Order: dayFirst, Zones: map[string]int{"IST": 5*3600 + 1800}},
+	"ie": {Order: dayFirst, Zones: map[string]int{"IST": 1 * 3600}},
+	"il": {Order: dayFirst, Zones: map[string]int{"IST": 2 * 3600}},
+	"uk": {Order: dayFirst, Zones: map[string]int{"BST": 1 * 3600}},
+	"us": {Order: monthFirst, Zones: map[string]int{"CST": -6 * 3600, "CDT": -5 * 3600}},
+	"ar": {Order: dayFirst},
+	"br": {Order: dayFirst},
+	"cl": {Order: dayFirst},
+	"es": {Order: dayFirst},
+	"fr": {Order: dayFirst},
+	"it": {Order: dayFirst},
+	"mx": {Order: dayFirst},
+	"pt": {Order: dayFirst},
+	"tr": {Order: dayFirst},
+}
+
+var (
+	// "2001/02/03 12:00:00 (JST)", "2020-01-01 10:00:00 CET"
+	trailingZone = regexp.MustCompile(`(?:\s+|\s*\()([A-Z]{2,4})\)?$`)
+	// "19990101 #1234" (.br), "2020-01-01 (changed)", "2005-10-27 (YYYY-MM-DD)" (.tw)
+	trailingComment = regexp.MustCompile(`\s+(#.*|\([a-z ]+\)|\((?:[A-Z]+-)+[A-Z]+\))$`)
+	spaces          = regexp.MustCompile(`\s+`)
+)
+
+// ParseRegistryDate normaliza una fecha de WHOIS/RDAP a UTC y devuelve el nombre del
+// formato reconocido. domainName elige las convenciones del registro (zonas locales y
+// orden día/mes); con un dominio vacío solo se aceptan fechas sin ambigüedad.
+func ParseRegistryDate(domainName, value string) (time.Time, string, error) {
+	s := spaces.ReplaceAllString(strings.TrimSpace(value), " ")
+	s = trailingComment.ReplaceAllString(s, "")
+	// Nominet publica "before Aug-1996" para los dominios más antiguos.
+	prefix := ""
+	if strings.HasPrefix(strings.ToLower(s), "before ") {
+		prefix = "before "
+		s = strings.TrimSpace(s[len("before "):])
+	}
+	if s == "" {
+		return time.Time{}, "", ErrUnknownDateFormat
+	}
+
+	conv := registryConventions[tldOf(domainName)]
+	loc := time.UTC
+	if conv.Local != nil {
+		loc = conv.Local
+	}
+	zone := ""
+	if m := trailingZone.FindStringSubmatch(s); m != nil {
+		offset, ok := zoneOffsets[m[1]]
+		if !ok {
+			offset, ok = conv.Zones[m[1]]
+		}
+		if ok {
+			loc = time.FixedZone(m[1], offset)
+			zone = " " + m[1]
+			s = strings.TrimSpace(s[:len(s)-len(m[0])])
+		}
+	}
+
+	ambiguous := false
+	for _, l := range registryLayouts {
+		t, err := time.ParseInLocation(l.Layout, s, loc)
+		if err != nil {
+			continue
+		}
+		// 03/04/2020 se lee igual día primero que mes primero.
+		if l.Order != anyOrder && t.Day() <= 12 && t.Day() != int(t.Month()) && l.Order != conv.Order {
+			ambiguous = true
+			continue
+		}
+		return t.UTC(), prefix + l.Name + zone, nil
+	}
+	if ambiguous {
+		return time.Time{}, "", fmt.Errorf("%w: %q", ErrAmbiguousDate, value)
+	}
+	return time.Time{}, "", ErrUnknownDateFormat
+}
+
+func tldOf(domainName string) string {
+	name := strings.ToLower(strings.Trim(domainName, "."))
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// normalizeDate interpreta la fecha cruda. Una fecha ausente o ilegible queda en nil;
+// el valor original y el formato reconocido se conservan.
+func normalizeDate(domainName, value string) (*time.Time, *domain.RawDate) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	raw := &domain.RawDate{Value: value}
+	t, format, err := ParseRegistryDate(domainName, value)
+	if err != nil {
+		return nil, raw
+	}
+	raw.Format = format
+	return &t, raw
+}
@@ -0,0 +1,129 @@
+package domaininfo
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseRegistryDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		domain     string
+		value      string
+		want       time.Time
+		wantFormat string
+		wantErr    error
+	}{
+		{name: "rfc3339", domain: "example.com", value: "2020-01-02T03:04:05Z", want: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), wantFormat: "RFC3339"},
+		{name: "offset without colon", domain: "example.com", value: "1997-09-15T00:00:00-0700", want: time.Date(1997, 9, 15, 7, 0, 0, 0, time.UTC), wantFormat: "ISO8601 sin separador de zona"},
+		{name: "known zone", domain: "example.jp", value: "2001/02/03 12:00:00 (JST)", want: time.Date(2001, 2, 3, 3, 0, 0, 0, time.UTC), wantFormat: "YYYY/MM/DD hh:mm:ss JST"},
+		{name: "CST in China", domain: "example.cn", value: "2020-01-01 08:00:00 CST", want: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), wantFormat: "YYYY-MM-DD hh:mm:ss CST"},
+		{name: "CST in the US", domain: "example.us", value: "2020-01-01 08:00:00 CST", want: time.Date(2020, 1, 1, 14, 0, 0, 0, time.UTC), wantFormat: "YYYY-MM-DD hh:mm:ss CST"},
+		{name: "CST without registry", domain: "example.com", value: "2020-01-01 08:00:00 CST", wantErr: ErrUnknownDateFormat},
+		{name: "IST in India", domain: "example.in", value: "2020-01-01 05:30:00 IST", want: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), wantFormat: "YYYY-MM-DD hh:mm:ss IST"},
+		{name: "IST in Ireland", domain: "example.ie", value: "2020-06-01 01:00:00 IST", want: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), wantFormat: "YYYY-MM-DD hh:mm:ss IST"},
+		{name: "day first registry", domain: "example.com.ar", value: "03/04/2020", want: time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC), wantFormat: "DD/MM/YYYY"},
+		{name: "month first registry", domain: "example.us", value: "03/04/2020", want: time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC), wantFormat: "MM/DD/YYYY"},
+		{name: "ambiguous without registry", domain: "example.com", value: "03/04/2020", wantErr: ErrAmbiguousDate},
+		{name: "unambiguous day first", domain: "example.com", value: "25/04/2020", want: time.Date(2020, 4, 25, 0, 0, 0, 0, time.UTC), wantFormat: "DD/MM/YYYY"},
+		{name: "unambiguous month first", domain: "example.com", value: "04/25/2020", want: time.Date(2020, 4, 25, 0, 0, 0, 0, time.UTC), wantFormat: "MM/DD/YYYY"},
+		{name: "same day and month", domain: "example.com", value: "04/04/2020", want: time.Date(2020, 4, 4, 0, 0, 0, 0, time.UTC), wantFormat: "DD/MM/YYYY"},
+		{name: "dotted is day first", domain: "example.com", value: "03.04.2020", want: time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC), wantFormat: "DD.MM.YYYY"},
+		{name: "nominet before", domain: "example.co.uk", value: "before Aug-1996", want: time.Date(1996, 8, 1, 0, 0, 0, 0, time.UTC), wantFormat: "before Mon-YYYY"},
+		{name: "trailing comment", domain: "example.com.br", value: "19990101 #1234", want: time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), wantFormat: "YYYYMMDD"},
+		{name: "empty", domain: "example.com", value: "  ", wantErr: ErrUnknownDateFormat},
+		{name: "garbage", domain: "example.com", value: "not a date", wantErr: ErrUnknownDateFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, format, err := ParseRegistryDate(tt.domain, tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRegistryDate(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) || format != tt.wantFormat {
+				t.Errorf("got %v %q, want %v %q", got, format, tt.want, tt.wantFormat)
+			}
+		})
+	}
+}
+
+// TestWhoisDateCorpus interpreta respuestas WHOIS reales de testdata/whois. Una fecha
+// cero significa que el registro no publica ese dato.
+func TestWhoisDateCorpus(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	}
+	tests := []struct {
+		domain        string
+		created       time.Time
+		createdFormat string
+		expiry        time.Time
+		expiryFormat  string
+	}{
+		{"google.com", date(1997, 9, 15, 7, 0, 0), "ISO8601 sin separador de zona", date(2028, 9, 13, 7, 0, 0), "ISO8601 sin separador de zona"},
+		{"google.uk", date(2014, 6, 11, 0, 0, 0), "DD-Mon-YYYY", date(2020, 6, 11, 0, 0, 0), "DD-Mon-YYYY"},
+		{"google.co.uk", date(1996, 8, 1, 0, 0, 0), "before Mon-YYYY", date(2025, 2, 14, 0, 0, 0), "DD-Mon-YYYY"},
+		// El alta del dominio va antes que la fecha de alta del contacto.
+		{"google.com.ar", date(2001, 5, 8, 0, 0, 0), "YYYY-MM-DD hh:mm:ss", date(2024, 5, 8, 0, 0, 0), "YYYY-MM-DD hh:mm:ss"},
+		{"google.de", time.Time{}, "", time.Time{}, ""},
+		{"google.nl", time.Time{}, "", time.Time{}, ""},
+		{"google.com.au", time.Time{}, "", time.Time{}, ""},
+		{"google.jp", date(2005, 5, 30, 0, 0, 0), "YYYY/MM/DD", date(2018, 5, 31, 0, 0, 0), "YYYY/MM/DD"},
+		{"google.co.jp", date(2001, 3, 22, 0, 0, 0), "YYYY/MM/DD", time.Time{}, ""},
+		{"espm.br", date(1996, 12, 6, 0, 0, 0), "YYYYMMDD", time.Time{}, ""},
+		{"google.cn", date(2003, 3, 17, 4, 20, 5), "YYYY-MM-DD hh:mm:ss", date(2021, 3, 17, 4, 48, 36), "YYYY-MM-DD hh:mm:ss"},
+		{"google.fi", date(2006, 6, 30, 0, 0, 0), "DD.MM.YYYY hh:mm:ss", date(2020, 7, 4, 10, 15, 55), "DD.MM.YYYY hh:mm:ss"},
+		{"google.ee", date(2010, 7, 4, 1, 34, 46), "YYYY-MM-DD hh:mm:ss ±hh:mm", date(2021, 11, 9, 0, 0, 0), "YYYY-MM-DD"},
+		{"google.kr", date(2007, 3, 2, 0, 0, 0), "YYYY. MM. DD.", date(2020, 3, 2, 0, 0, 0), "YYYY. MM. DD."},
+		{"google.pl", date(2002, 9, 19, 13, 0, 0), "YYYY.MM.DD hh:mm:ss", date(2022, 9, 18, 14, 0, 0), "YYYY.MM.DD hh:mm:ss"},
+		{"google.tw", date(2005, 10, 27, 0, 0, 0), "YYYY-MM-DD", date(2020, 10, 31, 0, 0, 0), "YYYY-MM-DD"},
+		{"google.ro", date(2000, 7, 17, 0, 0, 0), "YYYY-MM-DD", date(2020, 9, 16, 0, 0, 0), "YYYY-MM-DD"},
+		{"google.fr", date(2000, 7, 26, 22, 0, 0), "RFC3339", date(2019, 12, 30, 17, 16, 48), "RFC3339"},
+		{"google.it", date(1999, 12, 10, 0, 0, 0), "YYYY-MM-DD hh:mm:ss", date(2020, 4, 21, 0, 0, 0), "YYYY-MM-DD"},
+		{"google.ru", date(2004, 3, 3, 21, 0, 0), "RFC3339", date(2020, 3, 4, 21, 0, 0), "RFC3339"},
+		{"google.se", date(2003, 8, 27, 0, 0, 0), "YYYY-MM-DD", date(2023, 10, 20, 0, 0, 0), "YYYY-MM-DD"},
+		{"google.in", date(2005, 2, 14, 20, 35, 14), "RFC3339", date(2020, 2, 14, 20, 35, 14), "RFC3339"},
+		{"google.us", date(2002, 4, 19, 23, 16, 1), "RFC3339", date(2020, 4, 18, 23, 59, 59), "RFC3339"},
+		{"nic.hu", date(1996, 6, 27, 13, 36, 21), "YYYY-MM-DD hh:mm:ss", time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", "whois", tt.domain+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec, err := parseWhoisResponse(tt.domain, string(raw))
+			if err != nil {
+				t.Fatalf("parseWhoisResponse: %v", err)
+			}
+			checkDate(t, "created", rec.Info.CreatedDate, rec.Info.CreatedDateRaw, tt.created, tt.createdFormat)
+			checkDate(t, "expiry", rec.Info.ExpiryDate, rec.Info.ExpiryDateRaw, tt.expiry, tt.expiryFormat)
+		})
+	}
+}
+
+func checkDate(t *testing.T, field string, got *time.Time, raw *domain.RawDate, want time.Time, wantFormat string) {
+	t.Helper()
+	if want.IsZero() {
+		if got != nil {
+			t.Errorf("%s = %v, want nil", field, got)
+		}
+		return
+	}
+	if got == nil || !got.Equal(want) {
+		t.Errorf("%s = %v, want %v", field, got, want)
+	}
+	if raw == nil || raw.Format != wantFormat {
+		t.Errorf("%s format = %+v, want %q", field, raw, wantFormat)
+	}
+}
@@ -0,0 +1,151 @@
+package domaininfo
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrDomainNotFound   = errors.New("domain not found")
+	ErrNotSupported     = errors.New("domain not supported by provider")
+	ErrNoProviderResult = errors.New("no domain info provider returned a result")
+)
+
+// Nombres de los proveedores, guardados como DomainInfo.Source.
+const (
+	SourceRDAP  = "rdap"
+	SourceWhois = "whois"
+)
+
+// Record es la respuesta de un proveedor normalizada al modelo de dominio. Raw es la
+// respuesta tal como la devolvió el servidor (texto WHOIS o JSON RDAP) y Responses todas
+// las respuestas crudas de la consulta, incluida esta.
+type Record struct {
+	Info      domain.DomainInfo
+	Location  domain.Location
+	Raw       []byte
+	Responses []Response
+}
+
+// Response es una respuesta cruda de un proveedor, se haya podido interpretar o no. Err
+// es el motivo por el que no dio un Record.
+type Response struct {
+	Source    string
+	Raw       []byte
+	FetchedAt time.Time
+	Err       error
+}
+
+// ResponseError es el error de una consulta que recibió respuestas del servidor (un "no
+// encontrado", un JSON inválido, ...), para poder archivarlas igual.
+type ResponseError struct {
+	Responses []Response
+	Err       error
+}
+
+func (e *ResponseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Responses devuelve las respuestas crudas de una consulta, haya salido bien o no.
+func Responses(rec *Record, err error) []Response {
+	if rec != nil {
+		return rec.Responses
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.Responses
+	}
+	return nil
+}
+
+// newResponse arma el resultado de un proveedor que recibió raw: el Record con su
+// respuesta cruda o un ResponseError con ella.
+func newResponse(source string, raw []byte, fetchedAt time.Time, rec *Record, err error) (*Record, error) {
+	res := Response{Source: source, Raw: raw, FetchedAt: fetchedAt, Err: err}
+	if err != nil {
+		return nil, &ResponseError{Responses: []Response{res}, Err: err}
+	}
+	rec.Responses = []Response{res}
+	return rec, nil
+}
+
+// DomainInfoProvider obtiene los datos de registro de un dominio (WHOIS, RDAP, ...).
+type DomainInfoProvider interface {
+	Name() string
+	Lookup(ctx context.Context, domainName string) (*Record, error)
+}
+
+type chain struct {
+	providers []DomainInfoProvider
+}
+
+// NewChain prueba los proveedores en orden y devuelve el primer resultado exitoso. Un
+// ErrDomainNotFound es una respuesta autoritativa (el registro no tiene el dominio) y corta
+// la cadena: los demás proveedores consultarían el mismo registro.
+func NewChain(providers ...DomainInfoProvider) DomainInfoProvider {
+	return &chain{providers: providers}
+}
+
+func (c *chain) Name() string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Lookup junta las respuestas crudas de todos los proveedores consultados, en orden, en
+// el Record o en el ResponseError que devuelve.
+func (c *chain) Lookup(ctx context.Context, domainName string) (*Record, error) {
+	var errs []error
+	var responses []Response
+	for _, p := range c.providers {
+		rec, err := p.Lookup(ctx, domainName)
+		responses = append(responses, Responses(rec, err)...)
+		if err == nil {
+			rec.Responses = responses
+			return rec, nil
+		}
+		if ctx.Err() != nil {
+			return nil, &ResponseError{Responses: responses, Err: ctx.Err()}
+		}
+		if errors.Is(err, ErrDomainNotFound) {
+			return nil, &ResponseError{Responses: responses, Err: err}
+		}
+		log.Printf("Proveedor %s falló para %s: %v", p.Name(), domainName, err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+	err := fmt.Errorf("%w: %w", ErrNoProviderResult, errors.Join(errs...))
+	return nil, &ResponseError{Responses: responses, Err: err}
+}
+
+// Parse vuelve a interpretar una respuesta cruda guardada, sin consultar la red.
+func Parse(source, domainName string, raw []byte) (*Record, error) {
+	switch source {
+	case SourceRDAP:
+		return parseRDAPResponse(domainName, raw)
+	case SourceWhois:
+		return parseWhoisResponse(domainName, string(raw))
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", ErrNotSupported, source)
+	}
+}
+
+// normalize completa los campos derivados comunes a todos los proveedores.
+func normalize(rec *Record, source string) *Record {
+	rec.Info.Source = source
+	if rec.Info.RegistrarName == "" {
+		rec.Info.RegistrarName = rec.Info.RegistrarInfo.Organization
+	}
+	return rec
+}
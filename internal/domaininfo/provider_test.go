@@ -0,0 +1,215 @@
+package domaininfo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const rdapDomainJSON = `{
+	"ldhName": "EXAMPLE.COM",
+	"events": [
+		{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+		{"eventAction": "expiration", "eventDate": "2030-08-13T04:00:00Z"}
+	],
+	"entities": [
+		{
+			"roles": ["registrar"],
+			"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]
+		},
+		{
+			"roles": ["administrative"],
+			"vcardArray": ["vcard", [
+				["fn", {}, "text", "Jane Doe"],
+				["email", {}, "text", "admin@example.com"],
+				["adr", {}, "text", ["", "", "1 Main St", "Los Angeles", "CA", "90001", "US"]]
+			]]
+		}
+	]
+}`
+
+const whoisDomainText = `Domain Name: EXAMPLE.COM
+Registrar: Example Registrar, Inc.
+Creation Date: 1995-08-14T04:00:00Z
+Registry Expiry Date: 2030-08-13T04:00:00Z
+Admin Name: Jane Doe
+Admin City: Los Angeles
+Admin Country: US
+Admin Email: admin@example.com
+`
+
+// newRDAPServer levanta un servidor RDAP con su bootstrap: example.com existe, broken.com
+// devuelve un JSON inválido y cualquier otro dominio .com responde 404.
+func newRDAPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	mux.HandleFunc("/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"services": [[["com"], ["%s/rdap/"]]]}`, srv.URL)
+	})
+	mux.HandleFunc("/rdap/domain/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rdap+json")
+		switch strings.TrimPrefix(r.URL.Path, "/rdap/domain/") {
+		case "example.com":
+			fmt.Fprint(w, rdapDomainJSON)
+		case "broken.com":
+			fmt.Fprint(w, `{"ldhName":`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorCode": 404, "title": "Not Found"}`)
+		}
+	})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newWhoisServer levanta un servidor WHOIS por TCP que responde example.com y example.net
+// y "No match" para el resto. Devuelve la dirección y un contador de consultas.
+func newWhoisServer(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	queries := new(atomic.Int32)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			queries.Add(1)
+			query, _ := bufio.NewReader(conn).ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(query)) {
+			case "example.com", "example.net":
+				fmt.Fprint(conn, whoisDomainText)
+			default:
+				fmt.Fprintf(conn, "No match for %q.\n", strings.TrimSpace(query))
+			}
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String(), queries
+}
+
+func TestRDAPProvider(t *testing.T) {
+	srv := newRDAPServer(t)
+	p := NewRDAPProvider(srv.URL + "/dns.json")
+	ctx := context.Background()
+
+	rec, err := p.Lookup(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if rec.Info.Source != SourceRDAP {
+		t.Errorf("Source = %q, want %q", rec.Info.Source, SourceRDAP)
+	}
+	if rec.Info.RegistrarInfo.Organization != "Example Registrar, Inc." {
+		t.Errorf("registrar = %q", rec.Info.RegistrarInfo.Organization)
+	}
+	if rec.Info.CreatedDate == nil || !rec.Info.CreatedDate.Equal(time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedDate = %v", rec.Info.CreatedDate)
+	}
+	if rec.Location.City != "Los Angeles" || rec.Location.Country != "US" {
+		t.Errorf("Location = %+v", rec.Location)
+	}
+	if len(rec.Raw) == 0 {
+		t.Error("Raw is empty")
+	}
+
+	if _, err := p.Lookup(ctx, "missing.com"); !errors.Is(err, ErrDomainNotFound) {
+		t.Errorf("missing.com: err = %v, want ErrDomainNotFound", err)
+	}
+	if _, err := p.Lookup(ctx, "example.org"); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("example.org: err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestWhoisProvider(t *testing.T) {
+	addr, _ := newWhoisServer(t)
+	p := NewWhoisProvider(addr)
+	ctx := context.Background()
+
+	rec, err := p.Lookup(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if rec.Info.Source != SourceWhois {
+		t.Errorf("Source = %q, want %q", rec.Info.Source, SourceWhois)
+	}
+	if rec.Info.ExpiryDate == nil || !rec.Info.ExpiryDate.Equal(time.Date(2030, 8, 13, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("ExpiryDate = %v", rec.Info.ExpiryDate)
+	}
+	if rec.Info.ContactEmail != "admin@example.com" {
+		t.Errorf("ContactEmail = %q", rec.Info.ContactEmail)
+	}
+
+	if _, err := p.Lookup(ctx, "missing.com"); !errors.Is(err, ErrDomainNotFound) {
+		t.Errorf("missing.com: err = %v, want ErrDomainNotFound", err)
+	}
+}
+
+func TestChainLookup(t *testing.T) {
+	srv := newRDAPServer(t)
+	addr, queries := newWhoisServer(t)
+	p := NewChain(NewRDAPProvider(srv.URL+"/dns.json"), NewWhoisProvider(addr))
+	ctx := context.Background()
+
+	// wantResponses son las fuentes de las respuestas crudas que devuelve la consulta.
+	tests := []struct {
+		name          string
+		domain        string
+		wantSource    string
+		wantErr       error
+		wantQueries   int
+		wantResponses []string
+	}{
+		{name: "rdap answers", domain: "example.com", wantSource: SourceRDAP, wantResponses: []string{SourceRDAP}},
+		{name: "rdap 404 stops the chain", domain: "missing.com", wantErr: ErrDomainNotFound, wantResponses: []string{SourceRDAP}},
+		{name: "falls back to whois", domain: "example.net", wantSource: SourceWhois, wantQueries: 1, wantResponses: []string{SourceWhois}},
+		{name: "whois not found", domain: "missing.net", wantErr: ErrDomainNotFound, wantQueries: 1, wantResponses: []string{SourceWhois}},
+		{name: "invalid rdap is kept", domain: "broken.com", wantErr: ErrDomainNotFound, wantQueries: 1, wantResponses: []string{SourceRDAP, SourceWhois}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries.Store(0)
+			rec, err := p.Lookup(ctx, tt.domain)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Lookup: %v", err)
+			} else if rec.Info.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", rec.Info.Source, tt.wantSource)
+			}
+			if got := queries.Load(); int(got) != tt.wantQueries {
+				t.Errorf("whois queries = %d, want %d", got, tt.wantQueries)
+			}
+			responses := Responses(rec, err)
+			var sources []string
+			for _, res := range responses {
+				if len(res.Raw) == 0 || res.FetchedAt.IsZero() {
+					t.Errorf("%s response without body or timestamp", res.Source)
+				}
+				sources = append(sources, res.Source)
+			}
+			if strings.Join(sources, ",") != strings.Join(tt.wantResponses, ",") {
+				t.Errorf("responses = %v, want %v", sources, tt.wantResponses)
+			}
+			if tt.wantErr != nil && responses[len(responses)-1].Err == nil {
+				t.Error("failed response without error")
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package domaininfo
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+	bootstrapTTL            = 24 * time.Hour
+	maxRDAPResponseSize     = 2 << 20
+)
+
+type rdapProvider struct {
+	bootstrapURL string
+	client       *http.Client
+
+	mu        sync.Mutex
+	services  map[string]string
+	fetchedAt time.Time
+}
+
+// NewRDAPProvider consulta RDAP (RFC 9083). El servidor de cada TLD se obtiene del
+// archivo bootstrap de IANA (RFC 9224), que se cachea 24 horas.
+func NewRDAPProvider(bootstrapURL string) DomainInfoProvider {
+	if bootstrapURL == "" {
+		bootstrapURL = DefaultRDAPBootstrapURL
+	}
+	return &rdapProvider{
+		bootstrapURL: bootstrapURL,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *rdapProvider) Name() string {
+	return SourceRDAP
+}
+
+func (p *rdapProvider) Lookup(ctx context.Context, domainName string) (*Record, error) {
+	base, err := p.serverFor(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, status, err := p.get(ctx, base+"domain/"+domainName)
+	fetchedAt := time.Now().UTC()
+	if status == http.StatusNotFound {
+		err = fmt.Errorf("%w: %s", ErrDomainNotFound, domainName)
+		return newResponse(SourceRDAP, raw, fetchedAt, nil, err)
+	}
+	if err != nil {
+		if status == 0 {
+			return nil, err
+		}
+		return newResponse(SourceRDAP, raw, fetchedAt, nil, err)
+	}
+	rec, err := parseRDAPResponse(domainName, raw)
+	return newResponse(SourceRDAP, raw, fetchedAt, rec, err)
+}
+
+func parseRDAPResponse(domainName string, raw []byte) (*Record, error) {
+	var resp rdapDomain
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("invalid RDAP response: %w", err)
+	}
+	rec := parseRDAP(domainName, resp)
+	rec.Raw = raw
+	return rec, nil
+}
+
+// serverFor busca el TLD más específico del dominio en el bootstrap (p. ej. "com.ar" antes que "ar").
+func (p *rdapProvider) serverFor(ctx context.Context, domainName string) (string, error) {
+	services, err := p.bootstrap(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	labels := strings.Split(strings.ToLower(strings.Trim(domainName, ".")), ".")
+	for i := 1; i < len(labels); i++ {
+		if base, ok := services[strings.Join(labels[i:], ".")]; ok {
+			return base, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no RDAP service for %s", ErrNotSupported, domainName)
+}
+
+func (p *rdapProvider) bootstrap(ctx context.Context) (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.services != nil && time.Since(p.fetchedAt) < bootstrapTTL {
+		return p.services, nil
+	}
+
+	var file struct {
+		Services [][][]string `json:"services"`
+	}
+	raw, _, err := p.get(ctx, p.bootstrapURL)
+	if err == nil {
+		err = json.Unmarshal(raw, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rdap bootstrap: %w", err)
+	}
+
+	services := map[string]string{}
+	for _, svc := range file.Services {
+		if len(svc) < 2 || len(svc[1]) == 0 {
+			continue
+		}
+		base := preferHTTPS(svc[1])
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		for _, tld := range svc[0] {
+			services[strings.ToLower(tld)] = base
+		}
+	}
+
+	p.services = services
+	p.fetchedAt = time.Now()
+	return services, nil
+}
+
+func (p *rdapProvider) get(ctx context.Context, url string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	// El cuerpo de una respuesta de error también se devuelve, para archivarlo.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRDAPResponseSize))
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return body, resp.StatusCode, err
+}
+
+func preferHTTPS(urls []string) string {
+	for _, u := range urls {
+		if strings.HasPrefix(u, "https://") {
+			return u
+		}
+	}
+	return urls[0]
+}
+
+type rdapDomain struct {
+	LDHName  string       `json:"ldhName"`
+	Events   []rdapEvent  `json:"events"`
+	Entities []rdapEntity `json:"entities"`
+}
+
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+type rdapEntity struct {
+	Roles      []string        `json:"roles"`
+	VCardArray json.RawMessage `json:"vcardArray"`
+	Entities   []rdapEntity    `json:"entities"`
+}
+
+// vcard es el subconjunto de jCard (RFC 7095) que se usa para los contactos.
+type vcard struct {
+	Name         string
+	Organization string
+	Street       string
+	City         string
+	State        string
+	PostalCode   string
+	Country      string
+	Phone        string
+	Fax          string
+	Email        string
+}
+
+func parseRDAP(domainName string, resp rdapDomain) *Record {
+	rec := &Record{}
+	for _, evt := range resp.Events {
+		switch evt.Action {
+		case "registration":
+			rec.Info.CreatedDate, rec.Info.CreatedDateRaw = normalizeDate(domainName, evt.Date)
+		case "expiration":
+			rec.Info.ExpiryDate, rec.Info.ExpiryDateRaw = normalizeDate(domainName, evt.Date)
+		}
+	}
+
+	contacts := map[string]vcard{}
+	collectContacts(resp.Entities, contacts)
+
+	if c, ok := contacts["registrar"]; ok {
+		rec.Info.RegistrarInfo = domain.RegistrarInfo{
+			Organization: firstNonEmpty(c.Organization, c.Name),
+			Address:      c.Street,
+			City:         c.City,
+			State:        c.State,
+			PostalCode:   c.PostalCode,
+			Country:      c.Country,
+			Phone:        c.Phone,
+			Fax:          c.Fax,
+			Email:        firstNonEmpty(c.Email, contacts["abuse"].Email),
+		}
+	}
+	if c, ok := contacts["technical"]; ok {
+		rec.Info.TechnicalInfo = domain.TechnicalInfo{
+			Organization: c.Organization,
+			Address:      c.Street,
+			City:         c.City,
+			State:        c.State,
+			PostalCode:   c.PostalCode,
+			Country:      c.Country,
+			Phone:        c.Phone,
+			Fax:          c.Fax,
+			Email:        c.Email,
+		}
+	}
+
+	// Igual que en WHOIS, el contacto administrativo da el nombre y la ubicación;
+	// muchos registros lo ocultan, así que se usa el titular como respaldo.
+	admin, ok := contacts["administrative"]
+	if !ok {
+		admin = contacts["registrant"]
+	}
+	rec.Info.RegistrarName = firstNonEmpty(admin.Name, admin.Organization)
+	rec.Info.ContactEmail = admin.Email
+	rec.Location = domain.Location{
+		City:    admin.City,
+		Country: admin.Country,
+		Address: admin.Street,
+		ZipCode: admin.PostalCode,
+	}
+
+	return normalize(rec, SourceRDAP)
+}
+
+// collectContacts recorre las entidades (incluidas las anidadas) y guarda el primer
+// contacto de cada rol.
+func collectContacts(entities []rdapEntity, contacts map[string]vcard) {
+	for _, e := range entities {
+		card := parseVCard(e.VCardArray)
+		for _, role := range e.Roles {
+			if _, ok := contacts[role]; !ok {
+				contacts[role] = card
+			}
+		}
+		collectContacts(e.Entities, contacts)
+	}
+}
+
+func parseVCard(raw json.RawMessage) vcard {
+	var card vcard
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err != nil || len(arr) < 2 {
+		return card
+	}
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(arr[1], &props); err != nil {
+		return card
+	}
+
+	for _, prop := range props {
+		if len(prop) < 4 {
+			continue
+		}
+		var name string
+		var params map[string]interface{}
+		json.Unmarshal(prop[0], &name)
+		json.Unmarshal(prop[1], &params)
+
+		switch name {
+		case "fn":
+			card.Name = jsonString(prop[3])
+		case "org":
+			card.Organization = jsonString(prop[3])
+		case "email":
+			card.Email = jsonString(prop[3])
+		case "tel":
+			tel := strings.TrimPrefix(jsonString(prop[3]), "tel:")
+			if strings.Contains(fmt.Sprint(params["type"]), "fax") {
+				card.Fax = tel
+			} else if card.Phone == "" {
+				card.Phone = tel
+			}
+		case "adr":
+			// [pobox, ext, street, locality, region, code, country]
+			var adr []json.RawMessage
+			if err := json.Unmarshal(prop[3], &adr); err == nil && len(adr) >= 7 {
+				card.Street = jsonString(adr[2])
+				card.City = jsonString(adr[3])
+				card.State = jsonString(adr[4])
+				card.PostalCode = jsonString(adr[5])
+				card.Country = jsonString(adr[6])
+			}
+			if label, ok := params["label"].(string); ok && card.Street == "" {
+				card.Street = label
+			}
+			if cc, ok := params["cc"].(string); ok && card.Country == "" {
+				card.Country = cc
+			}
+		}
+	}
+	return card
+}
+
+// jsonString acepta un string o una lista de strings (p. ej. calles en varias líneas).
+func jsonString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return strings.Join(strings.Fields(strings.Join(list, " ")), " ")
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,206 @@
+package domaininfo
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/likexian/whois"
+	whoisparser "github.com/likexian/whois-parser"
+)
+
+const whoisTimeout = 15 * time.Second
+
+type whoisProvider struct {
+	server string
+	// addr fija la dirección de todas las conexiones, sin seguir referencias.
+	addr string
+}
+
+// NewWhoisProvider consulta WHOIS por el puerto 43. Con server vacío el servidor se
+// descubre por referencia desde whois.iana.org; con "host:puerto" todas las consultas
+// van a esa dirección (útil para servidores locales de prueba).
+func NewWhoisProvider(server string) DomainInfoProvider {
+	p := &whoisProvider{server: server}
+	if host, port, err := net.SplitHostPort(server); err == nil {
+		p.addr = net.JoinHostPort(host, port)
+		p.server = host
+	}
+	return p
+}
+
+// newClient arma un cliente por consulta: la librería no recibe contexto, así que el
+// dialer corta la conexión cuando se cancela ctx o vence su plazo.
+func (p *whoisProvider) newClient(ctx context.Context) *whois.Client {
+	client := whois.NewClient().SetTimeout(whoisTimeout)
+	client.SetDialer(&contextDialer{ctx: ctx, addr: p.addr, timeout: whoisTimeout})
+	if p.addr != "" {
+		client.SetDisableReferral(true)
+	}
+	return client
+}
+
+func (p *whoisProvider) Name() string {
+	return SourceWhois
+}
+
+func (p *whoisProvider) Lookup(ctx context.Context, domainName string) (*Record, error) {
+	raw, err := p.newClient(ctx).Whois(domainName, p.server)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	fetchedAt := time.Now().UTC()
+
+	rec, err := parseWhoisResponse(domainName, raw)
+	if errors.Is(err, ErrDomainNotFound) {
+		err = fmt.Errorf("%w: %s", ErrDomainNotFound, domainName)
+	}
+	return newResponse(SourceWhois, []byte(raw), fetchedAt, rec, err)
+}
+
+func parseWhoisResponse(domainName, raw string) (*Record, error) {
+	parsed, err := whoisparser.Parse(raw)
+	if errors.Is(err, whoisparser.ErrNotFoundDomain) {
+		return nil, ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec, err := parseWhois(domainName, raw, parsed)
+	if err != nil {
+		return nil, err
+	}
+	rec.Raw = []byte(raw)
+	return rec, nil
+}
+
+func parseWhois(domainName, raw string, parsed whoisparser.WhoisInfo) (*Record, error) {
+	rec := &Record{}
+	var created, expiry string
+	if parsed.Domain != nil {
+		created, expiry = parsed.Domain.CreatedDate, parsed.Domain.ExpirationDate
+	}
+	// El parser no reconoce las etiquetas de varios ccTLD (.uk, .jp, ...).
+	if created == "" {
+		created = findRawField(raw, createdDateKeys)
+	}
+	if expiry == "" {
+		expiry = findRawField(raw, expiryDateKeys)
+	}
+	rec.Info.CreatedDate, rec.Info.CreatedDateRaw = normalizeDate(domainName, created)
+	rec.Info.ExpiryDate, rec.Info.ExpiryDateRaw = normalizeDate(domainName, expiry)
+	logUnparsedDate(created, rec.Info.CreatedDateRaw)
+	logUnparsedDate(expiry, rec.Info.ExpiryDateRaw)
+
+	if c := parsed.Registrar; c != nil {
+		rec.Info.RegistrarInfo = domain.RegistrarInfo{
+			Organization: c.Name,
+			Address:      c.Street,
+			City:         c.City,
+			State:        c.Province,
+			PostalCode:   c.PostalCode,
+			Country:      c.Country,
+			Phone:        c.Phone,
+			Fax:          c.Fax,
+			Email:        c.Email,
+		}
+	}
+	if c := parsed.Technical; c != nil {
+		rec.Info.TechnicalInfo = domain.TechnicalInfo{
+			Organization: c.Organization,
+			Address:      c.Street,
+			City:         c.City,
+			State:        c.Province,
+			PostalCode:   c.PostalCode,
+			Country:      c.Country,
+			Phone:        c.Phone,
+			Fax:          c.Fax,
+			Email:        c.Email,
+		}
+	}
+	if c := parsed.Administrative; c != nil {
+		rec.Info.RegistrarName = c.Name
+		rec.Info.ContactEmail = c.Email
+		rec.Location = domain.Location{
+			City:    c.City,
+			Country: c.Country,
+			Address: c.Street,
+			ZipCode: c.PostalCode,
+		}
+	}
+
+	return normalize(rec, SourceWhois), nil
+}
+
+var (
+	createdDateKeys = []string{"registered on", "created on", "[created on]", "[登録年月日]", "created", "registered", "registration date", "domain record activated", "creation date"}
+	expiryDateKeys  = []string{"expiry date", "expires on", "[expires on]", "[有効期限]", "expires", "expire", "expiration date", "renewal date", "paid-till", "domain expires"}
+)
+
+// findRawField busca la primera línea "clave: valor" (o "[clave] valor" en .jp) del texto WHOIS.
+func findRawField(raw string, keys []string) string {
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		for _, key := range keys {
+			if !strings.HasPrefix(lower, key) {
+				continue
+			}
+			value := strings.TrimSpace(line[len(key):])
+			if strings.HasPrefix(value, ":") {
+				value = strings.TrimSpace(value[1:])
+			} else if !strings.HasSuffix(key, "]") {
+				continue
+			}
+			if value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func logUnparsedDate(value string, raw *domain.RawDate) {
+	if raw != nil && raw.Format == "" {
+		log.Printf("Fecha WHOIS con formato desconocido: %q", value)
+	}
+}
+
+// contextDialer cierra las conexiones cuando se cancela ctx. Con addr no vacío envía todas
+// las conexiones a esa dirección.
+type contextDialer struct {
+	ctx     context.Context
+	addr    string
+	timeout time.Duration
+}
+
+func (d *contextDialer) Dial(network, addr string) (net.Conn, error) {
+	if d.addr != "" {
+		addr = d.addr
+	}
+	dialer := net.Dialer{Timeout: d.timeout}
+	conn, err := dialer.DialContext(d.ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(d.ctx, func() { conn.Close() })
+	return &contextConn{Conn: conn, stop: stop}, nil
+}
+
+type contextConn struct {
+	net.Conn
+	stop func() bool
+}
+
+func (c *contextConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
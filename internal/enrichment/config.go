@@ -0,0 +1,36 @@
+package enrichment
+
+import (
+	"clubhub-hotel-management/internal/env"
+	"time"
+)
+
+// Config define los reintentos y el plazo por defecto de los enrichers. Los reintentos son
+// de cada enricher y cubren también las fuentes que no pasan por el crawler, como RDAP,
+// WHOIS y las consultas DNS.
+type Config struct {
+	// Retries son los intentos extra ante un error que no es permanente; la espera entre
+	// intentos arranca en RetryBackoff y se duplica.
+	Retries      int
+	RetryBackoff time.Duration
+	// Timeout limita los enrichers que no declaran un plazo propio. El plazo cubre todos
+	// los intentos y las esperas entre ellos.
+	Timeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Retries:      2,
+		RetryBackoff: 500 * time.Millisecond,
+		Timeout:      30 * time.Second,
+	}
+}
+
+// ConfigFromEnv parte de DefaultConfig y aplica las variables ENRICHMENT_*.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	env.Int("ENRICHMENT_RETRIES", &cfg.Retries)
+	env.Duration("ENRICHMENT_RETRY_BACKOFF", &cfg.RetryBackoff)
+	env.Duration("ENRICHMENT_TIMEOUT", &cfg.Timeout)
+	return cfg
+}
@@ -0,0 +1,55 @@
+package enrichment
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"time"
+)
+
+// Enricher completa una parte de la franquicia desde una fuente de datos externa.
+type Enricher interface {
+	Name() string
+	// Dependencies son los enrichers que tienen que terminar antes: los que escriben datos
+	// que este usa o pisa.
+	Dependencies() []string
+	// Timeout limita la ejecución con todos sus intentos; cero usa el de la configuración
+	// del Registry.
+	Timeout() time.Duration
+	Enrich(ctx context.Context, f *domain.Franquicia) error
+}
+
+type funcEnricher struct {
+	name    string
+	timeout time.Duration
+	deps    []string
+	enrich  func(ctx context.Context, f *domain.Franquicia) error
+}
+
+// Func arma un Enricher a partir de una función.
+func Func(name string, timeout time.Duration, deps []string, enrich func(ctx context.Context, f *domain.Franquicia) error) Enricher {
+	return &funcEnricher{name: name, timeout: timeout, deps: deps, enrich: enrich}
+}
+
+func (e *funcEnricher) Name() string           { return e.name }
+func (e *funcEnricher) Dependencies() []string { return e.deps }
+func (e *funcEnricher) Timeout() time.Duration { return e.timeout }
+
+func (e *funcEnricher) Enrich(ctx context.Context, f *domain.Franquicia) error {
+	return e.enrich(ctx, f)
+}
+
+// Permanent marca un error que no se resuelve reintentando, como un dominio inexistente o
+// un sitio sin el dato buscado.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
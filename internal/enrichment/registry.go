@@ -0,0 +1,189 @@
+package enrichment
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDuplicateEnricher = errors.New("duplicate enricher")
+	ErrUnknownDependency = errors.New("unknown enricher dependency")
+	ErrDependencyCycle   = errors.New("enricher dependency cycle")
+)
+
+// Registry corre los enrichers como un grafo de dependencias: cada uno arranca cuando
+// terminaron los que declara como dependencias y los independientes corren en paralelo.
+// Los que corren a la vez tienen que escribir campos distintos del documento.
+type Registry struct {
+	cfg Config
+	// enrichers está en orden topológico.
+	enrichers []Enricher
+}
+
+// NewRegistry valida que los nombres no se repitan y que las dependencias existan y no
+// formen ciclos.
+func NewRegistry(cfg Config, enrichers ...Enricher) (*Registry, error) {
+	byName := map[string]Enricher{}
+	for _, e := range enrichers {
+		if _, ok := byName[e.Name()]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateEnricher, e.Name())
+		}
+		byName[e.Name()] = e
+	}
+
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, e := range enrichers {
+		for _, dep := range e.Dependencies() {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("%w: %s depends on %s", ErrUnknownDependency, e.Name(), dep)
+			}
+			pending[e.Name()]++
+			dependents[dep] = append(dependents[dep], e.Name())
+		}
+	}
+
+	// Kahn, respetando el orden de registro entre los que están listos a la vez.
+	var ordered []Enricher
+	var ready []string
+	for _, e := range enrichers {
+		if pending[e.Name()] == 0 {
+			ready = append(ready, e.Name())
+		}
+	}
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, byName[name])
+		for _, dependent := range dependents[name] {
+			if pending[dependent]--; pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(ordered) != len(enrichers) {
+		var cycle []string
+		for _, e := range enrichers {
+			if pending[e.Name()] > 0 {
+				cycle = append(cycle, e.Name())
+			}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDependencyCycle, cycle)
+	}
+
+	return &Registry{cfg: cfg, enrichers: ordered}, nil
+}
+
+// Names devuelve los nombres de los enrichers en orden topológico.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.enrichers))
+	for i, e := range r.enrichers {
+		names[i] = e.Name()
+	}
+	return names
+}
+
+// Run corre todos los enrichers sobre f y devuelve el resultado de cada uno en orden
+// topológico. Un enricher que falla no detiene al resto: los que dependen de él corren
+// igual y revisan en el documento si tienen los datos que necesitan. Solo se saltean si
+// se cancela ctx antes de que arranquen.
+func (r *Registry) Run(ctx context.Context, f *domain.Franquicia) []domain.EnrichmentResult {
+	results := make([]domain.EnrichmentResult, len(r.enrichers))
+	done := make(map[string]chan struct{}, len(r.enrichers))
+	for _, e := range r.enrichers {
+		done[e.Name()] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i, e := range r.enrichers {
+		wg.Add(1)
+		go func(i int, e Enricher) {
+			defer wg.Done()
+			defer close(done[e.Name()])
+			for _, dep := range e.Dependencies() {
+				select {
+				case <-done[dep]:
+				case <-ctx.Done():
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				results[i] = domain.EnrichmentResult{Name: e.Name(), Status: domain.EnrichmentSkipped, Error: err.Error()}
+				return
+			}
+			results[i] = r.run(ctx, e, f)
+		}(i, e)
+	}
+	wg.Wait()
+	return results
+}
+
+// run hace los intentos de un enricher. El plazo del enricher cubre todos los intentos y
+// las esperas entre ellos: no se reintenta si ya venció.
+func (r *Registry) run(ctx context.Context, e Enricher, f *domain.Franquicia) domain.EnrichmentResult {
+	timeout := e.Timeout()
+	if timeout <= 0 {
+		timeout = r.cfg.Timeout
+	}
+	enrichCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := domain.EnrichmentResult{Name: e.Name()}
+	start := time.Now()
+	var err error
+	for attempt := 0; ; attempt++ {
+		result.Attempts++
+		err = r.enrich(enrichCtx, e, f)
+		var permanent *permanentError
+		if err == nil || attempt >= r.cfg.Retries || errors.As(err, &permanent) || enrichCtx.Err() != nil {
+			break
+		}
+		log.Printf("Error en el enricher %s (intento %d), se reintenta: %v", e.Name(), result.Attempts, err)
+		if !sleep(enrichCtx, r.cfg.RetryBackoff<<attempt) {
+			break
+		}
+	}
+	if err != nil && errors.Is(enrichCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		log.Printf("Error en el enricher %s: %v", e.Name(), err)
+		result.Status = domain.EnrichmentFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = domain.EnrichmentOK
+	return result
+}
+
+func (r *Registry) enrich(ctx context.Context, e Enricher, f *domain.Franquicia) (err error) {
+	// Un enricher que entra en pánico cuenta como fallido, no se reintenta y no tira abajo
+	// la creación.
+	defer func() {
+		if p := recover(); p != nil {
+			err = Permanent(fmt.Errorf("panic: %v", p))
+		}
+	}()
+	return e.Enrich(ctx, f)
+}
+
+// sleep espera d o hasta que se cancele ctx; devuelve false si se canceló.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,217 @@
+package enrichment
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testConfig = Config{Retries: 2, RetryBackoff: time.Millisecond, Timeout: time.Second}
+
+func noop(context.Context, *domain.Franquicia) error { return nil }
+
+func TestNewRegistryOrder(t *testing.T) {
+	r, err := NewRegistry(testConfig,
+		Func("c", 0, []string{"b"}, noop),
+		Func("b", 0, []string{"a"}, noop),
+		Func("a", 0, nil, noop),
+		Func("d", 0, nil, noop),
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	if got, want := r.Names(), []string{"a", "d", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names = %v, want %v", got, want)
+	}
+}
+
+func TestNewRegistryRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		enrichers []Enricher
+		want      error
+	}{
+		{
+			name:      "duplicate name",
+			enrichers: []Enricher{Func("a", 0, nil, noop), Func("a", 0, nil, noop)},
+			want:      ErrDuplicateEnricher,
+		},
+		{
+			name:      "unknown dependency",
+			enrichers: []Enricher{Func("a", 0, []string{"missing"}, noop)},
+			want:      ErrUnknownDependency,
+		},
+		{
+			name: "cycle",
+			enrichers: []Enricher{
+				Func("a", 0, []string{"c"}, noop),
+				Func("b", 0, []string{"a"}, noop),
+				Func("c", 0, []string{"b"}, noop),
+				Func("d", 0, nil, noop),
+			},
+			want: ErrDependencyCycle,
+		},
+		{
+			name:      "self dependency",
+			enrichers: []Enricher{Func("a", 0, []string{"a"}, noop)},
+			want:      ErrDependencyCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRegistry(testConfig, tt.enrichers...); !errors.Is(err, tt.want) {
+				t.Errorf("NewRegistry = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWaitsForDependencies(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string, delay time.Duration) func(context.Context, *domain.Franquicia) error {
+		return func(context.Context, *domain.Franquicia) error {
+			time.Sleep(delay)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	// a tarda más que b, pero c depende de los dos y tiene que terminar último.
+	r, err := NewRegistry(testConfig,
+		Func("c", 0, []string{"a", "b"}, record("c", 0)),
+		Func("a", 0, nil, record("a", 20*time.Millisecond)),
+		Func("b", 0, nil, record("b", 0)),
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	r.Run(context.Background(), &domain.Franquicia{})
+	if len(order) != 3 || order[2] != "c" {
+		t.Errorf("order = %v, want c last", order)
+	}
+}
+
+func TestRunResults(t *testing.T) {
+	failing := errors.New("source unavailable")
+	var flakyCalls int
+	r, err := NewRegistry(testConfig,
+		Func("ok", 0, nil, func(_ context.Context, f *domain.Franquicia) error {
+			f.Name = "Hotel Sol"
+			return nil
+		}),
+		Func("failing", 0, nil, func(context.Context, *domain.Franquicia) error {
+			return failing
+		}),
+		Func("flaky", 0, nil, func(context.Context, *domain.Franquicia) error {
+			if flakyCalls++; flakyCalls < 3 {
+				return failing
+			}
+			return nil
+		}),
+		Func("permanent", 0, nil, func(context.Context, *domain.Franquicia) error {
+			return Permanent(failing)
+		}),
+		Func("panics", 0, nil, func(context.Context, *domain.Franquicia) error {
+			panic("boom")
+		}),
+		Func("slow", 10*time.Millisecond, nil, func(ctx context.Context, _ *domain.Franquicia) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		// Corre aunque falle su dependencia.
+		Func("after-failing", 0, []string{"failing"}, noop),
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	f := &domain.Franquicia{}
+	results := map[string]domain.EnrichmentResult{}
+	for _, res := range r.Run(context.Background(), f) {
+		results[res.Name] = res
+	}
+	if f.Name != "Hotel Sol" {
+		t.Errorf("Name = %q, want the value written by the ok enricher", f.Name)
+	}
+
+	tests := []struct {
+		name     string
+		status   string
+		attempts int
+		error    string
+	}{
+		{name: "ok", status: domain.EnrichmentOK, attempts: 1},
+		{name: "failing", status: domain.EnrichmentFailed, attempts: 3, error: "source unavailable"},
+		{name: "flaky", status: domain.EnrichmentOK, attempts: 3},
+		{name: "permanent", status: domain.EnrichmentFailed, attempts: 1, error: "source unavailable"},
+		{name: "panics", status: domain.EnrichmentFailed, attempts: 1, error: "panic: boom"},
+		{name: "slow", status: domain.EnrichmentFailed, attempts: 1, error: "timed out after 10ms"},
+		{name: "after-failing", status: domain.EnrichmentOK, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := results[tt.name]
+			if res.Status != tt.status {
+				t.Errorf("Status = %q, want %q", res.Status, tt.status)
+			}
+			if res.Attempts != tt.attempts {
+				t.Errorf("Attempts = %d, want %d", res.Attempts, tt.attempts)
+			}
+			if !strings.Contains(res.Error, tt.error) {
+				t.Errorf("Error = %q, want it to contain %q", res.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestRunRetriesWithinTimeout(t *testing.T) {
+	cfg := Config{Retries: 100, RetryBackoff: 5 * time.Millisecond}
+	calls := 0
+	r, err := NewRegistry(cfg, Func("flaky", 30*time.Millisecond, nil, func(context.Context, *domain.Franquicia) error {
+		calls++
+		return errors.New("source unavailable")
+	}))
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	start := time.Now()
+	res := r.Run(context.Background(), &domain.Franquicia{})[0]
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run took %s, want it bounded by the 30ms timeout", elapsed)
+	}
+	if res.Status != domain.EnrichmentFailed || !strings.Contains(res.Error, "timed out after 30ms") {
+		t.Errorf("result = %+v, want a timeout failure", res)
+	}
+	if calls >= 100 || res.Attempts != calls {
+		t.Errorf("Attempts = %d with %d calls, want fewer than 100", res.Attempts, calls)
+	}
+}
+
+func TestRunSkipsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := NewRegistry(testConfig,
+		Func("first", 0, nil, func(context.Context, *domain.Franquicia) error {
+			cancel()
+			return nil
+		}),
+		Func("second", 0, []string{"first"}, noop),
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	results := r.Run(ctx, &domain.Franquicia{})
+	if results[1].Status != domain.EnrichmentSkipped {
+		t.Errorf("second = %+v, want skipped", results[1])
+	}
+}
@@ -0,0 +1,38 @@
+// Package env lee los valores numéricos de la configuración desde variables de entorno.
+package env
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Duration lee una duración (por ejemplo "15m") de la variable name. Si falta deja dst
+// como está; si es inválida o negativa lo registra y también lo deja.
+func Duration(name string, dst *time.Duration) {
+	v := os.Getenv(name)
+	if v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("%s inválido %q, se usa %s", name, v, *dst)
+		return
+	}
+	*dst = d
+}
+
+// Int lee un entero no negativo de la variable name, con el mismo criterio que Duration.
+func Int(name string, dst *int) {
+	v := os.Getenv(name)
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("%s inválido %q, se usa %d", name, v, *dst)
+		return
+	}
+	*dst = n
+}
@@ -0,0 +1,222 @@
+package fingerprint
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fuentes de las que sale una detección.
+const (
+	SourceHeaders   = "headers"
+	SourceCookies   = "cookies"
+	SourceMeta      = "meta"
+	SourceScriptSrc = "script_src"
+	SourceDNS       = "dns"
+	SourceImplies   = "implies"
+)
+
+// Signals son los datos del sitio contra los que se evalúan las reglas.
+type Signals struct {
+	Headers   http.Header
+	Cookies   map[string]string
+	Meta      map[string][]string
+	ScriptSrc []string
+	// DNS son los registros por tipo ("CNAME") del host del sitio.
+	DNS map[string][]string
+}
+
+// detection acumula lo encontrado de una tecnología mientras se evalúan las reglas.
+type detection struct {
+	confidence int
+	version    string
+	sources    map[string]bool
+}
+
+// Analyze evalúa las reglas sobre las señales del sitio y devuelve las tecnologías
+// detectadas, con las implicadas por otras, ordenadas por nombre.
+func (r *Rules) Analyze(signals Signals) []domain.Technology {
+	found := map[string]*detection{}
+	add := func(name, source string, p pattern, version string) {
+		d, ok := found[name]
+		if !ok {
+			d = &detection{sources: map[string]bool{}}
+			found[name] = d
+		}
+		d.confidence = min(d.confidence+p.confidence, 100)
+		d.sources[source] = true
+		if betterVersion(version, d.version) {
+			d.version = version
+		}
+	}
+
+	for name, tech := range r.technologies {
+		for header, patterns := range tech.headers {
+			values, ok := signals.Headers[http.CanonicalHeaderKey(header)]
+			if !ok {
+				continue
+			}
+			for _, value := range values {
+				matchAll(patterns, value, func(p pattern, version string) { add(name, SourceHeaders, p, version) })
+			}
+		}
+		for cookie, patterns := range tech.cookies {
+			value, ok := signals.Cookies[cookie]
+			if !ok {
+				continue
+			}
+			matchAll(patterns, value, func(p pattern, version string) { add(name, SourceCookies, p, version) })
+		}
+		for meta, patterns := range tech.meta {
+			for _, value := range signals.Meta[meta] {
+				matchAll(patterns, value, func(p pattern, version string) { add(name, SourceMeta, p, version) })
+			}
+		}
+		for _, src := range signals.ScriptSrc {
+			matchAll(tech.scriptSrc, src, func(p pattern, version string) { add(name, SourceScriptSrc, p, version) })
+		}
+		for recordType, patterns := range tech.dns {
+			for _, value := range signals.DNS[recordType] {
+				matchAll(patterns, value, func(p pattern, version string) { add(name, SourceDNS, p, version) })
+			}
+		}
+	}
+
+	r.resolveImplies(found)
+	// Las exclusiones se juntan antes de borrar: borrar mientras se recorre el mapa haría
+	// que el resultado dependa del orden de iteración.
+	var excluded []string
+	for name := range found {
+		excluded = append(excluded, r.technologies[name].excludes...)
+	}
+	for _, name := range excluded {
+		delete(found, name)
+	}
+
+	technologies := make([]domain.Technology, 0, len(found))
+	for name, d := range found {
+		sources := make([]string, 0, len(d.sources))
+		for source := range d.sources {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		technologies = append(technologies, domain.Technology{
+			Name:       name,
+			Categories: r.technologies[name].categories,
+			Version:    d.version,
+			Confidence: d.confidence,
+			Sources:    sources,
+		})
+	}
+	sort.Slice(technologies, func(i, j int) bool { return technologies[i].Name < technologies[j].Name })
+	return technologies
+}
+
+// resolveImplies agrega las tecnologías implicadas por las detectadas, en cadena (WordPress
+// implica PHP, que podría implicar otra), con la confianza de la que la implica.
+func (r *Rules) resolveImplies(found map[string]*detection) {
+	pending := make([]string, 0, len(found))
+	for name := range found {
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		for _, imp := range r.technologies[name].implies {
+			if _, known := r.technologies[imp.name]; !known {
+				continue
+			}
+			confidence := found[name].confidence * imp.confidence / 100
+			d, ok := found[imp.name]
+			if !ok {
+				d = &detection{sources: map[string]bool{}}
+				found[imp.name] = d
+				pending = append(pending, imp.name)
+			}
+			d.confidence = max(d.confidence, confidence)
+			d.sources[SourceImplies] = true
+		}
+	}
+}
+
+// matchAll llama a onMatch por cada patrón que coincide con value.
+func matchAll(patterns []pattern, value string, onMatch func(p pattern, version string)) {
+	for _, p := range patterns {
+		if p.regex == nil {
+			onMatch(p, "")
+			continue
+		}
+		groups := p.regex.FindStringSubmatch(value)
+		if groups == nil {
+			continue
+		}
+		onMatch(p, resolveVersion(p.version, groups))
+	}
+}
+
+// resolveVersion arma la versión a partir de la plantilla "\1" del patrón. Soporta el
+// ternario de Wappalyzer "\1?a:b": a si el grupo tiene valor, b si no.
+func resolveVersion(template string, groups []string) string {
+	if template == "" {
+		return ""
+	}
+	if cond, branches, ok := strings.Cut(template, "?"); ok {
+		yes, no, _ := strings.Cut(branches, ":")
+		if resolveVersion(cond, groups) != "" {
+			template = yes
+		} else {
+			template = no
+		}
+	}
+	for i := len(groups) - 1; i >= 1; i-- {
+		template = strings.ReplaceAll(template, `\`+strconv.Itoa(i), groups[i])
+	}
+	return strings.TrimSpace(template)
+}
+
+// betterVersion prefiere la versión más específica (con más componentes).
+func betterVersion(candidate, current string) bool {
+	if candidate == "" {
+		return false
+	}
+	if current == "" {
+		return true
+	}
+	return strings.Count(candidate, ".") > strings.Count(current, ".")
+}
+
+// CompareVersions compara dos versiones numéricamente por componentes ("5.10" > "5.9").
+// Devuelve -1, 0 o 1.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	var parts []int
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,100 @@
+package fingerprint
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+var ErrNoHomepage = errors.New("could not fetch the homepage to detect technologies")
+
+// Detect descarga la home del sitio, junta sus señales (cabeceras, cookies, meta, scripts y
+// CNAME del dominio) y las evalúa con las reglas.
+func Detect(ctx context.Context, factory crawler.Factory, rules *Rules, siteURL string) ([]domain.Technology, error) {
+	if !strings.HasPrefix(siteURL, "http://") && !strings.HasPrefix(siteURL, "https://") {
+		siteURL = "https://" + siteURL
+	}
+
+	var signals Signals
+	c := factory.NewCollector(ctx)
+	c.OnResponse(func(r *colly.Response) {
+		if r.Headers != nil {
+			signals.Headers = *r.Headers
+		}
+		signals.Cookies = map[string]string{}
+		for _, cookie := range (&http.Response{Header: signals.Headers}).Cookies() {
+			signals.Cookies[cookie.Name] = cookie.Value
+		}
+		signals.Meta, signals.ScriptSrc = pageSignals(r.Body)
+	})
+	if err := c.Visit(siteURL); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoHomepage, err)
+	}
+	if signals.Headers == nil {
+		return nil, ErrNoHomepage
+	}
+
+	if u, err := url.Parse(siteURL); err == nil {
+		signals.DNS = map[string][]string{"CNAME": lookupCNAMEs(ctx, u.Hostname())}
+	}
+	return rules.Analyze(signals), nil
+}
+
+// pageSignals extrae las etiquetas meta (por name o property, en minúscula) y los src de
+// los scripts de la página.
+func pageSignals(body []byte) (map[string][]string, []string) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil
+	}
+
+	meta := map[string][]string{}
+	doc.Find("meta[content]").Each(func(_ int, s *goquery.Selection) {
+		key := s.AttrOr("name", s.AttrOr("property", ""))
+		if key == "" {
+			return
+		}
+		key = strings.ToLower(key)
+		meta[key] = append(meta[key], s.AttrOr("content", ""))
+	})
+
+	var scripts []string
+	doc.Find("script[src]").Each(func(_ int, s *goquery.Selection) {
+		if src := strings.TrimSpace(s.AttrOr("src", "")); src != "" {
+			scripts = append(scripts, src)
+		}
+	})
+	return meta, scripts
+}
+
+// lookupCNAMEs resuelve el CNAME del host y, si el sitio está en el dominio raíz (que no
+// puede tener CNAME), también el de www. Los errores de DNS solo dejan la lista vacía.
+func lookupCNAMEs(ctx context.Context, host string) []string {
+	hosts := []string{host}
+	if !strings.HasPrefix(host, "www.") {
+		hosts = append(hosts, "www."+host)
+	}
+
+	var cnames []string
+	for _, h := range hosts {
+		cname, err := net.DefaultResolver.LookupCNAME(ctx, h)
+		if err != nil {
+			continue
+		}
+		cname = strings.TrimSuffix(cname, ".")
+		if cname != "" && !strings.EqualFold(cname, h) {
+			cnames = append(cnames, cname)
+		}
+	}
+	return cnames
+}
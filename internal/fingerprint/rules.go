@@ -0,0 +1,309 @@
+package fingerprint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidRules = errors.New("invalid technology rules")
+
+// defaultRules son las reglas incluidas en el binario, usadas cuando no se configura un
+// archivo propio.
+//
+//go:embed technologies.json
+var defaultRules []byte
+
+// ruleFile es el formato del archivo de reglas, el mismo de Wappalyzer: cada patrón es
+// una regex seguida opcionalmente de "\;version:\1" y "\;confidence:50". Solo se evalúan
+// headers, cookies, meta, scriptSrc y dns; los campos que necesitan el HTML completo o
+// ejecutar la página (js, html, dom, ...) se ignoran con un aviso.
+type ruleFile struct {
+	Categories   map[string]ruleCategory   `json:"categories"`
+	Technologies map[string]ruleTechnology `json:"technologies"`
+}
+
+type ruleCategory struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+type ruleTechnology struct {
+	Cats      []int                 `json:"cats"`
+	Headers   map[string]stringList `json:"headers"`
+	Cookies   map[string]stringList `json:"cookies"`
+	Meta      map[string]stringList `json:"meta"`
+	ScriptSrc stringList            `json:"scriptSrc"`
+	DNS       map[string]stringList `json:"dns"`
+	Implies   stringList            `json:"implies"`
+	Excludes  stringList            `json:"excludes"`
+	Website   string                `json:"website"`
+
+	// Campos de detección no soportados; se leen solo para avisar que se ignoran.
+	JS         json.RawMessage `json:"js"`
+	HTML       json.RawMessage `json:"html"`
+	DOM        json.RawMessage `json:"dom"`
+	Text       json.RawMessage `json:"text"`
+	CSS        json.RawMessage `json:"css"`
+	URL        json.RawMessage `json:"url"`
+	XHR        json.RawMessage `json:"xhr"`
+	Scripts    json.RawMessage `json:"scripts"`
+	Robots     json.RawMessage `json:"robots"`
+	CertIssuer json.RawMessage `json:"certIssuer"`
+}
+
+// unsupported devuelve los campos de detección que la regla usa y no se evalúan.
+func (t ruleTechnology) unsupported() []string {
+	var fields []string
+	for _, f := range []struct {
+		name  string
+		value json.RawMessage
+	}{
+		{"js", t.JS}, {"html", t.HTML}, {"dom", t.DOM}, {"text", t.Text}, {"css", t.CSS},
+		{"url", t.URL}, {"xhr", t.XHR}, {"scripts", t.Scripts}, {"robots", t.Robots}, {"certIssuer", t.CertIssuer},
+	} {
+		if len(f.value) > 0 && string(f.value) != "null" {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// stringList acepta un string o una lista de strings, como el formato de Wappalyzer.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*l = stringList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*l = many
+	return nil
+}
+
+// pattern es un patrón compilado. Sin regex solo se exige que el dato exista.
+type pattern struct {
+	regex      *regexp.Regexp
+	version    string
+	confidence int
+}
+
+// technology es una tecnología con sus patrones compilados. Las claves de headers y meta
+// quedan en minúscula, las de dns (tipo de registro) en mayúscula y las de cookies como
+// vienen, porque distinguen mayúsculas.
+type technology struct {
+	name       string
+	categories []string
+	headers    map[string][]pattern
+	cookies    map[string][]pattern
+	meta       map[string][]pattern
+	scriptSrc  []pattern
+	dns        map[string][]pattern
+	implies    []implied
+	excludes   []string
+}
+
+type implied struct {
+	name       string
+	confidence int
+}
+
+// Rules es un conjunto de reglas listo para analizar sitios.
+type Rules struct {
+	technologies map[string]*technology
+}
+
+// ParseRules compila un archivo de reglas. Los patrones que RE2 no soporta (por ejemplo
+// lookaheads de JavaScript) se descartan con un aviso en vez de invalidar todo el archivo.
+func ParseRules(data []byte) (*Rules, error) {
+	var file ruleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	if len(file.Technologies) == 0 {
+		return nil, fmt.Errorf("%w: no technologies defined", ErrInvalidRules)
+	}
+
+	rules := &Rules{technologies: make(map[string]*technology, len(file.Technologies))}
+	unsupported := map[string]int{}
+	for name, t := range file.Technologies {
+		for _, field := range t.unsupported() {
+			unsupported[field]++
+		}
+		tech := &technology{
+			name:      name,
+			headers:   compileMap(name, t.Headers, strings.ToLower),
+			cookies:   compileMap(name, t.Cookies, nil),
+			meta:      compileMap(name, t.Meta, strings.ToLower),
+			scriptSrc: compileList(name, t.ScriptSrc),
+			dns:       compileMap(name, t.DNS, strings.ToUpper),
+			excludes:  t.Excludes,
+		}
+		for _, id := range t.Cats {
+			if category, ok := file.Categories[strconv.Itoa(id)]; ok {
+				tech.categories = append(tech.categories, category.Name)
+			}
+		}
+		for _, value := range t.Implies {
+			p := parsePattern(value)
+			tech.implies = append(tech.implies, implied{name: p.regexSource, confidence: p.confidence})
+		}
+		rules.technologies[name] = tech
+	}
+	if len(unsupported) > 0 {
+		fields := make([]string, 0, len(unsupported))
+		for field, count := range unsupported {
+			fields = append(fields, fmt.Sprintf("%s (%d)", field, count))
+		}
+		sort.Strings(fields)
+		log.Printf("Reglas de tecnologías con campos no soportados, se ignoran: %s", strings.Join(fields, ", "))
+	}
+	return rules, nil
+}
+
+// DefaultRules devuelve las reglas incluidas en el binario.
+func DefaultRules() *Rules {
+	rules, err := ParseRules(defaultRules)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// compileMap compila los patrones por clave; normalize unifica las mayúsculas de las
+// claves que no las distinguen.
+func compileMap(tech string, values map[string]stringList, normalize func(string) string) map[string][]pattern {
+	if len(values) == 0 {
+		return nil
+	}
+	compiled := make(map[string][]pattern, len(values))
+	for key, list := range values {
+		if normalize != nil {
+			key = normalize(key)
+		}
+		compiled[key] = append(compiled[key], compileList(tech, list)...)
+	}
+	return compiled
+}
+
+func compileList(tech string, values []string) []pattern {
+	var compiled []pattern
+	for _, value := range values {
+		p := parsePattern(value)
+		c := pattern{version: p.version, confidence: p.confidence}
+		if p.regexSource != "" {
+			regex, err := regexp.Compile("(?i)" + p.regexSource)
+			if err != nil {
+				log.Printf("Patrón inválido para %s descartado %q: %v", tech, p.regexSource, err)
+				continue
+			}
+			c.regex = regex
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled
+}
+
+type parsedPattern struct {
+	regexSource string
+	version     string
+	confidence  int
+}
+
+// parsePattern separa la regex de sus atributos "\;version:" y "\;confidence:".
+func parsePattern(value string) parsedPattern {
+	parts := strings.Split(value, `\;`)
+	p := parsedPattern{regexSource: parts[0], confidence: 100}
+	for _, attr := range parts[1:] {
+		key, arg, ok := strings.Cut(attr, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "version":
+			p.version = arg
+		case "confidence":
+			if n, err := strconv.Atoi(arg); err == nil {
+				p.confidence = n
+			}
+		}
+	}
+	return p
+}
+
+// RuleSource entrega las reglas vigentes.
+type RuleSource interface {
+	Rules() (*Rules, error)
+}
+
+// NewRuleSource devuelve las reglas incluidas en el binario si path está vacío, o las del
+// archivo, que se vuelve a leer cuando cambia para poder actualizarlo sin reiniciar.
+func NewRuleSource(path string) RuleSource {
+	if path == "" {
+		return staticSource{rules: DefaultRules()}
+	}
+	return &fileSource{path: path}
+}
+
+type staticSource struct {
+	rules *Rules
+}
+
+func (s staticSource) Rules() (*Rules, error) {
+	return s.rules, nil
+}
+
+type fileSource struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	rules   *Rules
+}
+
+// Rules relee el archivo si cambió. Si la nueva versión no es válida se siguen usando
+// las reglas anteriores.
+func (s *fileSource) Rules() (*Rules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if s.rules != nil {
+			log.Printf("No se pudo leer %s, se usan las reglas anteriores: %v", s.path, err)
+			return s.rules, nil
+		}
+		return nil, err
+	}
+	if s.rules != nil && info.ModTime().Equal(s.modTime) {
+		return s.rules, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		var rules *Rules
+		if rules, err = ParseRules(data); err == nil {
+			s.rules, s.modTime = rules, info.ModTime()
+			log.Printf("Reglas de tecnologías cargadas desde %s (%d tecnologías)", s.path, len(rules.technologies))
+			return s.rules, nil
+		}
+	}
+	if s.rules != nil {
+		log.Printf("Reglas de %s inválidas, se usan las anteriores: %v", s.path, err)
+		return s.rules, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,197 @@
+package folio
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNotFound         = errors.New("folio not found")
+	ErrRoomChargePosted = errors.New("room charge already posted for that night")
+)
+
+type Repository interface {
+	Create(ctx context.Context, f *domain.Folio) error
+	GetOne(ctx context.Context, id primitive.ObjectID) (domain.Folio, error)
+	GetByHotel(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.FolioStatus) ([]domain.Folio, error)
+	GetOverlapping(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber, checkIn, checkOut string) ([]domain.Folio, error)
+	AddCharges(ctx context.Context, id primitive.ObjectID, charges ...domain.Charge) error
+	AddPayment(ctx context.Context, id primitive.ObjectID, p domain.Payment) error
+	ClaimCheckout(ctx context.Context, f domain.Folio, pending []domain.Charge) (bool, error)
+	ReleaseCheckout(ctx context.Context, id primitive.ObjectID) error
+	Close(ctx context.Context, id, invoiceID primitive.ObjectID, closedAt time.Time) error
+}
+
+type repository struct {
+	db *mongo.Collection
+}
+
+func NewRepository(db *mongo.Collection) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, f *domain.Folio) error {
+	_, err := r.db.InsertOne(ctx, f)
+	return err
+}
+
+func (r *repository) GetOne(ctx context.Context, id primitive.ObjectID) (domain.Folio, error) {
+	var f domain.Folio
+	err := r.db.FindOne(ctx, bson.M{"_id": id}).Decode(&f)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return f, ErrNotFound
+	}
+	return f, err
+}
+
+func (r *repository) GetByHotel(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.FolioStatus) ([]domain.Folio, error) {
+	var folios []domain.Folio
+	filter := bson.M{"franquicia_id": franquiciaID}
+	if hotelID != "" {
+		filter["hotel_id"] = hotelID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	cursor, err := r.db.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var f domain.Folio
+		if err := cursor.Decode(&f); err != nil {
+			return nil, err
+		}
+		folios = append(folios, f)
+	}
+
+	return folios, nil
+}
+
+// GetOverlapping devuelve los folios abiertos o en checkout de la habitación cuya estadía
+// comparte al menos una noche con [checkIn, checkOut).
+func (r *repository) GetOverlapping(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber, checkIn, checkOut string) ([]domain.Folio, error) {
+	filter := bson.M{
+		"franquicia_id": franquiciaID,
+		"hotel_id":      hotelID,
+		"room_number":   roomNumber,
+		"status":        bson.M{"$in": []domain.FolioStatus{domain.FolioOpen, domain.FolioClosing}},
+		"check_in":      bson.M{"$lt": checkOut},
+		"check_out":     bson.M{"$gt": checkIn},
+	}
+	cursor, err := r.db.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var folios []domain.Folio
+	if err := cursor.All(ctx, &folios); err != nil {
+		return nil, err
+	}
+	return folios, nil
+}
+
+// AddCharges agrega cargos solo si el folio sigue abierto. Los cargos de habitación no se
+// agregan si alguna de sus noches ya tiene uno (ErrRoomChargePosted), así la auditoría
+// nocturna y el checkout no cobran dos veces la misma noche aunque corran a la vez.
+func (r *repository) AddCharges(ctx context.Context, id primitive.ObjectID, charges ...domain.Charge) error {
+	filter := bson.M{"_id": id, "status": domain.FolioOpen}
+	if nights := roomNights(charges); len(nights) > 0 {
+		filter["charges"] = bson.M{"$not": bson.M{"$elemMatch": bson.M{"type": domain.ChargeRoom, "date": bson.M{"$in": nights}}}}
+	}
+	update := bson.M{"$push": bson.M{"charges": bson.M{"$each": charges}}}
+
+	res, err := r.db.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+	f, err := r.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+	if f.Status != domain.FolioOpen {
+		return ErrFolioClosed
+	}
+	return ErrRoomChargePosted
+}
+
+func roomNights(charges []domain.Charge) []string {
+	var nights []string
+	for _, c := range charges {
+		if c.Type == domain.ChargeRoom {
+			nights = append(nights, c.Date)
+		}
+	}
+	return nights
+}
+
+func (r *repository) AddPayment(ctx context.Context, id primitive.ObjectID, p domain.Payment) error {
+	filter := bson.M{"_id": id, "status": domain.FolioOpen}
+	update := bson.M{"$push": bson.M{"payments": p}}
+	return r.updateOpen(ctx, filter, update)
+}
+
+// ClaimCheckout postea los cargos pendientes y pasa el folio a CLOSING en una sola
+// actualización, solo si sigue abierto y tiene los mismos cargos y pagos que f (los arrays
+// solo crecen, alcanza con comparar el largo). Devuelve false si el folio cambió o ya no
+// está abierto.
+func (r *repository) ClaimCheckout(ctx context.Context, f domain.Folio, pending []domain.Charge) (bool, error) {
+	filter := bson.M{
+		"_id":      f.ID,
+		"status":   domain.FolioOpen,
+		"charges":  bson.M{"$size": len(f.Charges)},
+		"payments": bson.M{"$size": len(f.Payments)},
+	}
+	update := bson.M{"$set": bson.M{"status": domain.FolioClosing}}
+	if len(pending) > 0 {
+		update["$push"] = bson.M{"charges": bson.M{"$each": pending}}
+	}
+	res, err := r.db.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// ReleaseCheckout vuelve a abrir un folio en CLOSING cuando no se pudo emitir la factura.
+func (r *repository) ReleaseCheckout(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "status": domain.FolioClosing}
+	_, err := r.db.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": domain.FolioOpen}})
+	return err
+}
+
+// Close cierra un folio reservado con ClaimCheckout.
+func (r *repository) Close(ctx context.Context, id, invoiceID primitive.ObjectID, closedAt time.Time) error {
+	filter := bson.M{"_id": id, "status": domain.FolioClosing}
+	update := bson.M{"$set": bson.M{
+		"status":     domain.FolioClosed,
+		"invoice_id": invoiceID,
+		"closed_at":  closedAt,
+	}}
+	return r.updateOpen(ctx, filter, update)
+}
+
+func (r *repository) updateOpen(ctx context.Context, filter, update bson.M) error {
+	res, err := r.db.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrFolioClosed
+	}
+	return nil
+}
@@ -0,0 +1,516 @@
+package folio
+
+import (
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/housekeeping"
+	"clubhub-hotel-management/internal/invoice"
+	"clubhub-hotel-management/internal/rateplan"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidFolio       = errors.New("invalid folio")
+	ErrFolioClosed        = errors.New("folio is closed")
+	ErrOutstandingBalance = errors.New("folio has an outstanding balance")
+	ErrCheckoutInProgress = errors.New("folio checkout already in progress")
+	ErrFolioBusy          = errors.New("folio changed during checkout, try again")
+)
+
+// Veces que Checkout vuelve a leer el folio si cambia mientras se cierra.
+const checkoutAttempts = 3
+
+// Tiempo para emitir la factura y cerrar el folio una vez reservado. Esos pasos no se
+// cortan si el cliente se desconecta, así que tienen su propio límite.
+const checkoutTimeout = 30 * time.Second
+
+type Service interface {
+	OpenFolio(ctx context.Context, f *domain.Folio) error
+	GetFolio(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.Folio, error)
+	GetFolios(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.FolioStatus) ([]domain.Folio, error)
+	PostCharge(ctx context.Context, franquiciaID, id primitive.ObjectID, req domain.ChargeRequest) (*domain.Charge, error)
+	PostPayment(ctx context.Context, franquiciaID, id primitive.ObjectID, req domain.PaymentRequest) (*domain.Payment, error)
+	PostRoomCharges(ctx context.Context, franquiciaID primitive.ObjectID, date string) (int, error)
+	Checkout(ctx context.Context, franquiciaID, id primitive.ObjectID) (*domain.Invoice, error)
+	InCurrency(ctx context.Context, f domain.Folio, to string) (domain.Folio, error)
+
+	SaveTaxConfig(ctx context.Context, cfg *domain.TaxConfig) error
+	GetTaxConfig(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (domain.TaxConfig, error)
+}
+
+type service struct {
+	repo         Repository
+	taxes        TaxRepository
+	ratePlans    rateplan.Service
+	invoices     invoice.Service
+	franquicias  franquicia.Service
+	housekeeping housekeeping.Service
+	rates        currency.Service
+}
+
+// NewService crea un nuevo servicio de folios.
+func NewService(r Repository, taxes TaxRepository, ratePlans rateplan.Service, invoices invoice.Service, franquicias franquicia.Service, hk housekeeping.Service, rates currency.Service) Service {
+	return &service{
+		repo:         r,
+		taxes:        taxes,
+		ratePlans:    ratePlans,
+		invoices:     invoices,
+		franquicias:  franquicias,
+		housekeeping: hk,
+		rates:        rates,
+	}
+}
+
+// OpenFolio abre el folio de una estadía y congela la tarifa de cada noche según el plan tarifario.
+func (s *service) OpenFolio(ctx context.Context, f *domain.Folio) error {
+	if f.HotelID == "" || f.GuestName == "" || f.RoomNumber == "" {
+		return fmt.Errorf("%w: hotel_id, guest_name and room_number are required", ErrInvalidFolio)
+	}
+
+	rp, err := s.ratePlans.GetRatePlan(ctx, f.FranquiciaID, f.RatePlanID)
+	if err != nil {
+		return err
+	}
+	if rp.HotelID != f.HotelID {
+		return fmt.Errorf("%w: rate plan belongs to another hotel", ErrInvalidFolio)
+	}
+	quote, err := s.ratePlans.Quote(ctx, f.FranquiciaID, f.RatePlanID, f.CheckIn, f.CheckOut, f.Currency)
+	if err != nil {
+		return err
+	}
+	if err := s.housekeeping.EnsureAssignable(ctx, *f); err != nil {
+		return err
+	}
+
+	f.ID = primitive.NewObjectID()
+	f.Currency = quote.Total.Currency
+	f.Rates = quote.Nights
+	f.Status = domain.FolioOpen
+	f.Charges = []domain.Charge{}
+	f.Payments = []domain.Payment{}
+	f.OpenedAt = time.Now().UTC()
+
+	if err := s.repo.Create(ctx, f); err != nil {
+		log.Printf("Error al abrir folio: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) GetFolio(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.Folio, error) {
+	f, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return domain.Folio{}, err
+	}
+	if f.FranquiciaID != franquiciaID {
+		return domain.Folio{}, ErrNotFound
+	}
+	return f, nil
+}
+
+func (s *service) GetFolios(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.FolioStatus) ([]domain.Folio, error) {
+	result, err := s.repo.GetByHotel(ctx, franquiciaID, hotelID, status)
+	if err != nil {
+		return []domain.Folio{}, err
+	}
+	return result, nil
+}
+
+// PostCharge postea un extra (minibar, spa, parking...) con los impuestos del hotel.
+func (s *service) PostCharge(ctx context.Context, franquiciaID, id primitive.ObjectID, req domain.ChargeRequest) (*domain.Charge, error) {
+	if !req.Type.IsValid() || req.Type == domain.ChargeRoom {
+		return nil, fmt.Errorf("%w: unknown extra charge type %q", ErrInvalidFolio, req.Type)
+	}
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidFolio)
+	}
+
+	f, err := s.GetFolio(ctx, franquiciaID, id)
+	if err != nil {
+		return nil, err
+	}
+	if f.Status != domain.FolioOpen {
+		return nil, ErrFolioClosed
+	}
+
+	if req.Date == "" {
+		req.Date = time.Now().UTC().Format(domain.DateLayout)
+	}
+	if _, err := time.Parse(domain.DateLayout, req.Date); err != nil {
+		return nil, fmt.Errorf("%w: date: %v", ErrInvalidFolio, err)
+	}
+
+	charge, err := s.newCharge(ctx, f, req.Type, req.Description, req.Date, domain.NewMoney(req.Amount, f.Currency))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddCharges(ctx, f.ID, charge); err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
+
+func (s *service) PostPayment(ctx context.Context, franquiciaID, id primitive.ObjectID, req domain.PaymentRequest) (*domain.Payment, error) {
+	if req.Kind == "" {
+		req.Kind = domain.PaymentKindPayment
+	}
+	if req.Kind != domain.PaymentKindPayment && req.Kind != domain.PaymentKindRefund {
+		return nil, fmt.Errorf("%w: unknown payment kind %q", ErrInvalidFolio, req.Kind)
+	}
+	if req.Method == "" || !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: method and a positive amount are required", ErrInvalidFolio)
+	}
+
+	f, err := s.GetFolio(ctx, franquiciaID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p := domain.Payment{
+		ID:        primitive.NewObjectID(),
+		Kind:      req.Kind,
+		Method:    req.Method,
+		Reference: req.Reference,
+		Amount:    domain.NewMoney(req.Amount, f.Currency).Round(),
+		PostedAt:  time.Now().UTC(),
+	}
+	if err := s.repo.AddPayment(ctx, f.ID, p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// PostRoomCharges es la auditoría nocturna: postea el cargo de habitación de date en
+// cada folio abierto que tenga esa noche y genera la limpieza de las habitaciones en
+// stay-over. Devuelve la cantidad de cargos posteados.
+func (s *service) PostRoomCharges(ctx context.Context, franquiciaID primitive.ObjectID, date string) (int, error) {
+	if _, err := time.Parse(domain.DateLayout, date); err != nil {
+		return 0, fmt.Errorf("%w: date: %v", ErrInvalidFolio, err)
+	}
+
+	folios, err := s.repo.GetByHotel(ctx, franquiciaID, "", domain.FolioOpen)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, f := range folios {
+		if date > f.CheckIn && date < f.CheckOut {
+			if err := s.housekeeping.OnStayover(ctx, f, date); err != nil {
+				return posted, err
+			}
+		}
+
+		charges, err := s.pendingRoomCharges(ctx, f, func(night string) bool { return night == date })
+		if err != nil {
+			return posted, err
+		}
+		if len(charges) == 0 {
+			continue
+		}
+		// Otro proceso pudo postear la noche o cerrar el folio desde que se leyó.
+		err = s.repo.AddCharges(ctx, f.ID, charges...)
+		if errors.Is(err, ErrRoomChargePosted) || errors.Is(err, ErrFolioClosed) {
+			continue
+		}
+		if err != nil {
+			return posted, err
+		}
+		posted += len(charges)
+	}
+
+	log.Printf("Auditoría nocturna %s: %d cargos de habitación posteados", date, posted)
+	return posted, nil
+}
+
+// Checkout postea las noches pendientes, emite la factura, cierra el folio y deja la
+// habitación sucia con su tarea de limpieza. El folio se reserva antes de emitir la factura,
+// así dos checkouts simultáneos o repetidos no emiten dos facturas ni consumen números.
+// Repetir el checkout de un folio que quedó en CLOSING con la factura emitida termina de
+// cerrarlo.
+func (s *service) Checkout(ctx context.Context, franquiciaID, id primitive.ObjectID) (*domain.Invoice, error) {
+	f, err := s.claimCheckout(ctx, franquiciaID, id)
+	if errors.Is(err, ErrCheckoutInProgress) {
+		return s.resumeCheckout(ctx, f)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Con el folio reservado, cortar a la mitad porque el cliente se desconectó lo dejaría
+	// en CLOSING con la factura emitida.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checkoutTimeout)
+	defer cancel()
+
+	inv, err := s.issueInvoice(ctx, f)
+	if errors.Is(err, invoice.ErrAlreadyInvoiced) {
+		return s.resumeCheckout(ctx, f)
+	}
+	if err != nil {
+		if err := s.repo.ReleaseCheckout(ctx, f.ID); err != nil {
+			log.Printf("Error al reabrir folio %s después de fallar el checkout: %v", f.ID.Hex(), err)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.Close(ctx, f.ID, inv.ID, inv.IssuedAt); err != nil {
+		return nil, err
+	}
+	s.onCheckout(ctx, f, inv)
+	return inv, nil
+}
+
+// resumeCheckout termina el checkout de un folio en CLOSING que ya tiene factura. Si todavía
+// no la tiene, otro checkout la está emitiendo.
+func (s *service) resumeCheckout(ctx context.Context, f domain.Folio) (*domain.Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checkoutTimeout)
+	defer cancel()
+
+	inv, err := s.invoices.GetInvoiceByFolio(ctx, f.ID)
+	if errors.Is(err, invoice.ErrNotFound) {
+		return nil, ErrCheckoutInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Close(ctx, f.ID, inv.ID, inv.IssuedAt)
+	if errors.Is(err, ErrFolioClosed) {
+		// El checkout original lo cerró mientras tanto y ya generó la limpieza.
+		return &inv, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Checkout del folio %s retomado con la factura %s", f.ID.Hex(), inv.Number)
+	s.onCheckout(ctx, f, &inv)
+	return &inv, nil
+}
+
+// onCheckout deja la habitación sucia con su tarea de limpieza. La factura ya está emitida:
+// un error al generar la limpieza no debe revertir el checkout.
+func (s *service) onCheckout(ctx context.Context, f domain.Folio, inv *domain.Invoice) {
+	if err := s.housekeeping.OnCheckout(ctx, f, inv.IssuedAt.Format(domain.DateLayout)); err != nil {
+		log.Printf("Error al generar limpieza de checkout para folio %s: %v", f.ID.Hex(), err)
+	}
+}
+
+// claimCheckout postea las noches pendientes y pasa el folio a CLOSING con una sola
+// actualización condicionada. Si el folio cambió desde que se leyó (un cargo, un pago o la
+// auditoría nocturna) se vuelve a leer. Un folio que ya está en CLOSING se devuelve junto
+// con ErrCheckoutInProgress.
+func (s *service) claimCheckout(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.Folio, error) {
+	for attempt := 0; attempt < checkoutAttempts; attempt++ {
+		f, err := s.GetFolio(ctx, franquiciaID, id)
+		if err != nil {
+			return domain.Folio{}, err
+		}
+		switch f.Status {
+		case domain.FolioOpen:
+		case domain.FolioClosing:
+			return f, ErrCheckoutInProgress
+		default:
+			return domain.Folio{}, ErrFolioClosed
+		}
+
+		// Solo se cobran las noches anteriores a la salida real: en una salida anticipada
+		// la estadía termina hoy, como en los calendarios.
+		departure := time.Now().UTC().Format(domain.DateLayout)
+		pending, err := s.pendingRoomCharges(ctx, f, func(night string) bool { return night < departure })
+		if err != nil {
+			return domain.Folio{}, err
+		}
+		charged := f
+		charged.Charges = append(append([]domain.Charge{}, f.Charges...), pending...)
+
+		if balance := charged.Balance(); !balance.Amount.IsZero() {
+			// Las noches se postean igual para que el saldo del folio muestre lo que falta pagar.
+			if len(pending) > 0 {
+				err := s.repo.AddCharges(ctx, f.ID, pending...)
+				if err != nil && !errors.Is(err, ErrRoomChargePosted) {
+					return domain.Folio{}, err
+				}
+			}
+			return domain.Folio{}, fmt.Errorf("%w: %s %s", ErrOutstandingBalance, balance.Amount.StringFixed(domain.MinorUnits(balance.Currency)), balance.Currency)
+		}
+
+		claimed, err := s.repo.ClaimCheckout(ctx, f, pending)
+		if err != nil {
+			return domain.Folio{}, err
+		}
+		if claimed {
+			charged.Status = domain.FolioClosing
+			return charged, nil
+		}
+	}
+	return domain.Folio{}, ErrFolioBusy
+}
+
+func (s *service) issueInvoice(ctx context.Context, f domain.Folio) (*domain.Invoice, error) {
+	issuer, err := s.issuer(ctx, f.FranquiciaID)
+	if err != nil {
+		return nil, err
+	}
+	return s.invoices.IssueInvoice(ctx, f, issuer)
+}
+
+// InCurrency devuelve una copia del folio con los montos en la moneda to. Cada noche y
+// cada cargo se convierten con el tipo de cambio vigente en su fecha, y cada pago con el
+// del día en que se registró.
+func (s *service) InCurrency(ctx context.Context, f domain.Folio, to string) (domain.Folio, error) {
+	if to == f.Currency {
+		return f, nil
+	}
+	convert := func(m domain.Money, date string) (domain.Money, error) {
+		return s.rates.Convert(ctx, m, to, date)
+	}
+
+	rates := make([]domain.QuoteNight, len(f.Rates))
+	for i, night := range f.Rates {
+		original := night.Rate
+		converted, err := convert(night.Rate, night.Date)
+		if err != nil {
+			return domain.Folio{}, err
+		}
+		night.Rate, night.OriginalRate = converted, &original
+		rates[i] = night
+	}
+
+	charges := make([]domain.Charge, len(f.Charges))
+	for i, c := range f.Charges {
+		amount, err := convert(c.Amount, c.Date)
+		if err != nil {
+			return domain.Folio{}, err
+		}
+		c.Amount = amount
+		taxes := make([]domain.TaxLine, len(c.Taxes))
+		for j, t := range c.Taxes {
+			if t.Amount, err = convert(t.Amount, c.Date); err != nil {
+				return domain.Folio{}, err
+			}
+			taxes[j] = t
+		}
+		if len(taxes) > 0 {
+			c.Taxes = taxes
+		}
+		charges[i] = c
+	}
+
+	payments := make([]domain.Payment, len(f.Payments))
+	for i, p := range f.Payments {
+		amount, err := convert(p.Amount, p.PostedAt.Format(domain.DateLayout))
+		if err != nil {
+			return domain.Folio{}, err
+		}
+		p.Amount = amount
+		payments[i] = p
+	}
+
+	f.Rates, f.Charges, f.Payments = rates, charges, payments
+	f.OriginalCurrency, f.Currency = f.Currency, to
+	return f, nil
+}
+
+func (s *service) SaveTaxConfig(ctx context.Context, cfg *domain.TaxConfig) error {
+	if cfg.HotelID == "" {
+		return fmt.Errorf("%w: hotel_id is required", ErrInvalidFolio)
+	}
+	for _, r := range cfg.Rules {
+		if r.Name == "" || r.Rate.IsNegative() {
+			return fmt.Errorf("%w: tax rules need a name and a non-negative rate", ErrInvalidFolio)
+		}
+		for _, t := range r.AppliesTo {
+			if !t.IsValid() {
+				return fmt.Errorf("%w: unknown charge type %q in tax %q", ErrInvalidFolio, t, r.Name)
+			}
+		}
+	}
+	return s.taxes.Save(ctx, cfg)
+}
+
+func (s *service) GetTaxConfig(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (domain.TaxConfig, error) {
+	return s.taxes.Get(ctx, franquiciaID, hotelID)
+}
+
+// pendingRoomCharges arma los cargos de habitación aún no posteados de las noches para las
+// que include devuelve true.
+func (s *service) pendingRoomCharges(ctx context.Context, f domain.Folio, include func(night string) bool) ([]domain.Charge, error) {
+	posted := map[string]bool{}
+	for _, c := range f.Charges {
+		if c.Type == domain.ChargeRoom {
+			posted[c.Date] = true
+		}
+	}
+
+	var charges []domain.Charge
+	for _, night := range f.Rates {
+		if posted[night.Date] || !include(night.Date) {
+			continue
+		}
+		charge, err := s.newCharge(ctx, f, domain.ChargeRoom, "Habitación "+f.RoomNumber, night.Date, night.Rate)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+	return charges, nil
+}
+
+func (s *service) newCharge(ctx context.Context, f domain.Folio, t domain.ChargeType, description, date string, amount domain.Money) (domain.Charge, error) {
+	charge := domain.Charge{
+		ID:          primitive.NewObjectID(),
+		Type:        t,
+		Description: description,
+		Date:        date,
+		Amount:      amount.Round(),
+		PostedAt:    time.Now().UTC(),
+	}
+
+	cfg, err := s.taxes.Get(ctx, f.FranquiciaID, f.HotelID)
+	if errors.Is(err, ErrTaxConfigNotFound) {
+		return charge, nil
+	}
+	if err != nil {
+		return domain.Charge{}, err
+	}
+
+	for _, rule := range cfg.Rules {
+		if !rule.Applies(t) {
+			continue
+		}
+		tax := charge.Amount.Amount.Mul(rule.Rate).Div(decimal.NewFromInt(100))
+		charge.Taxes = append(charge.Taxes, domain.TaxLine{
+			Name:   rule.Name,
+			Rate:   rule.Rate,
+			Amount: domain.NewMoney(tax, f.Currency).Round(),
+		})
+	}
+	return charge, nil
+}
+
+func (s *service) issuer(ctx context.Context, franquiciaID primitive.ObjectID) (domain.InvoiceIssuer, error) {
+	fr, err := s.franquicias.GetFranquiciaByID(ctx, franquiciaID.Hex())
+	if err != nil {
+		return domain.InvoiceIssuer{}, fmt.Errorf("error obteniendo franquicia emisora: %w", err)
+	}
+	issuer := domain.InvoiceIssuer{
+		Name:    fr.Name,
+		Address: fr.Location.Address,
+		City:    fr.Location.City,
+		Country: fr.Location.Country,
+		ZipCode: fr.Location.ZipCode,
+		LogoURL: fr.LogoURL,
+	}
+	if fr.Logo != nil {
+		issuer.LogoMediaID = fr.Logo.MediaID
+	}
+	return issuer, nil
+}
@@ -0,0 +1,53 @@
+package folio
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrTaxConfigNotFound = errors.New("tax configuration not found")
+
+// TaxRepository guarda la configuración de impuestos de cada hotel (una por hotel).
+type TaxRepository interface {
+	Save(ctx context.Context, cfg *domain.TaxConfig) error
+	Get(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (domain.TaxConfig, error)
+}
+
+type taxRepository struct {
+	db *mongo.Collection
+}
+
+func NewTaxRepository(db *mongo.Collection) TaxRepository {
+	return &taxRepository{
+		db: db,
+	}
+}
+
+func (r *taxRepository) Save(ctx context.Context, cfg *domain.TaxConfig) error {
+	filter := bson.M{"franquicia_id": cfg.FranquiciaID, "hotel_id": cfg.HotelID}
+	update := bson.M{
+		"$set": bson.M{
+			"jurisdiction": cfg.Jurisdiction,
+			"rules":        cfg.Rules,
+		},
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return r.db.FindOneAndUpdate(ctx, filter, update, opts).Decode(cfg)
+}
+
+func (r *taxRepository) Get(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (domain.TaxConfig, error) {
+	var cfg domain.TaxConfig
+	filter := bson.M{"franquicia_id": franquiciaID, "hotel_id": hotelID}
+	err := r.db.FindOne(ctx, filter).Decode(&cfg)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return cfg, ErrTaxConfigNotFound
+	}
+	return cfg, err
+}
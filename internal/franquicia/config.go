@@ -0,0 +1,60 @@
+package franquicia
+
+import (
+	"clubhub-hotel-management/internal/enrichment"
+	"clubhub-hotel-management/internal/env"
+	"context"
+	"time"
+)
+
+// Config reúne la configuración del servicio de franquicias.
+type Config struct {
+	Enrichment enrichment.Config
+	Timeouts   Timeouts
+}
+
+// Timeouts son los plazos totales de cada tipo de operación, llamadas a la red incluidas.
+// Cero deja la operación sin plazo propio.
+type Timeouts struct {
+	// Create limita los enrichers de la creación; el guardado usa Write.
+	Create time.Duration
+	// Read limita las consultas.
+	Read time.Duration
+	// Write limita las actualizaciones y la reinterpretación de WHOIS/RDAP archivado.
+	Write time.Duration
+	// Refresh limita los análisis que vuelven a consultar el sitio o el DNS: cabeceras de
+	// seguridad, tecnologías y seguridad del correo.
+	Refresh time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enrichment: enrichment.DefaultConfig(),
+		Timeouts: Timeouts{
+			Create:  2 * time.Minute,
+			Read:    10 * time.Second,
+			Write:   10 * time.Second,
+			Refresh: time.Minute,
+		},
+	}
+}
+
+// ConfigFromEnv parte de DefaultConfig y aplica las variables ENRICHMENT_* y
+// FRANCHISE_*_TIMEOUT.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	cfg.Enrichment = enrichment.ConfigFromEnv()
+	env.Duration("FRANCHISE_CREATE_TIMEOUT", &cfg.Timeouts.Create)
+	env.Duration("FRANCHISE_READ_TIMEOUT", &cfg.Timeouts.Read)
+	env.Duration("FRANCHISE_WRITE_TIMEOUT", &cfg.Timeouts.Write)
+	env.Duration("FRANCHISE_REFRESH_TIMEOUT", &cfg.Timeouts.Refresh)
+	return cfg
+}
+
+// withTimeout aplica el plazo de una operación.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,148 @@
+package franquicia
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/domaininfo"
+	"clubhub-hotel-management/internal/enrichment"
+	"clubhub-hotel-management/internal/website"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Nombres de los enrichers que completan la franquicia al crearla.
+const (
+	EnricherDomainInfo      = "domain-info"
+	EnricherSSL             = "ssl"
+	EnricherName            = "name"
+	EnricherHotelData       = "hotel-data"
+	EnricherBrandAssets     = "brand-assets"
+	EnricherSecurityHeaders = "security-headers"
+	EnricherTechnologies    = "technologies"
+	EnricherEmailSecurity   = "email-security"
+)
+
+// permanentErrors son los errores que no cambian reintentando.
+var permanentErrors = []error{
+	domaininfo.ErrDomainNotFound,
+	domaininfo.ErrNotSupported,
+	website.ErrNoHotelData,
+	website.ErrNoBrandAsset,
+	crawler.ErrDisallowedByRobots,
+}
+
+// enrichers devuelve las fuentes de datos de la creación. Para sumar una alcanza con
+// agregarla acá. Las que escriben en DomainInfo dependen de domain-info, que lo reemplaza
+// entero; las que no tocan los mismos campos corren en paralelo.
+func (s *service) enrichers() []enrichment.Enricher {
+	afterDomainInfo := []string{EnricherDomainInfo}
+	return []enrichment.Enricher{
+		s.enricher(EnricherDomainInfo, 20*time.Second, nil, s.enrichDomainInfo),
+		s.enricher(EnricherSSL, 30*time.Second, afterDomainInfo, s.enrichSSL),
+		s.enricher(EnricherName, 30*time.Second, afterDomainInfo, s.enrichName),
+		s.enricher(EnricherHotelData, 30*time.Second, afterDomainInfo, s.enrichHotelData),
+		s.enricher(EnricherBrandAssets, 60*time.Second, nil, s.enrichBrandAssets),
+		s.enricher(EnricherSecurityHeaders, 30*time.Second, afterDomainInfo, s.enrichSecurityHeaders),
+		s.enricher(EnricherTechnologies, 30*time.Second, nil, s.enrichTechnologies),
+		s.enricher(EnricherEmailSecurity, 30*time.Second, afterDomainInfo, s.enrichEmailSecurity),
+	}
+}
+
+func (s *service) enricher(name string, timeout time.Duration, deps []string, enrich func(context.Context, *domain.Franquicia) error) enrichment.Enricher {
+	return enrichment.Func(name, timeout, deps, func(ctx context.Context, f *domain.Franquicia) error {
+		err := enrich(ctx, f)
+		for _, target := range permanentErrors {
+			if errors.Is(err, target) {
+				return enrichment.Permanent(err)
+			}
+		}
+		return err
+	})
+}
+
+// enrichDomainInfo trae los datos de registro y archiva las respuestas crudas de todos
+// los proveedores consultados, aunque ninguno haya dado un resultado.
+func (s *service) enrichDomainInfo(ctx context.Context, f *domain.Franquicia) error {
+	rec, err := s.getDomainInfo(ctx, f.URL)
+	s.archiveRaw(ctx, f.ID, f.URL, domaininfo.Responses(rec, err))
+	if err != nil {
+		return err
+	}
+	f.DomainInfo = rec.Info
+	f.Location = rec.Location
+	f.LocationSource = rec.Info.Source
+	return nil
+}
+
+func (s *service) enrichSSL(ctx context.Context, f *domain.Franquicia) error {
+	sslInfo, err := s.getSSLInfo(ctx, f.URL)
+	if err != nil {
+		return fmt.Errorf("error obteniendo información SSL: %w", err)
+	}
+	s.assignSSLInfo(f, sslInfo)
+	return nil
+}
+
+// enrichName usa el nombre del registro del dominio solo como último recurso.
+func (s *service) enrichName(ctx context.Context, f *domain.Franquicia) error {
+	var names []domain.NameCandidate
+	if f.Name == "" {
+		names = website.ScrapeNames(ctx, s.crawler, f.URL)
+	}
+	s.assignName(f, names, f.DomainInfo.RegistrarName)
+	if f.Name == "" {
+		return enrichment.Permanent(errors.New("no franchise name found"))
+	}
+	return nil
+}
+
+// enrichHotelData completa contacto y alojamiento desde schema.org; los datos de ubicación
+// del sitio reemplazan a los del registro del dominio campo por campo.
+func (s *service) enrichHotelData(ctx context.Context, f *domain.Franquicia) error {
+	data, err := website.ExtractHotelData(ctx, s.crawler, f.URL)
+	if err != nil {
+		return err
+	}
+	s.assignHotelData(f, data, f.LocationSource)
+	return nil
+}
+
+// enrichBrandAssets guarda una copia del logo y el favicon que encuentre, aunque falte uno.
+func (s *service) enrichBrandAssets(ctx context.Context, f *domain.Franquicia) error {
+	assets, err := s.scrapeBrandAssets(ctx, f.URL)
+	if assets != nil {
+		s.assignBrandAssets(f, assets)
+		s.storeBrandAsset(ctx, f.ID, domain.MediaKindLogo, f.Logo)
+		s.storeBrandAsset(ctx, f.ID, domain.MediaKindFavicon, f.Favicon)
+	}
+	return err
+}
+
+func (s *service) enrichSecurityHeaders(ctx context.Context, f *domain.Franquicia) error {
+	audit, err := website.AuditSecurityHeaders(ctx, s.crawler, f.URL)
+	if err != nil {
+		return err
+	}
+	f.DomainInfo.SecurityHeaders = audit
+	return nil
+}
+
+func (s *service) enrichTechnologies(ctx context.Context, f *domain.Franquicia) error {
+	technologies, err := s.detectTechnologies(ctx, f.URL)
+	if err != nil {
+		return err
+	}
+	f.Technologies = technologies
+	return nil
+}
+
+func (s *service) enrichEmailSecurity(ctx context.Context, f *domain.Franquicia) error {
+	report, err := s.checkEmailSecurity(ctx, f.URL)
+	if err != nil {
+		return err
+	}
+	f.DomainInfo.EmailSecurity = report
+	return nil
+}
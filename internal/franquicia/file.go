@@ -0,0 +1,89 @@
+package franquicia
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// fileCollection es el contenido del archivo, en MongoDB Extended JSON relajado para que
+// se pueda leer a mano sin perder los tipos de BSON.
+type fileCollection struct {
+	Franchises []bson.Raw `bson:"franchises"`
+}
+
+// NewFileRepository crea un repositorio en memoria que se carga desde path y reescribe el
+// archivo entero después de cada escritura. Es para despliegues de un solo nodo: dos
+// procesos con el mismo archivo se pisan los cambios.
+func NewFileRepository(path string) (Repository, error) {
+	r := newMemoryRepository()
+	if err := load(r, path); err != nil {
+		return nil, fmt.Errorf("loading %s: %w", path, err)
+	}
+	r.save = func(docs []bson.Raw) error { return writeCollection(path, docs) }
+	return r, nil
+}
+
+func load(r *memoryRepository, path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var collection fileCollection
+	if err := bson.UnmarshalExtJSON(data, false, &collection); err != nil {
+		return err
+	}
+	for _, doc := range collection.Franchises {
+		id, ok := doc.Lookup("_id").ObjectIDOK()
+		if !ok {
+			return errors.New("franchise without ObjectID _id")
+		}
+		if _, ok := r.docs[id]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateID, id.Hex())
+		}
+		r.docs[id] = doc
+		r.order = append(r.order, id)
+	}
+	return nil
+}
+
+// writeCollection escribe a un temporal y lo renombra para que nunca quede un archivo a
+// medias.
+func writeCollection(path string, docs []bson.Raw) error {
+	if docs == nil {
+		docs = []bson.Raw{}
+	}
+	data, err := bson.MarshalExtJSONIndent(fileCollection{Franchises: docs}, false, false, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".franchises-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
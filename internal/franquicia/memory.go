@@ -0,0 +1,224 @@
+package franquicia
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository guarda las franquicias como documentos BSON, así lo que se lee es una
+// copia y se pierde lo mismo que en Mongo (por ejemplo los campos bson:"-"). Las consultas
+// siguen la semántica del repositorio de Mongo y devuelven los documentos en orden de
+// creación.
+type memoryRepository struct {
+	mu    sync.RWMutex
+	docs  map[primitive.ObjectID]bson.Raw
+	order []primitive.ObjectID
+	// save persiste la colección después de cada escritura; nil en memoria pura.
+	save func(docs []bson.Raw) error
+}
+
+// NewMemoryRepository crea un repositorio en memoria, sin persistencia.
+func NewMemoryRepository() Repository {
+	return newMemoryRepository()
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{docs: map[primitive.ObjectID]bson.Raw{}}
+}
+
+func (r *memoryRepository) Create(ctx context.Context, franquicia *domain.Franquicia) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	doc, err := bson.Marshal(franquicia)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.docs[franquicia.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateID, franquicia.ID.Hex())
+	}
+	r.docs[franquicia.ID] = doc
+	r.order = append(r.order, franquicia.ID)
+	if err := r.persist(); err != nil {
+		delete(r.docs, franquicia.ID)
+		r.order = r.order[:len(r.order)-1]
+		return err
+	}
+	return nil
+}
+
+// Update pisa los mismos campos que el $set del repositorio de Mongo. Como UpdateOne, no
+// falla si la franquicia no existe.
+func (r *memoryRepository) Update(ctx context.Context, f domain.Franquicia) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.docs[f.ID]
+	if !ok {
+		return nil
+	}
+	var stored domain.Franquicia
+	if err := bson.Unmarshal(old, &stored); err != nil {
+		return err
+	}
+	dst := reflect.ValueOf(&stored).Elem()
+	src := reflect.ValueOf(f)
+	for _, i := range updatedFields(f) {
+		dst.Field(i).Set(src.Field(i))
+	}
+	doc, err := bson.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	r.docs[f.ID] = doc
+	if err := r.persist(); err != nil {
+		r.docs[f.ID] = old
+		return err
+	}
+	return nil
+}
+
+// GetOne devuelve ErrNotFound si no existe, igual que el repositorio de Mongo.
+func (r *memoryRepository) GetOne(ctx context.Context, id string) (domain.Franquicia, error) {
+	var franquicia domain.Franquicia
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return franquicia, err
+	}
+	if err := ctx.Err(); err != nil {
+		return franquicia, err
+	}
+
+	r.mu.RLock()
+	doc, ok := r.docs[objID]
+	r.mu.RUnlock()
+	if !ok {
+		return franquicia, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	err = bson.Unmarshal(doc, &franquicia)
+	return franquicia, err
+}
+
+func (r *memoryRepository) GetAll(ctx context.Context) ([]domain.Franquicia, error) {
+	return r.find(ctx, func(domain.Franquicia) bool { return true })
+}
+
+// GetByFranchiseName interpreta name como expresión regular sin distinguir mayúsculas, como
+// el $regex de Mongo. La sintaxis es la de Go (RE2), que no tiene lookarounds ni
+// referencias hacia atrás.
+func (r *memoryRepository) GetByFranchiseName(ctx context.Context, name string) ([]domain.Franquicia, error) {
+	re, err := regexp.Compile("(?i)" + name)
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, func(f domain.Franquicia) bool { return re.MatchString(f.Name) })
+}
+
+func (r *memoryRepository) GetByTechnology(ctx context.Context, name, category string) ([]domain.Franquicia, error) {
+	return r.find(ctx, func(f domain.Franquicia) bool {
+		for _, t := range f.Technologies {
+			if matchesTechnology(t, name, category) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func matchesTechnology(t domain.Technology, name, category string) bool {
+	if name != "" && !strings.EqualFold(t.Name, name) {
+		return false
+	}
+	if category == "" {
+		return true
+	}
+	for _, c := range t.Categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepository) GetByLocation(ctx context.Context, city, country string) ([]domain.Franquicia, error) {
+	return r.find(ctx, func(f domain.Franquicia) bool {
+		return f.Location.City == city && f.Location.Country == country
+	})
+}
+
+func (r *memoryRepository) GetByDateRange(ctx context.Context, dateRange domain.DateRange) ([]domain.Franquicia, error) {
+	return r.find(ctx, func(f domain.Franquicia) bool { return matchesDateRange(f.DomainInfo, dateRange) })
+}
+
+// matchesDateRange sigue a dateRangeFilter: una fecha ausente no entra en ningún rango.
+func matchesDateRange(info domain.DomainInfo, dateRange domain.DateRange) bool {
+	inRange := func(t *time.Time) bool {
+		if t == nil {
+			return false
+		}
+		if dateRange.From != nil && t.Before(*dateRange.From) {
+			return false
+		}
+		return dateRange.To == nil || !t.After(*dateRange.To)
+	}
+
+	created, expiry := inRange(info.CreatedDate), inRange(info.ExpiryDate)
+	switch dateRange.Field {
+	case domain.DateFieldExpiry:
+		return expiry
+	case domain.DateFieldBoth:
+		return created && expiry
+	case domain.DateFieldEither:
+		return created || expiry
+	default:
+		return created
+	}
+}
+
+func (r *memoryRepository) find(ctx context.Context, match func(domain.Franquicia) bool) ([]domain.Franquicia, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var franquicias []domain.Franquicia
+	for _, id := range r.order {
+		var franquicia domain.Franquicia
+		if err := bson.Unmarshal(r.docs[id], &franquicia); err != nil {
+			return nil, err
+		}
+		if match(franquicia) {
+			franquicias = append(franquicias, franquicia)
+		}
+	}
+	return franquicias, nil
+}
+
+// persist se llama con el lock tomado.
+func (r *memoryRepository) persist() error {
+	if r.save == nil {
+		return nil
+	}
+	docs := make([]bson.Raw, len(r.order))
+	for i, id := range r.order {
+		docs[i] = r.docs[id]
+	}
+	return r.save(docs)
+}
@@ -0,0 +1,89 @@
+package franquicia
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/domaininfo"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// legacyDateLayout es el formato con el que se guardaban las fechas del dominio como texto.
+const legacyDateLayout = "2006-01-02 15:04:05"
+
+// MigrateDomainDates convierte a fechas BSON las fechas de dominio guardadas como texto.
+// Los valores que no se pueden interpretar se eliminan y se conservan en el campo *_raw.
+// Es idempotente: solo toca documentos que todavía tienen fechas de tipo string.
+func MigrateDomainDates(ctx context.Context, db *mongo.Collection) (int, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"domain_info.created_date": bson.M{"$type": "string"}},
+		bson.M{"domain_info.expiry_date": bson.M{"$type": "string"}},
+	}}
+	cursor, err := db.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			URL        string             `bson:"url"`
+			DomainInfo struct {
+				CreatedDate interface{} `bson:"created_date"`
+				ExpiryDate  interface{} `bson:"expiry_date"`
+			} `bson:"domain_info"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return migrated, err
+		}
+
+		// El dominio elige las convenciones de fecha del registro; sin él solo se
+		// convierten las fechas sin ambigüedad.
+		domainName, _ := extractDomainName(doc.URL)
+		set, unset := bson.M{}, bson.M{}
+		migrateDate(domainName, doc.DomainInfo.CreatedDate, "domain_info.created_date", set, unset)
+		migrateDate(domainName, doc.DomainInfo.ExpiryDate, "domain_info.expiry_date", set, unset)
+
+		update := bson.M{}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		if _, err := db.UpdateOne(ctx, bson.M{"_id": doc.ID}, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
+
+func migrateDate(domainName string, value interface{}, field string, set, unset bson.M) {
+	s, ok := value.(string)
+	if !ok {
+		return
+	}
+	if s == "" {
+		unset[field] = ""
+		return
+	}
+
+	if t, err := time.Parse(legacyDateLayout, s); err == nil {
+		set[field] = t
+		return
+	}
+	if t, format, err := domaininfo.ParseRegistryDate(domainName, s); err == nil {
+		set[field] = t
+		set[field+"_raw"] = domain.RawDate{Value: s, Format: format}
+		return
+	}
+	unset[field] = ""
+	set[field+"_raw"] = domain.RawDate{Value: s}
+}
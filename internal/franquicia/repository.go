@@ -3,20 +3,34 @@ package franquicia
 import (
 	"clubhub-hotel-management/internal/domain"
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// ErrDuplicateID se devuelve al crear una franquicia con un ID que ya existe.
+var ErrDuplicateID = errors.New("duplicate franchise id")
+
+// ErrNotFound se devuelve cuando GetOne no encuentra la franquicia.
+var ErrNotFound = errors.New("franchise not found")
+
+// Repository guarda las franquicias. Hay implementaciones en Mongo, en memoria y en archivo;
+// todas devuelven ErrNotFound cuando GetOne no encuentra la franquicia.
 type Repository interface {
 	Create(ctx context.Context, franquicia *domain.Franquicia) error
 	Update(ctx context.Context, f domain.Franquicia) error
 	GetOne(ctx context.Context, id string) (domain.Franquicia, error)
 	GetAll(ctx context.Context) ([]domain.Franquicia, error)
-	GetByDateRange(ctx context.Context, startDate, endDate string) ([]domain.Franquicia, error)
+	GetByDateRange(ctx context.Context, dateRange domain.DateRange) ([]domain.Franquicia, error)
 	GetByLocation(ctx context.Context, city, country string) ([]domain.Franquicia, error)
 	GetByFranchiseName(ctx context.Context, name string) ([]domain.Franquicia, error)
+	GetByTechnology(ctx context.Context, name, category string) ([]domain.Franquicia, error)
 }
 
 type repository struct {
@@ -31,33 +45,52 @@ func NewRepository(db *mongo.Collection) Repository {
 
 func (r *repository) Create(ctx context.Context, franquicia *domain.Franquicia) error {
 	_, err := r.db.InsertOne(ctx, franquicia)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicateID, err)
+	}
 	return err
 }
 
 func (r *repository) Update(ctx context.Context, f domain.Franquicia) error {
 	filter := bson.M{"_id": f.ID}
-	update := bson.M{"$set": bson.M{}}
+	set := bson.M{}
 
 	val := reflect.ValueOf(f)
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i)
-		typeField := val.Type().Field(i)
-		tag := typeField.Tag.Get("json")
+	for _, i := range updatedFields(f) {
+		// La clave es el nombre bson del campo, sin opciones como ",omitempty".
+		tag, _, _ := strings.Cut(val.Type().Field(i).Tag.Get("bson"), ",")
+		set[tag] = val.Field(i).Interface()
+	}
+	_, err := r.db.UpdateOne(ctx, filter, bson.M{"$set": set})
+	return err
+}
 
-		if tag == "_id" || (!field.IsValid() || field.IsZero()) {
+// updatedFields devuelve los índices de los campos de f que pisa Update: los que no están
+// vacíos y se guardan con nombre propio, salvo el ID.
+func updatedFields(f domain.Franquicia) []int {
+	var fields []int
+	val := reflect.ValueOf(f)
+	for i := 0; i < val.NumField(); i++ {
+		tag, _, _ := strings.Cut(val.Type().Field(i).Tag.Get("bson"), ",")
+		if tag == "_id" || tag == "" || tag == "-" || val.Field(i).IsZero() {
 			continue
 		}
-
-		update["$set"].(bson.M)[tag] = field.Interface()
+		fields = append(fields, i)
 	}
-	_, err := r.db.UpdateOne(ctx, filter, update)
-	return err
+	return fields
 }
 
 func (r *repository) GetOne(ctx context.Context, id string) (domain.Franquicia, error) {
 	var franquicia domain.Franquicia
-	filter := bson.M{"_id": id}
-	err := r.db.FindOne(ctx, filter).Decode(&franquicia)
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return franquicia, err
+	}
+	filter := bson.M{"_id": objID}
+	err = r.db.FindOne(ctx, filter).Decode(&franquicia)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return franquicia, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
 	return franquicia, err
 }
 
@@ -100,6 +133,35 @@ func (r *repository) GetByFranchiseName(ctx context.Context, name string) ([]dom
 	return franquicias, nil
 }
 
+// GetByTechnology busca las franquicias con una tecnología detectada por nombre y/o
+// categoría, sin distinguir mayúsculas.
+func (r *repository) GetByTechnology(ctx context.Context, name, category string) ([]domain.Franquicia, error) {
+	var franquicias []domain.Franquicia
+	match := bson.M{}
+	if name != "" {
+		match["name"] = bson.M{"$regex": "^" + regexp.QuoteMeta(name) + "$", "$options": "i"}
+	}
+	if category != "" {
+		match["categories"] = bson.M{"$regex": "^" + regexp.QuoteMeta(category) + "$", "$options": "i"}
+	}
+	filter := bson.M{"technologies": bson.M{"$elemMatch": match}}
+	cursor, err := r.db.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var franquicia domain.Franquicia
+		if err := cursor.Decode(&franquicia); err != nil {
+			return nil, err
+		}
+		franquicias = append(franquicias, franquicia)
+	}
+
+	return franquicias, nil
+}
+
 func (r *repository) GetByLocation(ctx context.Context, city, country string) ([]domain.Franquicia, error) {
 	var franquicias []domain.Franquicia
 	filter := bson.M{
@@ -123,12 +185,9 @@ func (r *repository) GetByLocation(ctx context.Context, city, country string) ([
 	return franquicias, nil
 }
 
-func (r *repository) GetByDateRange(ctx context.Context, startDate, endDate string) ([]domain.Franquicia, error) {
+func (r *repository) GetByDateRange(ctx context.Context, dateRange domain.DateRange) ([]domain.Franquicia, error) {
 	var franquicias []domain.Franquicia
-	filter := bson.M{
-		"domain_info.created_date": bson.M{"$gte": startDate},
-		"domain_info.expiry_date":  bson.M{"$lte": endDate},
-	}
+	filter := dateRangeFilter(dateRange)
 	cursor, err := r.db.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -145,3 +204,26 @@ func (r *repository) GetByDateRange(ctx context.Context, startDate, endDate stri
 
 	return franquicias, nil
 }
+
+func dateRangeFilter(dateRange domain.DateRange) bson.M {
+	bounds := bson.M{}
+	if dateRange.From != nil {
+		bounds["$gte"] = *dateRange.From
+	}
+	if dateRange.To != nil {
+		bounds["$lte"] = *dateRange.To
+	}
+
+	created := bson.M{"domain_info.created_date": bounds}
+	expiry := bson.M{"domain_info.expiry_date": bounds}
+	switch dateRange.Field {
+	case domain.DateFieldExpiry:
+		return expiry
+	case domain.DateFieldBoth:
+		return bson.M{"$and": bson.A{created, expiry}}
+	case domain.DateFieldEither:
+		return bson.M{"$or": bson.A{created, expiry}}
+	default:
+		return created
+	}
+}
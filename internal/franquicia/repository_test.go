@@ -0,0 +1,451 @@
+package franquicia
+
+import (
+	"clubhub-hotel-management/internal/db"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// repositoryFactories crean un repositorio vacío por prueba. Todas las implementaciones
+// pasan por la misma suite, así responden igual; Mongo y PostgreSQL la corren aparte
+// porque necesitan un servidor.
+var repositoryFactories = map[string]func(t *testing.T) Repository{
+	"memory": func(t *testing.T) Repository {
+		return NewMemoryRepository()
+	},
+	"file": func(t *testing.T) Repository {
+		r, err := NewFileRepository(filepath.Join(t.TempDir(), "franchises.json"))
+		if err != nil {
+			t.Fatalf("NewFileRepository: %v", err)
+		}
+		return r
+	},
+	"sqlite": func(t *testing.T) Repository {
+		database, err := db.OpenSQLite(filepath.Join(t.TempDir(), "franchises.db"))
+		if err != nil {
+			t.Fatalf("OpenSQLite: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		return migratedSQLRepository(t, database, DialectSQLite)
+	},
+}
+
+func TestRepositoryConformance(t *testing.T) {
+	for name, newRepository := range repositoryFactories {
+		t.Run(name, func(t *testing.T) {
+			testRepository(t, newRepository)
+		})
+	}
+}
+
+// TestMongoRepositoryConformance corre la suite contra el MongoDB de
+// FRANCHISE_TEST_MONGO_URI; cada prueba usa una base propia que se borra al final.
+func TestMongoRepositoryConformance(t *testing.T) {
+	uri := os.Getenv("FRANCHISE_TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("FRANCHISE_TEST_MONGO_URI is not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(db.NewRegistry()))
+	if err != nil {
+		t.Fatalf("mongo.Connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	if err := db.PingMongoDB(ctx, client); err != nil {
+		t.Fatalf("PingMongoDB: %v", err)
+	}
+
+	testRepository(t, func(t *testing.T) Repository {
+		database := client.Database("franchise_test_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() {
+			if err := database.Drop(context.Background()); err != nil {
+				t.Errorf("Drop: %v", err)
+			}
+		})
+		return NewRepository(database.Collection("franchises"))
+	})
+}
+
+// TestPostgresRepositoryConformance corre la suite contra el PostgreSQL de
+// FRANCHISE_TEST_POSTGRES_DSN; cada prueba usa un esquema propio que se borra al final.
+func TestPostgresRepositoryConformance(t *testing.T) {
+	newDatabase := postgresSchemas(t)
+	testRepository(t, func(t *testing.T) Repository {
+		return migratedSQLRepository(t, newDatabase(t), DialectPostgres)
+	})
+}
+
+func TestMigrateSQLPostgresConcurrent(t *testing.T) {
+	database := postgresSchemas(t)(t)
+
+	const instances = 4
+	applied := make(chan int, instances)
+	errs := make(chan error, instances)
+	for i := 0; i < instances; i++ {
+		go func() {
+			n, err := MigrateSQL(context.Background(), database, DialectPostgres)
+			applied <- n
+			errs <- err
+		}()
+	}
+	total := 0
+	for i := 0; i < instances; i++ {
+		total += <-applied
+		if err := <-errs; err != nil {
+			t.Errorf("MigrateSQL: %v", err)
+		}
+	}
+	if total != len(sqlMigrations) {
+		t.Errorf("applied %d migrations in total, want %d", total, len(sqlMigrations))
+	}
+}
+
+// postgresSchemas omite la prueba si FRANCHISE_TEST_POSTGRES_DSN no está definido y
+// devuelve una función que abre una conexión a un esquema nuevo y vacío.
+func postgresSchemas(t *testing.T) func(t *testing.T) *sql.DB {
+	dsn := os.Getenv("FRANCHISE_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("FRANCHISE_TEST_POSTGRES_DSN is not set")
+	}
+	admin, err := db.OpenPostgres(dsn)
+	if err != nil {
+		t.Fatalf("OpenPostgres: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	return func(t *testing.T) *sql.DB {
+		schema := "franchise_test_" + primitive.NewObjectID().Hex()
+		if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+			t.Fatalf("CREATE SCHEMA: %v", err)
+		}
+		t.Cleanup(func() {
+			if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+				t.Errorf("DROP SCHEMA: %v", err)
+			}
+		})
+		database, err := db.OpenPostgres(withSearchPath(t, dsn, schema))
+		if err != nil {
+			t.Fatalf("OpenPostgres: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		return database
+	}
+}
+
+// withSearchPath agrega search_path al DSN, tanto en formato URL como clave=valor.
+func withSearchPath(t *testing.T, dsn, schema string) string {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return fmt.Sprintf("%s search_path=%s", dsn, schema)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("parse FRANCHISE_TEST_POSTGRES_DSN: %v", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func testRepository(t *testing.T, newRepository func(t *testing.T) Repository) {
+	ctx := context.Background()
+
+	t.Run("create and get one", func(t *testing.T) {
+		r := newRepository(t)
+		want := fullFranquicia()
+		if err := r.Create(ctx, &want); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		got, err := r.GetOne(ctx, want.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetOne: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetOne =\n%+v\nwant\n%+v", got, want)
+		}
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		r := newRepository(t)
+		f := testFranquicia("Hotel Sol")
+		mustCreate(t, r, f)
+		if err := r.Create(ctx, &f); !errors.Is(err, ErrDuplicateID) {
+			t.Errorf("Create duplicate = %v, want ErrDuplicateID", err)
+		}
+	})
+
+	t.Run("get one not found", func(t *testing.T) {
+		r := newRepository(t)
+		if _, err := r.GetOne(ctx, primitive.NewObjectID().Hex()); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetOne missing = %v, want ErrNotFound", err)
+		}
+		if _, err := r.GetOne(ctx, "not-an-id"); !errors.Is(err, primitive.ErrInvalidHex) {
+			t.Errorf("GetOne invalid id = %v, want primitive.ErrInvalidHex", err)
+		}
+	})
+
+	t.Run("update sets non-empty fields", func(t *testing.T) {
+		r := newRepository(t)
+		f := fullFranquicia()
+		mustCreate(t, r, f)
+
+		update := domain.Franquicia{
+			ID:           f.ID,
+			Name:         "Hotel Luna",
+			Technologies: []domain.Technology{{Name: "WordPress", Categories: []string{"CMS"}, Confidence: 100}},
+		}
+		if err := r.Update(ctx, update); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		want := f
+		want.Name = update.Name
+		want.Technologies = update.Technologies
+		got, err := r.GetOne(ctx, f.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetOne: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("after Update =\n%+v\nwant\n%+v", got, want)
+		}
+
+		if err := r.Update(ctx, domain.Franquicia{ID: primitive.NewObjectID(), Name: "Nadie"}); err != nil {
+			t.Errorf("Update missing = %v, want nil", err)
+		}
+	})
+
+	t.Run("get all in creation order", func(t *testing.T) {
+		r := newRepository(t)
+		if got, err := r.GetAll(ctx); err != nil || len(got) != 0 {
+			t.Fatalf("GetAll empty = %v, %v", got, err)
+		}
+		for _, name := range []string{"Zeta", "Alfa", "Beta"} {
+			mustCreate(t, r, testFranquicia(name))
+		}
+		got, err := r.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		assertNames(t, got, "Zeta", "Alfa", "Beta")
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		r := newRepository(t)
+		sol := testFranquicia("Hotel Sol")
+		sol.Location = domain.Location{City: "Rosario", Country: "AR"}
+		sol.DomainInfo.CreatedDate = date(2010, 5, 1)
+		sol.DomainInfo.ExpiryDate = date(2030, 5, 1)
+		sol.Technologies = []domain.Technology{
+			{Name: "WordPress", Categories: []string{"CMS"}, Confidence: 100},
+			{Name: "Cloudflare", Categories: []string{"CDN"}, Confidence: 80},
+		}
+		luna := testFranquicia("Luna Resort")
+		luna.Location = domain.Location{City: "Rosario", Country: "UY"}
+		luna.DomainInfo.CreatedDate = date(2020, 1, 1)
+		luna.Technologies = []domain.Technology{{Name: "Drupal", Categories: []string{"CMS"}, Confidence: 100}}
+		mar := testFranquicia("hotel del mar")
+		mar.Location = domain.Location{City: "Rosario", Country: "AR"}
+		mar.DomainInfo.ExpiryDate = date(2025, 12, 31)
+		for _, f := range []domain.Franquicia{sol, luna, mar} {
+			mustCreate(t, r, f)
+		}
+
+		t.Run("location", func(t *testing.T) {
+			got, err := r.GetByLocation(ctx, "Rosario", "AR")
+			if err != nil {
+				t.Fatalf("GetByLocation: %v", err)
+			}
+			assertNames(t, got, "Hotel Sol", "hotel del mar")
+		})
+
+		t.Run("name", func(t *testing.T) {
+			tests := []struct {
+				pattern string
+				want    []string
+			}{
+				{"hotel", []string{"Hotel Sol", "hotel del mar"}},
+				{"^luna", []string{"Luna Resort"}},
+				{"sol|mar$", []string{"Hotel Sol", "hotel del mar"}},
+				{"nada", nil},
+			}
+			for _, tt := range tests {
+				got, err := r.GetByFranchiseName(ctx, tt.pattern)
+				if err != nil {
+					t.Fatalf("GetByFranchiseName(%q): %v", tt.pattern, err)
+				}
+				assertNames(t, got, tt.want...)
+			}
+			if _, err := r.GetByFranchiseName(ctx, "("); err == nil {
+				t.Error("GetByFranchiseName with an invalid pattern: want error")
+			}
+		})
+
+		t.Run("technology", func(t *testing.T) {
+			tests := []struct {
+				name, category string
+				want           []string
+			}{
+				{"wordpress", "", []string{"Hotel Sol"}},
+				{"", "cms", []string{"Hotel Sol", "Luna Resort"}},
+				{"Cloudflare", "CDN", []string{"Hotel Sol"}},
+				// Nombre y categoría tienen que ser de la misma tecnología.
+				{"Cloudflare", "CMS", nil},
+			}
+			for _, tt := range tests {
+				got, err := r.GetByTechnology(ctx, tt.name, tt.category)
+				if err != nil {
+					t.Fatalf("GetByTechnology(%q, %q): %v", tt.name, tt.category, err)
+				}
+				assertNames(t, got, tt.want...)
+			}
+		})
+
+		t.Run("date range", func(t *testing.T) {
+			tests := []struct {
+				name     string
+				from, to *time.Time
+				field    domain.DateRangeField
+				want     []string
+			}{
+				{"created", date(2010, 1, 1), date(2015, 1, 1), domain.DateFieldCreated, []string{"Hotel Sol"}},
+				{"bounds are inclusive", date(2010, 5, 1), date(2020, 1, 1), domain.DateFieldCreated, []string{"Hotel Sol", "Luna Resort"}},
+				{"open start", nil, date(2026, 1, 1), domain.DateFieldExpiry, []string{"hotel del mar"}},
+				{"both", date(2000, 1, 1), date(2040, 1, 1), domain.DateFieldBoth, []string{"Hotel Sol"}},
+				{"either", date(2019, 1, 1), date(2026, 1, 1), domain.DateFieldEither, []string{"Luna Resort", "hotel del mar"}},
+			}
+			for _, tt := range tests {
+				got, err := r.GetByDateRange(ctx, domain.DateRange{From: tt.from, To: tt.to, Field: tt.field})
+				if err != nil {
+					t.Fatalf("GetByDateRange %s: %v", tt.name, err)
+				}
+				assertNames(t, got, tt.want...)
+			}
+		})
+	})
+}
+
+func TestFileRepositoryPersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "franchises.json")
+	r, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("NewFileRepository: %v", err)
+	}
+	f := fullFranquicia()
+	mustCreate(t, r, f)
+	if err := r.Update(ctx, domain.Franquicia{ID: f.ID, Name: "Hotel Luna"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	reopened, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	got, err := reopened.GetOne(ctx, f.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetOne after reopening: %v", err)
+	}
+	want := f
+	want.Name = "Hotel Luna"
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetOne after reopening =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// migratedSQLRepository migra el esquema en database y devuelve el repositorio.
+func migratedSQLRepository(t *testing.T, database *sql.DB, dialect Dialect) Repository {
+	t.Helper()
+	if _, err := MigrateSQL(context.Background(), database, dialect); err != nil {
+		t.Fatalf("MigrateSQL: %v", err)
+	}
+	return NewSQLRepository(database, dialect)
+}
+
+func mustCreate(t *testing.T, r Repository, f domain.Franquicia) {
+	t.Helper()
+	if err := r.Create(context.Background(), &f); err != nil {
+		t.Fatalf("Create %s: %v", f.Name, err)
+	}
+}
+
+func assertNames(t *testing.T, got []domain.Franquicia, want ...string) {
+	t.Helper()
+	var names []string
+	for _, f := range got {
+		names = append(names, f.Name)
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func testFranquicia(name string) domain.Franquicia {
+	return domain.Franquicia{
+		ID:   primitive.NewObjectID(),
+		Name: name,
+		URL:  "https://example.com",
+	}
+}
+
+// fullFranquicia completa todos los campos que guardan los repositorios, incluidos los que
+// SQL lleva a tablas hijas.
+func fullFranquicia() domain.Franquicia {
+	mediaID := primitive.NewObjectID()
+	checkedAt := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	f := testFranquicia("Hotel Sol")
+	f.NameSource = "og:site_name"
+	f.Location = domain.Location{City: "Rosario", Region: "Santa Fe", Country: "AR", Address: "Córdoba 1234", ZipCode: "2000", Latitude: -32.95, Longitude: -60.66}
+	f.LocationSource = "schema.org"
+	f.Contact = &domain.Contact{Telephone: "+54 341 555 0000", Email: "reservas@example.com"}
+	f.Lodging = &domain.LodgingDetails{Type: "Hotel", StarRating: 4, CheckinTime: "14:00", Amenities: []string{"pool", "wifi"}}
+	f.LogoURL = "https://example.com/logo.png"
+	f.Logo = &domain.BrandAsset{MediaID: &mediaID, URL: "https://example.com/logo.png", ContentType: "image/png", Width: 200, Height: 80, Size: 1024, Source: "header", Score: 90}
+	f.IsWebsiteLive = true
+	f.DomainInfo = domain.DomainInfo{
+		CreatedDate:      date(2010, 5, 1),
+		ExpiryDate:       date(2030, 5, 1),
+		RegistrarName:    "Registrar SA",
+		ContactEmail:     "hostmaster@example.com",
+		Protocol:         "HTTPS",
+		IsProtocolSecure: true,
+		ServerHops:       []string{"edge-1", "edge-2"},
+		SSLGrade:         "A",
+		DNSRecords:       []domain.DNSRecord{{Type: "MX", Value: "mail.example.com", TTL: 300, Priority: 10}},
+		RegistrarInfo:    domain.RegistrarInfo{Organization: "Registrar SA", Country: "AR", Email: "abuse@registrar.example"},
+		TechnicalInfo:    domain.TechnicalInfo{Organization: "Hosting SRL", City: "Rosario"},
+		Source:           "rdap",
+		CreatedDateRaw:   &domain.RawDate{Value: "2010-05-01T00:00:00Z", Format: "2006-01-02T15:04:05Z07:00"},
+		SecurityHeaders: &domain.SecurityAudit{
+			URL:       "https://example.com",
+			Score:     80,
+			Grade:     "B",
+			Checks:    []domain.SecurityCheck{{Name: "hsts", Pass: true, Value: "max-age=31536000"}},
+			CheckedAt: checkedAt,
+		},
+	}
+	f.Technologies = []domain.Technology{
+		{Name: "WordPress", Categories: []string{"CMS", "Blogs"}, Version: "6.4", Confidence: 100, Sources: []string{"meta"}},
+		{Name: "Cloudflare", Categories: []string{"CDN"}, Confidence: 80},
+	}
+	f.Enrichment = []domain.EnrichmentResult{{Name: EnricherDomainInfo, Status: domain.EnrichmentOK, DurationMs: 120}}
+	return f
+}
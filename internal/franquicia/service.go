@@ -1,125 +1,279 @@
 package franquicia
 
 import (
+	"clubhub-hotel-management/internal/crawler"
 	"clubhub-hotel-management/internal/domain"
-	"crypto/tls"
+	"clubhub-hotel-management/internal/domaininfo"
+	"clubhub-hotel-management/internal/enrichment"
+	"clubhub-hotel-management/internal/fingerprint"
+	"clubhub-hotel-management/internal/mailsec"
+	"clubhub-hotel-management/internal/media"
+	"clubhub-hotel-management/internal/website"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/gocolly/colly/v2"
-	"github.com/joho/godotenv"
-	"github.com/likexian/whois"
-	whoisparser "github.com/likexian/whois-parser"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type service struct {
-	repo Repository
-}
+var (
+	ErrInvalidDateRange       = errors.New("invalid date range")
+	ErrInvalidTechnologyQuery = errors.New("invalid technology query")
+	ErrInvalidCreateOptions   = errors.New("invalid create options")
+	ErrEnrichmentFailed       = errors.New("required enrichment steps failed")
+)
 
-func init() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal(err)
-	}
+type service struct {
+	repo       Repository
+	domainInfo domaininfo.DomainInfoProvider
+	archive    domaininfo.ArchiveRepository
+	media      media.Service
+	crawler    crawler.Factory
+	techRules  fingerprint.RuleSource
+	mailsec    mailsec.Checker
+	enrichment *enrichment.Registry
+	timeouts   Timeouts
 }
 
 type Service interface {
-	CreateFranquicia(*gin.Context, *domain.Franquicia) error
-	getSSLInfo(*gin.Context, string) (*domain.SSLInfo, error)
-	scrapeLogoURL(*gin.Context, string) (string, error)
-	getDomainInfo(ctx *gin.Context, domainReq string) (*domain.DomainInfo, *domain.Location, error)
-
-	GetFranquiciaByID(ctx *gin.Context, id string) (domain.Franquicia, error)
-	GetByLocation(ctx *gin.Context, city, country string) ([]domain.Franquicia, error)
-	GetByDateRange(ctx *gin.Context, startDate, endDate string) ([]domain.Franquicia, error)
-	GetByFranchiseName(ctx *gin.Context, name string) ([]domain.Franquicia, error)
-	GetAllFranquicias(*gin.Context) ([]domain.Franquicia, error)
-	UpdateFranquicia(*gin.Context, domain.Franquicia) error
+	CreateFranquicia(context.Context, *domain.Franquicia, domain.CreateOptions) error
+	getSSLInfo(context.Context, string) (*domain.SSLInfo, error)
+	scrapeBrandAssets(context.Context, string) (*website.BrandAssets, error)
+	getDomainInfo(ctx context.Context, domainReq string) (*domaininfo.Record, error)
+
+	GetFranquiciaByID(ctx context.Context, id string) (domain.Franquicia, error)
+	GetByLocation(ctx context.Context, city, country string) ([]domain.Franquicia, error)
+	GetByDateRange(ctx context.Context, from, to string, field domain.DateRangeField) ([]domain.Franquicia, error)
+	GetByFranchiseName(ctx context.Context, name string) ([]domain.Franquicia, error)
+	GetByTechnology(ctx context.Context, filter domain.TechnologyFilter) ([]domain.Franquicia, error)
+	GetAllFranquicias(context.Context) ([]domain.Franquicia, error)
+	UpdateFranquicia(context.Context, domain.Franquicia) error
+
+	GetRawDomainInfo(ctx context.Context, id string) ([]domain.RawDomainResponse, error)
+	ReparseDomainInfo(ctx context.Context, id string) (domain.Franquicia, error)
+	AuditSecurityHeaders(ctx context.Context, id string) (domain.Franquicia, error)
+	DetectTechnologies(ctx context.Context, id string) (domain.Franquicia, error)
+	CheckEmailSecurity(ctx context.Context, id string) (domain.Franquicia, error)
 }
 
-// NewService crea un nuevo servicio de franquicia.
-func NewService(r Repository) Service {
-	return &service{
-		repo: r,
+// NewService crea un nuevo servicio de franquicia. archive y mediaService pueden ser nil
+// cuando no hay MongoDB: entonces no se archivan las respuestas WHOIS/RDAP ni se guardan
+// copias del logo y el favicon.
+func NewService(r Repository, domainInfo domaininfo.DomainInfoProvider, archive domaininfo.ArchiveRepository, mediaService media.Service, crawler crawler.Factory, techRules fingerprint.RuleSource, mailChecker mailsec.Checker, cfg Config) Service {
+	s := &service{
+		repo:       r,
+		domainInfo: domainInfo,
+		archive:    archive,
+		media:      mediaService,
+		crawler:    crawler,
+		techRules:  techRules,
+		mailsec:    mailChecker,
+		timeouts:   cfg.Timeouts,
 	}
+	registry, err := enrichment.NewRegistry(cfg.Enrichment, s.enrichers()...)
+	if err != nil {
+		log.Fatalf("Error al registrar los enrichers: %v", err)
+	}
+	s.enrichment = registry
+	return s
 }
 
-// CreateFranquicia maneja la creación de una nueva franquicia.
-func (s *service) CreateFranquicia(ctx *gin.Context, req *domain.Franquicia) error {
+// CreateFranquicia maneja la creación de una nueva franquicia. Los enrichers completan
+// los datos y el resultado de cada uno queda en Enrichment, también cuando la creación
+// falla. En modo strict, si falla un paso requerido no se guarda nada. Los enrichers que no
+// terminan dentro del plazo Create quedan fallidos o salteados.
+func (s *service) CreateFranquicia(ctx context.Context, req *domain.Franquicia, opts domain.CreateOptions) error {
+	required, err := s.requiredSteps(opts)
+	if err != nil {
+		return err
+	}
 	log.Println("Iniciando la creación de franquicia")
 
-	var info *domain.DomainInfo
-	var location *domain.Location
-	var sslInfo *domain.SSLInfo
-	var wg sync.WaitGroup
-	errs := make(chan error, 3)
+	// El ID se asigna antes porque los enrichers guardan imágenes y respuestas crudas
+	// asociadas a la franquicia.
+	req.ID = primitive.NewObjectID()
+	enrichCtx, cancel := withTimeout(ctx, s.timeouts.Create)
+	req.Enrichment = s.enrichment.Run(enrichCtx, req)
+	cancel()
 
-	var err error
+	if failed := failedSteps(req.Enrichment, required); len(failed) > 0 {
+		s.discardEnrichment(ctx, req.ID)
+		return fmt.Errorf("%w: %s", ErrEnrichmentFailed, strings.Join(failed, ", "))
+	}
 
-	wg.Add(2)
+	saveCtx, cancel := withTimeout(ctx, s.timeouts.Write)
+	defer cancel()
+	if err := s.repo.Create(saveCtx, req); err != nil {
+		log.Printf("Error al crear franquicia: %v", err)
+		s.discardEnrichment(ctx, req.ID)
+		return err
+	}
 
-	// Goroutine para obtener información SSL
-	go func() {
-		defer wg.Done()
-		sslInfo, err = s.getSSLInfo(ctx, req.URL)
-		if err != nil {
-			errs <- fmt.Errorf("error obteniendo información SSL: %w", err)
-			return
-		}
-		s.assignSSLInfo(req, sslInfo)
-	}()
+	log.Println("Franquicia creada con éxito: ", req)
+	return nil
+}
 
-	go func() {
-		defer wg.Done()
-		info, location, err = s.getDomainInfo(ctx, req.URL)
-		if err != nil {
-			errs <- err
-			return
-		}
-		req.DomainInfo = *info
-		req.Location = *location
+// defaultRequiredSteps son los pasos que exige el modo strict si no se indica ninguno.
+var defaultRequiredSteps = []string{EnricherDomainInfo, EnricherSSL}
 
-	}()
+// requiredSteps devuelve los enrichers que tienen que salir bien para guardar la franquicia.
+func (s *service) requiredSteps(opts domain.CreateOptions) (map[string]bool, error) {
+	switch opts.Mode {
+	case "", domain.CreateBestEffort:
+		if len(opts.Required) > 0 {
+			return nil, fmt.Errorf("%w: required steps need mode %q", ErrInvalidCreateOptions, domain.CreateStrict)
+		}
+		return nil, nil
+	case domain.CreateStrict:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidCreateOptions, opts.Mode)
+	}
 
-	// go func() {
-	// 	defer wg.Done()
-	// 	logoURL, err := s.scrapeLogoURL(ctx, ensureURLScheme(req.URL))
-	// 	if err != nil {
-	// 		errs <- err
-	// 		return
-	// 	}
-	// 	req.LogoURL = logoURL
-	// }()
+	names := s.enrichment.Names()
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+	steps := opts.Required
+	if len(steps) == 0 {
+		steps = defaultRequiredSteps
+	}
+	required := map[string]bool{}
+	for _, name := range steps {
+		if !known[name] {
+			return nil, fmt.Errorf("%w: unknown step %q, valid steps are %s", ErrInvalidCreateOptions, name, strings.Join(names, ", "))
+		}
+		required[name] = true
+	}
+	return required, nil
+}
 
-	wg.Wait()
-	close(errs)
+func failedSteps(results []domain.EnrichmentResult, required map[string]bool) []string {
+	var failed []string
+	for _, r := range results {
+		if required[r.Name] && r.Status != domain.EnrichmentOK {
+			failed = append(failed, r.Name)
+		}
+	}
+	return failed
+}
 
-	log.Println("Finalizando la espera de goroutines en CreateFranquicia")
+// discardEnrichment borra las imágenes que los enrichers guardaron para una franquicia que
+// no se creó. Se hace aunque el cliente ya se haya desconectado. Las respuestas crudas de
+// WHOIS/RDAP se conservan para poder revisar por qué falló.
+func (s *service) discardEnrichment(ctx context.Context, franquiciaID primitive.ObjectID) {
+	if s.media == nil {
+		return
+	}
+	ctx, cancel := withTimeout(context.WithoutCancel(ctx), s.timeouts.Write)
+	defer cancel()
+	if err := s.media.DeleteAssets(ctx, franquiciaID); err != nil {
+		log.Printf("Error al borrar las imágenes de %s: %v", franquiciaID.Hex(), err)
+	}
+}
 
-	for err := range errs {
+// archiveRaw guarda las respuestas crudas de WHOIS/RDAP. Un error no impide la creación.
+// Se guardan aunque el contexto de la consulta haya vencido.
+func (s *service) archiveRaw(ctx context.Context, franquiciaID primitive.ObjectID, url string, responses []domaininfo.Response) {
+	if s.archive == nil || len(responses) == 0 {
+		return
+	}
+	ctx, cancel := withTimeout(context.WithoutCancel(ctx), s.timeouts.Write)
+	defer cancel()
+	domainName, _ := extractDomainName(url)
+	for _, resp := range responses {
+		res, err := domaininfo.NewRawResponse(franquiciaID, domainName, resp)
+		if err == nil {
+			err = s.archive.Save(ctx, res)
+		}
 		if err != nil {
-			return err
+			log.Printf("Error al archivar la respuesta %s: %v", resp.Source, err)
 		}
 	}
+}
 
-	req.ID = primitive.NewObjectID()
-	req.Name = info.RegistrarName
-	log.Println("Finalizando la espera de goroutines en CreateFranquicia")
-	err = s.repo.Create(ctx, req)
+// storeBrandAsset guarda una copia del logo o favicon para no depender del sitio de la
+// franquicia. Un error no impide la creación: queda la URL original.
+func (s *service) storeBrandAsset(ctx context.Context, franquiciaID primitive.ObjectID, kind domain.MediaKind, asset *domain.BrandAsset) {
+	if s.media == nil || asset == nil || len(asset.Data) == 0 {
+		return
+	}
+	stored, err := s.media.Store(ctx, franquiciaID, "", kind, asset.URL, asset.Data)
 	if err != nil {
-		log.Printf("Error al crear franquicia: %v", err)
-		return err
+		log.Printf("Error al guardar el %s de la franquicia: %v", kind, err)
+		return
 	}
+	asset.MediaID = &stored.ID
+}
 
-	log.Println("Franquicia creada con éxito: ", req)
-	return nil
+// assignHotelData completa contacto y datos de alojamiento desde el marcado schema.org. La
+// ubicación del sitio tiene prioridad sobre la de WHOIS/RDAP, que suele ser la dirección
+// administrativa del titular del dominio y no la del hotel.
+func (s *service) assignHotelData(req *domain.Franquicia, data *website.HotelData, registrySource string) {
+	req.LocationSource = registrySource
+	if data == nil {
+		return
+	}
+	if data.HasLocation() {
+		req.Location = mergeLocation(req.Location, data.Location)
+		req.LocationSource = data.Source
+	}
+	if data.Contact != (domain.Contact{}) {
+		contact := data.Contact
+		req.Contact = &contact
+	}
+	lodging := data.Lodging
+	req.Lodging = &lodging
+}
+
+// mergeLocation completa base con los campos de markup campo por campo: lo que el sitio
+// no publica (p. ej. el país) se conserva. Las coordenadas van juntas.
+func mergeLocation(base, markup domain.Location) domain.Location {
+	merged := base
+	set := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	set(&merged.Address, markup.Address)
+	set(&merged.City, markup.City)
+	set(&merged.Region, markup.Region)
+	set(&merged.Country, markup.Country)
+	set(&merged.ZipCode, markup.ZipCode)
+	if markup.Latitude != 0 && markup.Longitude != 0 {
+		merged.Latitude, merged.Longitude = markup.Latitude, markup.Longitude
+	}
+	return merged
+}
+
+// assignName respeta el nombre enviado por el cliente; si no hay, elige el mejor candidato
+// del sitio y usa WHOIS solo como último recurso.
+func (s *service) assignName(req *domain.Franquicia, scraped []domain.NameCandidate, whoisName string) {
+	if req.Name != "" {
+		req.NameSource = website.NameSourceRequest
+		return
+	}
+
+	ranked := website.ResolveName(scraped, req.URL, whoisName)
+	if len(ranked) == 0 {
+		return
+	}
+	req.Name = ranked[0].Name
+	req.NameSource = ranked[0].Source
+	log.Printf("Nombre de franquicia %q elegido desde %s", req.Name, req.NameSource)
+}
+
+func (s *service) assignBrandAssets(req *domain.Franquicia, assets *website.BrandAssets) {
+	req.Logo = assets.Logo
+	req.Favicon = assets.Favicon
+	if assets.Logo != nil {
+		req.LogoURL = assets.Logo.URL
+	}
 }
 
 func (s *service) assignSSLInfo(req *domain.Franquicia, sslInfo *domain.SSLInfo) {
@@ -133,67 +287,29 @@ func (s *service) assignSSLInfo(req *domain.Franquicia, sslInfo *domain.SSLInfo)
 	}
 }
 
-func (s *service) getSSLInfo(ctx *gin.Context, url string) (*domain.SSLInfo, error) {
-	log.Printf("Obteniendo información SSL para URL: %s", url)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+// getSSLInfo consulta SSL Labs con el crawler compartido, que verifica el certificado de
+// la API y aplica sus plazos y reintentos.
+func (s *service) getSSLInfo(ctx context.Context, siteURL string) (*domain.SSLInfo, error) {
+	log.Printf("Obteniendo información SSL para URL: %s", siteURL)
+	host, err := extractDomainName(siteURL)
+	if err != nil {
+		return nil, err
 	}
-	client := &http.Client{Transport: tr}
-
-	apiURL := fmt.Sprintf("https://api.ssllabs.com/api/v3/analyze?host=%s", url)
-
-	resp, err := client.Get(apiURL)
+	resp, err := s.crawler.Fetch(ctx, "https://api.ssllabs.com/api/v3/analyze?host="+url.QueryEscape(host))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	var sslInfo domain.SSLInfo
-	if err := json.NewDecoder(resp.Body).Decode(&sslInfo); err != nil {
+	if err := json.Unmarshal(resp.Body, &sslInfo); err != nil {
 		return nil, err
 	}
-
 	return &sslInfo, nil
 }
 
-func (s *service) scrapeLogoURL(ctx *gin.Context, url string) (string, error) {
-	log.Printf("Buscando logo en URL: %s", url)
-	c := colly.NewCollector(
-		colly.Async(true),
-		// colly timeout
-		colly.MaxDepth(1),
-	)
-	c.WithTransport(&http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	})
-
-	var logoURL string
-	var found bool
-
-	c.OnHTML("img", func(e *colly.HTMLElement) {
-		if !found {
-			logoURL = e.Request.AbsoluteURL(e.Attr("src"))
-			log.Println("Found image:", logoURL)
-			found = true
-		}
-	})
-
-	c.OnError(func(r *colly.Response, err error) {
-		log.Println("Request URL:", r.Request.URL, "failed with response:", r, "\nError:", err)
-	})
-
-	err := c.Visit(url)
-	if err != nil {
-		return "", err
-	}
-
-	c.Wait()
-
-	if logoURL == "" {
-		return "", fmt.Errorf("no se pudo encontrar el logo en la URL proporcionada")
-	}
-
-	return logoURL, nil
+func (s *service) scrapeBrandAssets(ctx context.Context, url string) (*website.BrandAssets, error) {
+	log.Printf("Buscando logo y favicon en URL: %s", url)
+	return website.ExtractBrandAssets(ctx, s.crawler, url)
 }
 
 func ensureURLScheme(urlStr string) string {
@@ -211,118 +327,19 @@ func extractDomainName(urlStr string) (string, error) {
 	return parsedURL.Hostname(), nil
 }
 
-func (s *service) getDomainInfo(ctx *gin.Context, domainReq string) (*domain.DomainInfo, *domain.Location, error) {
+func (s *service) getDomainInfo(ctx context.Context, domainReq string) (*domaininfo.Record, error) {
 	log.Printf("Obteniendo información de dominio para: %s", domainReq)
 	domainName, err := extractDomainName(domainReq)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	whoisResult, err := whois.Whois(domainName)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	parsedResult, err := whoisparser.Parse(whoisResult)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	createdDate, err := time.Parse(time.RFC3339, parsedResult.Domain.CreatedDate)
-	if err != nil {
-		return nil, nil, err
-	}
-	expiryDate, err := time.Parse(time.RFC3339, parsedResult.Domain.ExpirationDate)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	registrarInfo := domain.RegistrarInfo{
-		Organization: parsedResult.Registrar.Name,
-		Address:      parsedResult.Registrar.Street,
-		City:         parsedResult.Registrar.City,
-		State:        parsedResult.Registrar.Province,
-		PostalCode:   parsedResult.Registrar.PostalCode,
-		Country:      parsedResult.Registrar.Country,
-		Phone:        parsedResult.Registrar.Phone,
-		Fax:          parsedResult.Registrar.Fax,
-		Email:        parsedResult.Registrar.Email,
-	}
-
-	technicalInfo := domain.TechnicalInfo{
-		Organization: parsedResult.Technical.Organization,
-		Address:      parsedResult.Technical.Street,
-		City:         parsedResult.Technical.City,
-		State:        parsedResult.Technical.Province,
-		PostalCode:   parsedResult.Technical.PostalCode,
-		Country:      parsedResult.Technical.Country,
-		Phone:        parsedResult.Technical.Phone,
-		Fax:          parsedResult.Technical.Fax,
-		Email:        parsedResult.Technical.Email,
-	}
-
-	location := &domain.Location{
-		City:    parsedResult.Administrative.City,
-		Country: parsedResult.Administrative.Country,
-		Address: parsedResult.Administrative.Street,
-		ZipCode: parsedResult.Administrative.PostalCode,
-	}
-
-	domainInfo := &domain.DomainInfo{
-		CreatedDate:   createdDate.Format("2006-01-02 15:04:05"),
-		ExpiryDate:    expiryDate.Format("2006-01-02 15:04:05"),
-		RegistrarName: parsedResult.Administrative.Name,
-		ContactEmail:  parsedResult.Administrative.Email,
-		RegistrarInfo: registrarInfo,
-		TechnicalInfo: technicalInfo,
-	}
-
-	fmt.Println("ParsedAdministrative result: ", parsedResult.Administrative)
-
-	return domainInfo, location, nil
-}
-
-// Funcion para obtener la ubicacion de una pagina. (params: etiquetas css.)
-func (s *service) ScrapeLocationInfo(urlReq, city, country, adrress, info string) (*domain.Location, error) {
-
-	c := colly.NewCollector(colly.Async(true))
-	c.WithTransport(&http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	})
-
-	location := &domain.Location{}
-
-	c.OnHTML(city, func(e *colly.HTMLElement) {
-		location.City = strings.TrimSpace(e.Text)
-	})
-	c.OnHTML(country, func(e *colly.HTMLElement) {
-		location.Country = strings.TrimSpace(e.Text)
-	})
-	c.OnHTML(adrress, func(e *colly.HTMLElement) {
-		location.Address = strings.TrimSpace(e.Text)
-	})
-	c.OnHTML(info, func(e *colly.HTMLElement) {
-		location.ZipCode = strings.TrimSpace(e.Text)
-	})
-
-	c.OnScraped(func(r *colly.Response) {
-		log.Printf("Finished scraping: %s", r.Request.URL)
-		if location.City == "" || location.Country == "" || location.Address == "" || location.ZipCode == "" {
-			log.Println("No se pudo obtener la información completa de ubicación")
-		}
-	})
-
-	err := c.Visit(urlReq)
 	if err != nil {
 		return nil, err
 	}
 
-	c.Wait()
-
-	return location, nil
+	return s.domainInfo.Lookup(ctx, domainName)
 }
 
-func (s *service) GetFranquiciaByID(ctx *gin.Context, id string) (domain.Franquicia, error) {
+func (s *service) GetFranquiciaByID(ctx context.Context, id string) (domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
 	result, err := s.repo.GetOne(ctx, id)
 	if err != nil {
 		return domain.Franquicia{}, err
@@ -331,7 +348,9 @@ func (s *service) GetFranquiciaByID(ctx *gin.Context, id string) (domain.Franqui
 	return result, nil
 }
 
-func (s *service) GetByLocation(ctx *gin.Context, city, country string) ([]domain.Franquicia, error) {
+func (s *service) GetByLocation(ctx context.Context, city, country string) ([]domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
 	result, err := s.repo.GetByLocation(ctx, city, country)
 	if err != nil {
 		return []domain.Franquicia{}, err
@@ -339,15 +358,77 @@ func (s *service) GetByLocation(ctx *gin.Context, city, country string) ([]domai
 	return result, nil
 }
 
-func (s *service) GetByDateRange(ctx *gin.Context, startDate, endDate string) ([]domain.Franquicia, error) {
-	result, err := s.repo.GetByDateRange(ctx, startDate, endDate)
+// GetByDateRange busca franquicias cuya fecha de alta y/o vencimiento del dominio cae en
+// [from, to]. Las fechas son ISO-8601; una fecha sin hora en "to" incluye el día completo.
+func (s *service) GetByDateRange(ctx context.Context, from, to string, field domain.DateRangeField) ([]domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
+	dateRange, err := parseDateRange(from, to, field)
+	if err != nil {
+		return []domain.Franquicia{}, err
+	}
+
+	result, err := s.repo.GetByDateRange(ctx, dateRange)
 	if err != nil {
 		return []domain.Franquicia{}, err
 	}
 	return result, nil
 }
 
-func (s *service) GetByFranchiseName(ctx *gin.Context, name string) ([]domain.Franquicia, error) {
+func parseDateRange(from, to string, field domain.DateRangeField) (domain.DateRange, error) {
+	dateRange := domain.DateRange{Field: field}
+	if dateRange.Field == "" {
+		dateRange.Field = domain.DateFieldCreated
+	}
+	if !dateRange.Field.IsValid() {
+		return dateRange, fmt.Errorf("%w: field must be created, expiry, both or either", ErrInvalidDateRange)
+	}
+	if from == "" && to == "" {
+		return dateRange, fmt.Errorf("%w: from or to is required", ErrInvalidDateRange)
+	}
+
+	if from != "" {
+		t, _, err := parseISODate(from)
+		if err != nil {
+			return dateRange, fmt.Errorf("%w: invalid from %q", ErrInvalidDateRange, from)
+		}
+		dateRange.From = &t
+	}
+	if to != "" {
+		t, dateOnly, err := parseISODate(to)
+		if err != nil {
+			return dateRange, fmt.Errorf("%w: invalid to %q", ErrInvalidDateRange, to)
+		}
+		// Las fechas se guardan con precisión de milisegundos: el último milisegundo del
+		// día es el extremo inclusivo de una fecha sin hora.
+		if dateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Millisecond)
+		}
+		dateRange.To = &t
+	}
+	if dateRange.From != nil && dateRange.To != nil && dateRange.From.After(*dateRange.To) {
+		return dateRange, fmt.Errorf("%w: from must not be after to", ErrInvalidDateRange)
+	}
+
+	return dateRange, nil
+}
+
+// parseISODate acepta fechas ISO-8601 con o sin hora; sin zona horaria se asume UTC.
+func parseISODate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid ISO-8601 date %q", value)
+}
+
+func (s *service) GetByFranchiseName(ctx context.Context, name string) ([]domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
 	result, err := s.repo.GetByFranchiseName(ctx, name)
 	if err != nil {
 		return []domain.Franquicia{}, err
@@ -355,7 +436,42 @@ func (s *service) GetByFranchiseName(ctx *gin.Context, name string) ([]domain.Fr
 	return result, nil
 }
 
-func (s *service) GetAllFranquicias(ctx *gin.Context) ([]domain.Franquicia, error) {
+// GetByTechnology busca franquicias por tecnología detectada. Con VersionBelow quedan solo
+// las que tienen una versión conocida menor, por ejemplo un motor de reservas desactualizado.
+func (s *service) GetByTechnology(ctx context.Context, filter domain.TechnologyFilter) ([]domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
+	if filter.Name == "" && filter.Category == "" {
+		return []domain.Franquicia{}, fmt.Errorf("%w: name or category is required", ErrInvalidTechnologyQuery)
+	}
+	if filter.VersionBelow != "" && filter.Name == "" {
+		return []domain.Franquicia{}, fmt.Errorf("%w: version_below requires name", ErrInvalidTechnologyQuery)
+	}
+
+	result, err := s.repo.GetByTechnology(ctx, filter.Name, filter.Category)
+	if err != nil {
+		return []domain.Franquicia{}, err
+	}
+	if filter.VersionBelow == "" {
+		return result, nil
+	}
+
+	outdated := []domain.Franquicia{}
+	for _, f := range result {
+		for _, t := range f.Technologies {
+			if strings.EqualFold(t.Name, filter.Name) && t.Version != "" &&
+				fingerprint.CompareVersions(t.Version, filter.VersionBelow) < 0 {
+				outdated = append(outdated, f)
+				break
+			}
+		}
+	}
+	return outdated, nil
+}
+
+func (s *service) GetAllFranquicias(ctx context.Context) ([]domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
 	fs, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return []domain.Franquicia{}, err
@@ -363,6 +479,163 @@ func (s *service) GetAllFranquicias(ctx *gin.Context) ([]domain.Franquicia, erro
 	return fs, nil
 }
 
-func (s *service) UpdateFranquicia(ctx *gin.Context, f domain.Franquicia) error {
+func (s *service) UpdateFranquicia(ctx context.Context, f domain.Franquicia) error {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Write)
+	defer cancel()
+	if f.Name != "" {
+		f.NameSource = website.NameSourceRequest
+	}
 	return s.repo.Update(ctx, f)
 }
+
+// GetRawDomainInfo devuelve las respuestas WHOIS/RDAP archivadas de la franquicia, descomprimidas.
+func (s *service) GetRawDomainInfo(ctx context.Context, id string) ([]domain.RawDomainResponse, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Read)
+	defer cancel()
+	f, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.archive == nil {
+		return nil, domaininfo.ErrRawResponseNotFound
+	}
+
+	responses, err := s.archive.GetByFranquicia(ctx, f.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range responses {
+		body, err := domaininfo.Decompress(responses[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i].Body = string(body)
+	}
+	return responses, nil
+}
+
+// ReparseDomainInfo vuelve a interpretar la última respuesta archivada y actualiza los datos
+// de dominio y la ubicación, sin consultar la red. Los datos SSL y las auditorías de
+// cabeceras y de correo se conservan.
+func (s *service) ReparseDomainInfo(ctx context.Context, id string) (domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Write)
+	defer cancel()
+	f, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return f, err
+	}
+	if s.archive == nil {
+		return f, domaininfo.ErrRawResponseNotFound
+	}
+
+	res, err := s.archive.GetLatest(ctx, f.ID)
+	if err != nil {
+		return f, err
+	}
+	rec, err := domaininfo.Reparse(res)
+	if err != nil {
+		return f, err
+	}
+
+	info := rec.Info
+	info.Protocol = f.DomainInfo.Protocol
+	info.IsProtocolSecure = f.DomainInfo.IsProtocolSecure
+	info.ServerHops = f.DomainInfo.ServerHops
+	info.SSLGrade = f.DomainInfo.SSLGrade
+	info.DNSRecords = f.DomainInfo.DNSRecords
+	info.SecurityHeaders = f.DomainInfo.SecurityHeaders
+	info.EmailSecurity = f.DomainInfo.EmailSecurity
+	f.DomainInfo = info
+	// Solo se reemplaza una ubicación que salió del registro del dominio; la tomada del
+	// sitio o de un perfil de scraping se conserva.
+	switch f.LocationSource {
+	case "", domaininfo.SourceRDAP, domaininfo.SourceWhois:
+		f.Location = rec.Location
+		f.LocationSource = rec.Info.Source
+	}
+
+	if err := s.repo.Update(ctx, f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// AuditSecurityHeaders vuelve a auditar las cabeceras de seguridad del sitio de la
+// franquicia y guarda el resultado junto a los datos del dominio.
+func (s *service) AuditSecurityHeaders(ctx context.Context, id string) (domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Refresh)
+	defer cancel()
+	f, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return f, err
+	}
+
+	audit, err := website.AuditSecurityHeaders(ctx, s.crawler, f.URL)
+	if err != nil {
+		return f, err
+	}
+	f.DomainInfo.SecurityHeaders = audit
+
+	if err := s.repo.Update(ctx, f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// DetectTechnologies vuelve a detectar las tecnologías del sitio con las reglas vigentes.
+func (s *service) DetectTechnologies(ctx context.Context, id string) (domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Refresh)
+	defer cancel()
+	f, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return f, err
+	}
+
+	technologies, err := s.detectTechnologies(ctx, f.URL)
+	if err != nil {
+		return f, err
+	}
+	f.Technologies = technologies
+
+	if err := s.repo.Update(ctx, f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+func (s *service) detectTechnologies(ctx context.Context, url string) ([]domain.Technology, error) {
+	rules, err := s.techRules.Rules()
+	if err != nil {
+		return nil, err
+	}
+	return fingerprint.Detect(ctx, s.crawler, rules, url)
+}
+
+// CheckEmailSecurity vuelve a revisar SPF, DMARC, DKIM, MTA-STS y BIMI del dominio.
+func (s *service) CheckEmailSecurity(ctx context.Context, id string) (domain.Franquicia, error) {
+	ctx, cancel := withTimeout(ctx, s.timeouts.Refresh)
+	defer cancel()
+	f, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return f, err
+	}
+
+	report, err := s.checkEmailSecurity(ctx, f.URL)
+	if err != nil {
+		return f, err
+	}
+	f.DomainInfo.EmailSecurity = report
+
+	if err := s.repo.Update(ctx, f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+func (s *service) checkEmailSecurity(ctx context.Context, url string) (*domain.EmailSecurity, error) {
+	domainName, err := extractDomainName(url)
+	if err != nil {
+		return nil, err
+	}
+	return s.mailsec.Check(ctx, domainName)
+}
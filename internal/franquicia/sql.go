@@ -0,0 +1,550 @@
+package franquicia
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de contacto de franchise_contacts.
+const (
+	contactHotel     = "hotel"
+	contactRegistrar = "registrar"
+	contactTechnical = "technical"
+)
+
+// queryer es lo que comparten *sql.DB y *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// sqlRepository guarda las franquicias en SQLite o PostgreSQL con el esquema de
+// MigrateSQL. Las consultas siguen la semántica del repositorio de Mongo.
+type sqlRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSQLRepository crea el repositorio sobre una base ya migrada con MigrateSQL.
+func NewSQLRepository(db *sql.DB, dialect Dialect) Repository {
+	return &sqlRepository{db: db, dialect: dialect}
+}
+
+const franchiseColumns = `f.id, f.name, f.name_source, f.url,
+	f.city, f.region, f.country, f.address, f.zip_code, f.latitude, f.longitude, f.location_source,
+	f.lodging, f.logo_url, f.logo, f.favicon, f.is_website_live,
+	f.created_date, f.expiry_date, f.created_date_raw, f.expiry_date_raw,
+	f.registrar_name, f.contact_email, f.protocol, f.is_protocol_secure, f.ssl_grade, f.domain_source,
+	f.security_headers, f.email_security, f.enrichment`
+
+func (r *sqlRepository) Create(ctx context.Context, franquicia *domain.Franquicia) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		values, err := franchiseValues(*franquicia)
+		if err != nil {
+			return err
+		}
+		columns := strings.ReplaceAll(franchiseColumns, "f.", "")
+		query := fmt.Sprintf(`INSERT INTO franchises (%s) VALUES (%s) ON CONFLICT (id) DO NOTHING`,
+			columns, placeholders(len(values)))
+		res, err := tx.ExecContext(ctx, rebind(r.dialect, query), values...)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("%w: %s", ErrDuplicateID, franquicia.ID.Hex())
+		}
+		return r.insertChildren(ctx, tx, *franquicia)
+	})
+}
+
+// Update pisa los mismos campos que el $set del repositorio de Mongo y reescribe las
+// tablas hijas. Como UpdateOne, no falla si la franquicia no existe.
+func (r *sqlRepository) Update(ctx context.Context, f domain.Franquicia) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if r.dialect == DialectPostgres {
+			// Bloquea la fila hasta el commit para que dos Update no se pisen.
+			if _, err := tx.ExecContext(ctx, `SELECT 1 FROM franchises WHERE id = $1 FOR UPDATE`, f.ID.Hex()); err != nil {
+				return err
+			}
+		}
+		found, err := r.find(ctx, tx, `f.id = ?`, f.ID.Hex())
+		if err != nil || len(found) == 0 {
+			return err
+		}
+		stored := found[0]
+		dst := reflect.ValueOf(&stored).Elem()
+		src := reflect.ValueOf(f)
+		for _, i := range updatedFields(f) {
+			dst.Field(i).Set(src.Field(i))
+		}
+
+		values, err := franchiseValues(stored)
+		if err != nil {
+			return err
+		}
+		var set []string
+		for _, column := range strings.Split(strings.ReplaceAll(franchiseColumns, "f.", ""), ",")[1:] {
+			set = append(set, strings.TrimSpace(column)+" = ?")
+		}
+		query := fmt.Sprintf(`UPDATE franchises SET %s WHERE id = ?`, strings.Join(set, ", "))
+		if _, err := tx.ExecContext(ctx, rebind(r.dialect, query), append(values[1:], values[0])...); err != nil {
+			return err
+		}
+
+		for _, table := range []string{"franchise_technology_categories", "franchise_technologies", "franchise_dns_records", "franchise_server_hops", "franchise_contacts"} {
+			query := fmt.Sprintf(`DELETE FROM %s WHERE franchise_id = ?`, table)
+			if _, err := tx.ExecContext(ctx, rebind(r.dialect, query), f.ID.Hex()); err != nil {
+				return err
+			}
+		}
+		return r.insertChildren(ctx, tx, stored)
+	})
+}
+
+// GetOne devuelve ErrNotFound si no existe, igual que el repositorio de Mongo.
+func (r *sqlRepository) GetOne(ctx context.Context, id string) (domain.Franquicia, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Franquicia{}, err
+	}
+	found, err := r.find(ctx, r.db, `f.id = ?`, objID.Hex())
+	if err != nil {
+		return domain.Franquicia{}, err
+	}
+	if len(found) == 0 {
+		return domain.Franquicia{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return found[0], nil
+}
+
+func (r *sqlRepository) GetAll(ctx context.Context) ([]domain.Franquicia, error) {
+	return r.find(ctx, r.db, "")
+}
+
+// GetByFranchiseName interpreta name como expresión regular sin distinguir mayúsculas, como
+// el $regex de Mongo. PostgreSQL la evalúa con ~*; SQLite no trae REGEXP, así que ahí se
+// evalúa en Go (RE2) sobre los nombres y después se cargan solo las que coinciden.
+func (r *sqlRepository) GetByFranchiseName(ctx context.Context, name string) ([]domain.Franquicia, error) {
+	if r.dialect == DialectPostgres {
+		return r.find(ctx, r.db, `f.name ~* ?`, name)
+	}
+	re, err := regexp.Compile("(?i)" + name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name FROM franchises ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	var ids []any
+	for rows.Next() {
+		var id, franchiseName string
+		if err := rows.Scan(&id, &franchiseName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if re.MatchString(franchiseName) {
+			ids = append(ids, id)
+		}
+	}
+	// Se cierra antes de la segunda consulta: SQLite usa una sola conexión.
+	rows.Close()
+	if err := rows.Err(); err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return r.find(ctx, r.db, `f.id IN (`+placeholders(len(ids))+`)`, ids...)
+}
+
+// GetByTechnology busca las franquicias con una tecnología detectada por nombre y/o
+// categoría, sin distinguir mayúsculas; las dos condiciones valen para la misma tecnología.
+func (r *sqlRepository) GetByTechnology(ctx context.Context, name, category string) ([]domain.Franquicia, error) {
+	where := `EXISTS (SELECT 1 FROM franchise_technologies t WHERE t.franchise_id = f.id`
+	var args []any
+	if name != "" {
+		where += ` AND LOWER(t.name) = LOWER(?)`
+		args = append(args, name)
+	}
+	if category != "" {
+		where += ` AND EXISTS (SELECT 1 FROM franchise_technology_categories c
+			WHERE c.franchise_id = t.franchise_id AND c.technology = t.position AND LOWER(c.category) = LOWER(?))`
+		args = append(args, category)
+	}
+	return r.find(ctx, r.db, where+`)`, args...)
+}
+
+func (r *sqlRepository) GetByLocation(ctx context.Context, city, country string) ([]domain.Franquicia, error) {
+	return r.find(ctx, r.db, `f.city = ? AND f.country = ?`, city, country)
+}
+
+// GetByDateRange sigue a dateRangeFilter: una fecha ausente (NULL) no entra en ningún rango.
+func (r *sqlRepository) GetByDateRange(ctx context.Context, dateRange domain.DateRange) ([]domain.Franquicia, error) {
+	var args []any
+	bounds := func(column string) string {
+		conditions := []string{column + " IS NOT NULL"}
+		if dateRange.From != nil {
+			conditions = append(conditions, column+" >= ?")
+			args = append(args, dateRange.From.UnixMilli())
+		}
+		if dateRange.To != nil {
+			conditions = append(conditions, column+" <= ?")
+			args = append(args, dateRange.To.UnixMilli())
+		}
+		return "(" + strings.Join(conditions, " AND ") + ")"
+	}
+
+	var where string
+	switch dateRange.Field {
+	case domain.DateFieldExpiry:
+		where = bounds("f.expiry_date")
+	case domain.DateFieldBoth:
+		where = bounds("f.created_date") + " AND " + bounds("f.expiry_date")
+	case domain.DateFieldEither:
+		where = bounds("f.created_date") + " OR " + bounds("f.expiry_date")
+	default:
+		where = bounds("f.created_date")
+	}
+	return r.find(ctx, r.db, where, args...)
+}
+
+// find carga las franquicias que cumplen where, en orden de creación, con sus tablas hijas.
+// Cada tabla hija se lee con una sola consulta filtrada por el mismo where.
+func (r *sqlRepository) find(ctx context.Context, q queryer, where string, args ...any) ([]domain.Franquicia, error) {
+	if where != "" {
+		where = " WHERE (" + where + ")"
+	}
+	rows, err := q.QueryContext(ctx, rebind(r.dialect, `SELECT `+franchiseColumns+` FROM franchises f`+where+` ORDER BY f.seq`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var franquicias []domain.Franquicia
+	for rows.Next() {
+		f, err := scanFranchise(rows)
+		if err != nil {
+			return nil, err
+		}
+		franquicias = append(franquicias, f)
+	}
+	if err := rows.Err(); err != nil || len(franquicias) == 0 {
+		return nil, err
+	}
+	byID := map[string]*domain.Franquicia{}
+	for i := range franquicias {
+		byID[franquicias[i].ID.Hex()] = &franquicias[i]
+	}
+
+	for _, child := range []struct {
+		query string
+		scan  func(rows *sql.Rows, byID map[string]*domain.Franquicia) error
+	}{
+		{`SELECT x.franchise_id, x.kind, x.organization, x.address, x.city, x.state, x.postal_code, x.country, x.phone, x.fax, x.email
+			FROM franchise_contacts x %s`, scanContact},
+		{`SELECT x.franchise_id, x.server_name FROM franchise_server_hops x %s ORDER BY x.franchise_id, x.position`, scanServerHop},
+		{`SELECT x.franchise_id, x.type, x.value, x.ttl, x.priority FROM franchise_dns_records x %s ORDER BY x.franchise_id, x.position`, scanDNSRecord},
+		{`SELECT x.franchise_id, x.name, x.version, x.confidence, x.sources FROM franchise_technologies x %s ORDER BY x.franchise_id, x.position`, scanTechnology},
+		// Las categorías van después de las tecnologías: technology es su posición en la lista.
+		{`SELECT x.franchise_id, x.technology, x.category FROM franchise_technology_categories x %s
+			ORDER BY x.franchise_id, x.technology, x.position`, scanTechnologyCategory},
+	} {
+		query := fmt.Sprintf(child.query, `JOIN franchises f ON f.id = x.franchise_id`+where)
+		if err := r.scanChildren(ctx, q, query, args, byID, child.scan); err != nil {
+			return nil, err
+		}
+	}
+	return franquicias, nil
+}
+
+func (r *sqlRepository) scanChildren(ctx context.Context, q queryer, query string, args []any, byID map[string]*domain.Franquicia, scan func(*sql.Rows, map[string]*domain.Franquicia) error) error {
+	rows, err := q.QueryContext(ctx, rebind(r.dialect, query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows, byID); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *sqlRepository) insertChildren(ctx context.Context, tx *sql.Tx, f domain.Franquicia) error {
+	id := f.ID.Hex()
+	insert := func(query string, args ...any) error {
+		_, err := tx.ExecContext(ctx, rebind(r.dialect, query), args...)
+		return err
+	}
+
+	contacts := map[string]domain.RegistrarInfo{
+		contactRegistrar: f.DomainInfo.RegistrarInfo,
+		contactTechnical: domain.RegistrarInfo(f.DomainInfo.TechnicalInfo),
+	}
+	if f.Contact != nil {
+		contacts[contactHotel] = domain.RegistrarInfo{Phone: f.Contact.Telephone, Email: f.Contact.Email, Fax: f.Contact.Fax}
+	}
+	for _, kind := range []string{contactHotel, contactRegistrar, contactTechnical} {
+		c, ok := contacts[kind]
+		if !ok || (kind != contactHotel && c == (domain.RegistrarInfo{})) {
+			continue
+		}
+		if err := insert(`INSERT INTO franchise_contacts (franchise_id, kind, organization, address, city, state, postal_code, country, phone, fax, email)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, kind, c.Organization, c.Address, c.City, c.State, c.PostalCode, c.Country, c.Phone, c.Fax, c.Email); err != nil {
+			return err
+		}
+	}
+
+	for i, hop := range f.DomainInfo.ServerHops {
+		if err := insert(`INSERT INTO franchise_server_hops (franchise_id, position, server_name) VALUES (?, ?, ?)`, id, i, hop); err != nil {
+			return err
+		}
+	}
+	for i, record := range f.DomainInfo.DNSRecords {
+		if err := insert(`INSERT INTO franchise_dns_records (franchise_id, position, type, value, ttl, priority) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, i, record.Type, record.Value, record.TTL, record.Priority); err != nil {
+			return err
+		}
+	}
+	for i, t := range f.Technologies {
+		sources, err := jsonValue(t.Sources)
+		if err != nil {
+			return err
+		}
+		if err := insert(`INSERT INTO franchise_technologies (franchise_id, position, name, version, confidence, sources) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, i, t.Name, t.Version, t.Confidence, sources); err != nil {
+			return err
+		}
+		for j, category := range t.Categories {
+			if err := insert(`INSERT INTO franchise_technology_categories (franchise_id, technology, position, category) VALUES (?, ?, ?, ?)`,
+				id, i, j, category); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *sqlRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// franchiseValues devuelve los valores de franchiseColumns, con el ID primero.
+func franchiseValues(f domain.Franquicia) ([]any, error) {
+	var values []any
+	for _, v := range []any{f.Lodging, f.Logo, f.Favicon, f.DomainInfo.CreatedDateRaw, f.DomainInfo.ExpiryDateRaw,
+		f.DomainInfo.SecurityHeaders, f.DomainInfo.EmailSecurity, f.Enrichment} {
+		encoded, err := jsonValue(v)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, encoded)
+	}
+	lodging, logo, favicon, createdRaw, expiryRaw, securityHeaders, emailSecurity, enrichment :=
+		values[0], values[1], values[2], values[3], values[4], values[5], values[6], values[7]
+
+	info := f.DomainInfo
+	return []any{
+		f.ID.Hex(), f.Name, f.NameSource, f.URL,
+		f.Location.City, f.Location.Region, f.Location.Country, f.Location.Address, f.Location.ZipCode,
+		f.Location.Latitude, f.Location.Longitude, f.LocationSource,
+		lodging, f.LogoURL, logo, favicon, f.IsWebsiteLive,
+		unixMilli(info.CreatedDate), unixMilli(info.ExpiryDate), createdRaw, expiryRaw,
+		info.RegistrarName, info.ContactEmail, info.Protocol, info.IsProtocolSecure, info.SSLGrade, info.Source,
+		securityHeaders, emailSecurity, enrichment,
+	}, nil
+}
+
+func scanFranchise(rows *sql.Rows) (domain.Franquicia, error) {
+	var f domain.Franquicia
+	var id string
+	var created, expiry sql.NullInt64
+	var lodging, logo, favicon, createdRaw, expiryRaw, securityHeaders, emailSecurity, enrichment sql.NullString
+	info := &f.DomainInfo
+	err := rows.Scan(&id, &f.Name, &f.NameSource, &f.URL,
+		&f.Location.City, &f.Location.Region, &f.Location.Country, &f.Location.Address, &f.Location.ZipCode,
+		&f.Location.Latitude, &f.Location.Longitude, &f.LocationSource,
+		&lodging, &f.LogoURL, &logo, &favicon, &f.IsWebsiteLive,
+		&created, &expiry, &createdRaw, &expiryRaw,
+		&info.RegistrarName, &info.ContactEmail, &info.Protocol, &info.IsProtocolSecure, &info.SSLGrade, &info.Source,
+		&securityHeaders, &emailSecurity, &enrichment)
+	if err != nil {
+		return f, err
+	}
+	if err := setID(&f, id); err != nil {
+		return f, err
+	}
+	info.CreatedDate = fromUnixMilli(created)
+	info.ExpiryDate = fromUnixMilli(expiry)
+
+	for _, field := range []struct {
+		value sql.NullString
+		dst   any
+	}{
+		{lodging, &f.Lodging}, {logo, &f.Logo}, {favicon, &f.Favicon},
+		{createdRaw, &info.CreatedDateRaw}, {expiryRaw, &info.ExpiryDateRaw},
+		{securityHeaders, &info.SecurityHeaders}, {emailSecurity, &info.EmailSecurity}, {enrichment, &f.Enrichment},
+	} {
+		if !field.value.Valid {
+			continue
+		}
+		if err := json.Unmarshal([]byte(field.value.String), field.dst); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+func scanContact(rows *sql.Rows, byID map[string]*domain.Franquicia) error {
+	var id, kind string
+	var c domain.RegistrarInfo
+	if err := rows.Scan(&id, &kind, &c.Organization, &c.Address, &c.City, &c.State, &c.PostalCode, &c.Country, &c.Phone, &c.Fax, &c.Email); err != nil {
+		return err
+	}
+	f, err := childOf(byID, id)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case contactHotel:
+		f.Contact = &domain.Contact{Telephone: c.Phone, Email: c.Email, Fax: c.Fax}
+	case contactRegistrar:
+		f.DomainInfo.RegistrarInfo = c
+	case contactTechnical:
+		f.DomainInfo.TechnicalInfo = domain.TechnicalInfo(c)
+	}
+	return nil
+}
+
+func scanServerHop(rows *sql.Rows, byID map[string]*domain.Franquicia) error {
+	var id, hop string
+	if err := rows.Scan(&id, &hop); err != nil {
+		return err
+	}
+	f, err := childOf(byID, id)
+	if err != nil {
+		return err
+	}
+	f.DomainInfo.ServerHops = append(f.DomainInfo.ServerHops, hop)
+	return nil
+}
+
+func scanDNSRecord(rows *sql.Rows, byID map[string]*domain.Franquicia) error {
+	var id string
+	var record domain.DNSRecord
+	if err := rows.Scan(&id, &record.Type, &record.Value, &record.TTL, &record.Priority); err != nil {
+		return err
+	}
+	f, err := childOf(byID, id)
+	if err != nil {
+		return err
+	}
+	f.DomainInfo.DNSRecords = append(f.DomainInfo.DNSRecords, record)
+	return nil
+}
+
+func scanTechnology(rows *sql.Rows, byID map[string]*domain.Franquicia) error {
+	var id string
+	var t domain.Technology
+	var sources sql.NullString
+	if err := rows.Scan(&id, &t.Name, &t.Version, &t.Confidence, &sources); err != nil {
+		return err
+	}
+	if sources.Valid {
+		if err := json.Unmarshal([]byte(sources.String), &t.Sources); err != nil {
+			return err
+		}
+	}
+	f, err := childOf(byID, id)
+	if err != nil {
+		return err
+	}
+	f.Technologies = append(f.Technologies, t)
+	return nil
+}
+
+func scanTechnologyCategory(rows *sql.Rows, byID map[string]*domain.Franquicia) error {
+	var id, category string
+	var technology int
+	if err := rows.Scan(&id, &technology, &category); err != nil {
+		return err
+	}
+	f, err := childOf(byID, id)
+	if err != nil {
+		return err
+	}
+	if technology < 0 || technology >= len(f.Technologies) {
+		return fmt.Errorf("category of unknown technology %d of franchise %s", technology, id)
+	}
+	f.Technologies[technology].Categories = append(f.Technologies[technology].Categories, category)
+	return nil
+}
+
+func childOf(byID map[string]*domain.Franquicia, id string) (*domain.Franquicia, error) {
+	f, ok := byID[id]
+	if !ok {
+		return nil, fmt.Errorf("child row of unknown franchise %s", id)
+	}
+	return f, nil
+}
+
+func setID(f *domain.Franquicia, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	f.ID = objID
+	return nil
+}
+
+// jsonValue codifica v para una columna JSON; los valores vacíos quedan en NULL, como los
+// campos omitempty en Mongo.
+func jsonValue(v any) (any, error) {
+	if val := reflect.ValueOf(v); !val.IsValid() || val.IsZero() || (val.Kind() == reflect.Slice && val.Len() == 0) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func unixMilli(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms sql.NullInt64) *time.Time {
+	if !ms.Valid {
+		return nil
+	}
+	t := time.UnixMilli(ms.Int64).UTC()
+	return &t
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,186 @@
+package franquicia
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Dialect es el motor SQL del repositorio.
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// sqlTypes adapta los tipos del esquema a cada motor.
+var sqlTypes = map[Dialect]*strings.Replacer{
+	DialectSQLite:   strings.NewReplacer("{{serial}}", "INTEGER PRIMARY KEY AUTOINCREMENT", "{{float}}", "REAL", "{{json}}", "TEXT"),
+	DialectPostgres: strings.NewReplacer("{{serial}}", "BIGSERIAL PRIMARY KEY", "{{float}}", "DOUBLE PRECISION", "{{json}}", "JSONB"),
+}
+
+// sqlMigrations es el esquema por versiones. Para cambiarlo se agrega una versión nueva;
+// las aplicadas no se tocan.
+//
+// Las fechas del dominio se guardan en milisegundos desde epoch, la misma precisión que en
+// Mongo, para comparar rangos igual en los dos motores. Contactos, saltos de servidor,
+// registros DNS y tecnologías van en tablas propias; lo que no se filtra (alojamiento,
+// imágenes, auditorías, resultado del enriquecimiento) va como JSON.
+var sqlMigrations = []struct {
+	version    int
+	statements []string
+}{
+	{1, []string{
+		`CREATE TABLE franchises (
+			seq {{serial}},
+			id TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			name_source TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			city TEXT NOT NULL DEFAULT '',
+			region TEXT NOT NULL DEFAULT '',
+			country TEXT NOT NULL DEFAULT '',
+			address TEXT NOT NULL DEFAULT '',
+			zip_code TEXT NOT NULL DEFAULT '',
+			latitude {{float}} NOT NULL DEFAULT 0,
+			longitude {{float}} NOT NULL DEFAULT 0,
+			location_source TEXT NOT NULL DEFAULT '',
+			lodging {{json}},
+			logo_url TEXT NOT NULL DEFAULT '',
+			logo {{json}},
+			favicon {{json}},
+			is_website_live BOOLEAN NOT NULL DEFAULT FALSE,
+			created_date BIGINT,
+			expiry_date BIGINT,
+			created_date_raw {{json}},
+			expiry_date_raw {{json}},
+			registrar_name TEXT NOT NULL DEFAULT '',
+			contact_email TEXT NOT NULL DEFAULT '',
+			protocol TEXT NOT NULL DEFAULT '',
+			is_protocol_secure BOOLEAN NOT NULL DEFAULT FALSE,
+			ssl_grade TEXT NOT NULL DEFAULT '',
+			domain_source TEXT NOT NULL DEFAULT '',
+			security_headers {{json}},
+			email_security {{json}},
+			enrichment {{json}}
+		)`,
+		`CREATE INDEX franchises_location ON franchises (city, country)`,
+		`CREATE INDEX franchises_created_date ON franchises (created_date)`,
+		`CREATE INDEX franchises_expiry_date ON franchises (expiry_date)`,
+		// kind es hotel (el contacto publicado en el sitio), registrar o technical.
+		`CREATE TABLE franchise_contacts (
+			franchise_id TEXT NOT NULL REFERENCES franchises (id) ON DELETE CASCADE,
+			kind TEXT NOT NULL,
+			organization TEXT NOT NULL DEFAULT '',
+			address TEXT NOT NULL DEFAULT '',
+			city TEXT NOT NULL DEFAULT '',
+			state TEXT NOT NULL DEFAULT '',
+			postal_code TEXT NOT NULL DEFAULT '',
+			country TEXT NOT NULL DEFAULT '',
+			phone TEXT NOT NULL DEFAULT '',
+			fax TEXT NOT NULL DEFAULT '',
+			email TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (franchise_id, kind)
+		)`,
+		`CREATE TABLE franchise_server_hops (
+			franchise_id TEXT NOT NULL REFERENCES franchises (id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			server_name TEXT NOT NULL,
+			PRIMARY KEY (franchise_id, position)
+		)`,
+		`CREATE TABLE franchise_dns_records (
+			franchise_id TEXT NOT NULL REFERENCES franchises (id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			type TEXT NOT NULL,
+			value TEXT NOT NULL,
+			ttl INTEGER NOT NULL DEFAULT 0,
+			priority INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (franchise_id, position)
+		)`,
+		`CREATE TABLE franchise_technologies (
+			franchise_id TEXT NOT NULL REFERENCES franchises (id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			version TEXT NOT NULL DEFAULT '',
+			confidence INTEGER NOT NULL DEFAULT 0,
+			sources {{json}},
+			PRIMARY KEY (franchise_id, position)
+		)`,
+		`CREATE INDEX franchise_technologies_name ON franchise_technologies (LOWER(name))`,
+		`CREATE TABLE franchise_technology_categories (
+			franchise_id TEXT NOT NULL,
+			technology INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			category TEXT NOT NULL,
+			PRIMARY KEY (franchise_id, technology, position),
+			FOREIGN KEY (franchise_id, technology) REFERENCES franchise_technologies (franchise_id, position) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX franchise_technology_categories_category ON franchise_technology_categories (LOWER(category))`,
+	}},
+}
+
+// MigrateSQL aplica las versiones del esquema que falten y devuelve cuántas aplicó. En
+// PostgreSQL toma un lock para que dos instancias que arrancan a la vez no migren juntas.
+func MigrateSQL(ctx context.Context, db *sql.DB, dialect Dialect) (int, error) {
+	types, ok := sqlTypes[dialect]
+	if !ok {
+		return 0, fmt.Errorf("unknown SQL dialect %q", dialect)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	// El lock va antes de crear schema_migrations: dos CREATE TABLE IF NOT EXISTS
+	// simultáneos en PostgreSQL pueden fallar por la clave única del catálogo.
+	if dialect == DialectPostgres {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('schema_migrations'))`); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return 0, err
+	}
+
+	var current int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, m := range sqlMigrations {
+		if m.version <= current {
+			continue
+		}
+		for _, statement := range m.statements {
+			if _, err := tx.ExecContext(ctx, types.Replace(statement)); err != nil {
+				return 0, fmt.Errorf("migration %d: %w", m.version, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, rebind(dialect, `INSERT INTO schema_migrations (version) VALUES (?)`), m.version); err != nil {
+			return 0, err
+		}
+		applied++
+	}
+	return applied, tx.Commit()
+}
+
+// rebind pasa los placeholders "?" a la sintaxis del motor.
+func rebind(dialect Dialect, query string) string {
+	if dialect != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package housekeeping
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// broker reparte los cambios de estado de habitaciones a los tableros suscritos de cada hotel.
+type broker struct {
+	mu   sync.Mutex
+	subs map[string]map[chan domain.RoomStatusEvent]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subs: map[string]map[chan domain.RoomStatusEvent]struct{}{}}
+}
+
+func boardKey(franquiciaID primitive.ObjectID, hotelID string) string {
+	return franquiciaID.Hex() + ":" + hotelID
+}
+
+func (b *broker) subscribe(key string) (<-chan domain.RoomStatusEvent, func()) {
+	ch := make(chan domain.RoomStatusEvent, 16)
+
+	b.mu.Lock()
+	if b.subs[key] == nil {
+		b.subs[key] = map[chan domain.RoomStatusEvent]struct{}{}
+	}
+	b.subs[key][ch] = struct{}{}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[key][ch]; ok {
+			delete(b.subs[key], ch)
+			close(ch)
+		}
+		if len(b.subs[key]) == 0 {
+			delete(b.subs, key)
+		}
+	}
+	return ch, cancel
+}
+
+// publish nunca bloquea: si un suscriptor está atrasado se descarta el evento para él.
+func (b *broker) publish(key string, evt domain.RoomStatusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[key] {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
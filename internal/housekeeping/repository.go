@@ -0,0 +1,163 @@
+package housekeeping
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	ErrTaskNotFound = errors.New("housekeeping task not found")
+)
+
+type Repository interface {
+	GetRoom(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number string) (domain.Room, error)
+	GetRooms(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.Room, error)
+	SaveRoom(ctx context.Context, room *domain.Room) error
+	SetRoomType(ctx context.Context, room *domain.Room) error
+
+	CreateTask(ctx context.Context, task *domain.HousekeepingTask) error
+	UpdateTask(ctx context.Context, task domain.HousekeepingTask) error
+	GetTask(ctx context.Context, id primitive.ObjectID) (domain.HousekeepingTask, error)
+	GetTasks(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.TaskStatus, assignedTo string) ([]domain.HousekeepingTask, error)
+	FindTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string, taskType domain.TaskType, date string) (domain.HousekeepingTask, error)
+}
+
+type repository struct {
+	rooms *mongo.Collection
+	tasks *mongo.Collection
+}
+
+func NewRepository(rooms, tasks *mongo.Collection) Repository {
+	return &repository{
+		rooms: rooms,
+		tasks: tasks,
+	}
+}
+
+func (r *repository) GetRoom(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number string) (domain.Room, error) {
+	var room domain.Room
+	filter := bson.M{"franquicia_id": franquiciaID, "hotel_id": hotelID, "number": number}
+	err := r.rooms.FindOne(ctx, filter).Decode(&room)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return room, ErrRoomNotFound
+	}
+	return room, err
+}
+
+func (r *repository) GetRooms(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.Room, error) {
+	var rooms []domain.Room
+	filter := bson.M{"franquicia_id": franquiciaID, "hotel_id": hotelID}
+	cursor, err := r.rooms.Find(ctx, filter, options.Find().SetSort(bson.M{"number": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var room domain.Room
+		if err := cursor.Decode(&room); err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+
+	return rooms, nil
+}
+
+// SaveRoom actualiza el estado de una habitación registrada con SetRoomType; si no existe
+// devuelve ErrRoomNotFound.
+func (r *repository) SaveRoom(ctx context.Context, room *domain.Room) error {
+	filter := bson.M{"franquicia_id": room.FranquiciaID, "hotel_id": room.HotelID, "number": room.Number}
+	update := bson.M{"$set": bson.M{"status": room.Status, "updated_at": room.UpdatedAt}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.rooms.FindOneAndUpdate(ctx, filter, update, opts).Decode(room)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrRoomNotFound
+	}
+	return err
+}
+
+// SetRoomType actualiza el tipo de la habitación; si no existía se registra como CLEAN.
+func (r *repository) SetRoomType(ctx context.Context, room *domain.Room) error {
+	filter := bson.M{"franquicia_id": room.FranquiciaID, "hotel_id": room.HotelID, "number": room.Number}
+	update := bson.M{
+		"$set":         bson.M{"type": room.Type},
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "status": domain.RoomClean, "updated_at": room.UpdatedAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return r.rooms.FindOneAndUpdate(ctx, filter, update, opts).Decode(room)
+}
+
+func (r *repository) CreateTask(ctx context.Context, task *domain.HousekeepingTask) error {
+	_, err := r.tasks.InsertOne(ctx, task)
+	return err
+}
+
+func (r *repository) UpdateTask(ctx context.Context, task domain.HousekeepingTask) error {
+	res, err := r.tasks.ReplaceOne(ctx, bson.M{"_id": task.ID}, task)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetTask(ctx context.Context, id primitive.ObjectID) (domain.HousekeepingTask, error) {
+	var task domain.HousekeepingTask
+	err := r.tasks.FindOne(ctx, bson.M{"_id": id}).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return task, ErrTaskNotFound
+	}
+	return task, err
+}
+
+func (r *repository) GetTasks(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.TaskStatus, assignedTo string) ([]domain.HousekeepingTask, error) {
+	var tasks []domain.HousekeepingTask
+	filter := bson.M{"franquicia_id": franquiciaID, "hotel_id": hotelID}
+	if status != "" {
+		filter["status"] = status
+	}
+	if assignedTo != "" {
+		filter["assigned_to"] = assignedTo
+	}
+	cursor, err := r.tasks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "room_number", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var task domain.HousekeepingTask
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func (r *repository) FindTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber string, taskType domain.TaskType, date string) (domain.HousekeepingTask, error) {
+	var task domain.HousekeepingTask
+	filter := bson.M{
+		"franquicia_id": franquiciaID,
+		"hotel_id":      hotelID,
+		"room_number":   roomNumber,
+		"type":          taskType,
+		"date":          date,
+	}
+	err := r.tasks.FindOne(ctx, filter).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return task, ErrTaskNotFound
+	}
+	return task, err
+}
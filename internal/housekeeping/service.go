@@ -0,0 +1,306 @@
+package housekeeping
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrRoomNotReady      = errors.New("room is not ready for check-in")
+	ErrRoomOccupied      = errors.New("room is occupied on those dates")
+	ErrInvalidTask       = errors.New("invalid housekeeping task")
+	ErrInvalidRoom       = errors.New("invalid room")
+)
+
+type Service interface {
+	GetBoard(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (*domain.HousekeepingBoard, error)
+	Subscribe(franquiciaID primitive.ObjectID, hotelID string) (<-chan domain.RoomStatusEvent, func())
+	SetRoomStatus(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number string, status domain.RoomStatus) (domain.Room, error)
+	SetRoomType(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number, roomType string) (domain.Room, error)
+	EnsureAssignable(ctx context.Context, f domain.Folio) error
+
+	OnCheckout(ctx context.Context, f domain.Folio, date string) error
+	OnStayover(ctx context.Context, f domain.Folio, date string) error
+
+	GetTasks(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.TaskStatus, assignedTo string) ([]domain.HousekeepingTask, error)
+	AssignTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID, staff string) (domain.HousekeepingTask, error)
+	StartTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (domain.HousekeepingTask, error)
+	CompleteTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (domain.HousekeepingTask, error)
+	InspectTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID, inspector string) (domain.HousekeepingTask, error)
+}
+
+// Stays busca las estadías abiertas o en checkout de una habitación que se superponen con
+// un rango de fechas; folio.Repository lo implementa.
+type Stays interface {
+	GetOverlapping(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, roomNumber, checkIn, checkOut string) ([]domain.Folio, error)
+}
+
+type service struct {
+	repo   Repository
+	stays  Stays
+	broker *broker
+}
+
+// NewService crea un nuevo servicio de housekeeping.
+func NewService(r Repository, stays Stays) Service {
+	return &service{
+		repo:   r,
+		stays:  stays,
+		broker: newBroker(),
+	}
+}
+
+func (s *service) GetBoard(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (*domain.HousekeepingBoard, error) {
+	rooms, err := s.repo.GetRooms(ctx, franquiciaID, hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &domain.HousekeepingBoard{
+		HotelID: hotelID,
+		Rooms:   rooms,
+		Tasks:   []domain.HousekeepingTask{},
+	}
+	for _, status := range []domain.TaskStatus{domain.TaskPending, domain.TaskInProgress, domain.TaskDone} {
+		tasks, err := s.repo.GetTasks(ctx, franquiciaID, hotelID, status, "")
+		if err != nil {
+			return nil, err
+		}
+		board.Tasks = append(board.Tasks, tasks...)
+	}
+	return board, nil
+}
+
+func (s *service) Subscribe(franquiciaID primitive.ObjectID, hotelID string) (<-chan domain.RoomStatusEvent, func()) {
+	return s.broker.subscribe(boardKey(franquiciaID, hotelID))
+}
+
+// SetRoomStatus cambia el estado de una habitación a mano. La habitación tiene que estar
+// registrada con SetRoomType; si no, devuelve ErrRoomNotFound.
+func (s *service) SetRoomStatus(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number string, status domain.RoomStatus) (domain.Room, error) {
+	if !status.IsValid() {
+		return domain.Room{}, fmt.Errorf("%w: unknown room status %q", ErrInvalidTransition, status)
+	}
+
+	room, err := s.repo.GetRoom(ctx, franquiciaID, hotelID, number)
+	if err != nil {
+		return domain.Room{}, err
+	}
+	if !room.Status.CanTransitionTo(status) {
+		return domain.Room{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, room.Status, status)
+	}
+
+	return s.saveRoom(ctx, franquiciaID, hotelID, number, status, nil)
+}
+
+// SetRoomType registra la habitación (como CLEAN) si no existía y le asigna el tipo.
+func (s *service) SetRoomType(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number, roomType string) (domain.Room, error) {
+	roomType = strings.TrimSpace(roomType)
+	if roomType == "" {
+		return domain.Room{}, fmt.Errorf("%w: room type is required", ErrInvalidRoom)
+	}
+
+	room := domain.Room{
+		FranquiciaID: franquiciaID,
+		HotelID:      hotelID,
+		Number:       number,
+		Type:         roomType,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if err := s.repo.SetRoomType(ctx, &room); err != nil {
+		return domain.Room{}, err
+	}
+	return room, nil
+}
+
+// EnsureAssignable comprueba que la habitación del folio f se pueda asignar: devuelve
+// ErrRoomNotFound si no está registrada, ErrRoomNotReady si está sucia o en limpieza y
+// ErrRoomOccupied si otro folio abierto o en checkout la ocupa en alguna de esas noches.
+func (s *service) EnsureAssignable(ctx context.Context, f domain.Folio) error {
+	room, err := s.repo.GetRoom(ctx, f.FranquiciaID, f.HotelID, f.RoomNumber)
+	if err != nil {
+		return err
+	}
+	if !room.Status.IsAssignable() {
+		return fmt.Errorf("%w: room %s is %s", ErrRoomNotReady, f.RoomNumber, room.Status)
+	}
+
+	stays, err := s.stays.GetOverlapping(ctx, f.FranquiciaID, f.HotelID, f.RoomNumber, f.CheckIn, f.CheckOut)
+	if err != nil {
+		return err
+	}
+	if len(stays) > 0 {
+		return fmt.Errorf("%w: room %s has folio %s from %s to %s", ErrRoomOccupied, f.RoomNumber, stays[0].ID.Hex(), stays[0].CheckIn, stays[0].CheckOut)
+	}
+	return nil
+}
+
+func (s *service) OnCheckout(ctx context.Context, f domain.Folio, date string) error {
+	return s.generateTask(ctx, f, domain.TaskCheckout, date)
+}
+
+func (s *service) OnStayover(ctx context.Context, f domain.Folio, date string) error {
+	return s.generateTask(ctx, f, domain.TaskStayover, date)
+}
+
+func (s *service) GetTasks(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, status domain.TaskStatus, assignedTo string) ([]domain.HousekeepingTask, error) {
+	result, err := s.repo.GetTasks(ctx, franquiciaID, hotelID, status, assignedTo)
+	if err != nil {
+		return []domain.HousekeepingTask{}, err
+	}
+	return result, nil
+}
+
+func (s *service) AssignTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID, staff string) (domain.HousekeepingTask, error) {
+	if staff == "" {
+		return domain.HousekeepingTask{}, fmt.Errorf("%w: staff is required", ErrInvalidTask)
+	}
+
+	task, err := s.getTask(ctx, franquiciaID, hotelID, id)
+	if err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+	if task.Status != domain.TaskPending && task.Status != domain.TaskInProgress {
+		return domain.HousekeepingTask{}, fmt.Errorf("%w: task is %s", ErrInvalidTransition, task.Status)
+	}
+
+	task.AssignedTo = staff
+	if err := s.repo.UpdateTask(ctx, task); err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+	return task, nil
+}
+
+func (s *service) StartTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (domain.HousekeepingTask, error) {
+	task, err := s.getTask(ctx, franquiciaID, hotelID, id)
+	if err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+	if task.AssignedTo == "" {
+		return domain.HousekeepingTask{}, fmt.Errorf("%w: task must be assigned before starting", ErrInvalidTask)
+	}
+
+	now := time.Now().UTC()
+	task.StartedAt = &now
+	return s.advanceTask(ctx, task, domain.TaskPending, domain.TaskInProgress, domain.RoomInProgress)
+}
+
+func (s *service) CompleteTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (domain.HousekeepingTask, error) {
+	task, err := s.getTask(ctx, franquiciaID, hotelID, id)
+	if err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+
+	now := time.Now().UTC()
+	task.CompletedAt = &now
+	return s.advanceTask(ctx, task, domain.TaskInProgress, domain.TaskDone, domain.RoomClean)
+}
+
+func (s *service) InspectTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID, inspector string) (domain.HousekeepingTask, error) {
+	task, err := s.getTask(ctx, franquiciaID, hotelID, id)
+	if err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+
+	now := time.Now().UTC()
+	task.InspectedAt = &now
+	task.InspectedBy = inspector
+	return s.advanceTask(ctx, task, domain.TaskDone, domain.TaskInspected, domain.RoomInspected)
+}
+
+func (s *service) getTask(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, id primitive.ObjectID) (domain.HousekeepingTask, error) {
+	task, err := s.repo.GetTask(ctx, id)
+	if err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+	if task.FranquiciaID != franquiciaID || task.HotelID != hotelID {
+		return domain.HousekeepingTask{}, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// advanceTask mueve la tarea de from a to y la habitación a roomStatus.
+func (s *service) advanceTask(ctx context.Context, task domain.HousekeepingTask, from, to domain.TaskStatus, roomStatus domain.RoomStatus) (domain.HousekeepingTask, error) {
+	if task.Status != from {
+		return domain.HousekeepingTask{}, fmt.Errorf("%w: task is %s, expected %s", ErrInvalidTransition, task.Status, from)
+	}
+
+	room, err := s.repo.GetRoom(ctx, task.FranquiciaID, task.HotelID, task.RoomNumber)
+	if err != nil && !errors.Is(err, ErrRoomNotFound) {
+		return domain.HousekeepingTask{}, err
+	}
+	if err == nil && !room.Status.CanTransitionTo(roomStatus) {
+		return domain.HousekeepingTask{}, fmt.Errorf("%w: room %s is %s", ErrInvalidTransition, room.Number, room.Status)
+	}
+
+	task.Status = to
+	if err := s.repo.UpdateTask(ctx, task); err != nil {
+		return domain.HousekeepingTask{}, err
+	}
+	if _, err := s.saveRoom(ctx, task.FranquiciaID, task.HotelID, task.RoomNumber, roomStatus, &task); err != nil && !errors.Is(err, ErrRoomNotFound) {
+		return domain.HousekeepingTask{}, err
+	}
+	return task, nil
+}
+
+// generateTask marca la habitación como sucia y crea la tarea de limpieza, una por día y tipo.
+func (s *service) generateTask(ctx context.Context, f domain.Folio, taskType domain.TaskType, date string) error {
+	_, err := s.repo.FindTask(ctx, f.FranquiciaID, f.HotelID, f.RoomNumber, taskType, date)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrTaskNotFound) {
+		return err
+	}
+
+	folioID := f.ID
+	task := &domain.HousekeepingTask{
+		ID:           primitive.NewObjectID(),
+		FranquiciaID: f.FranquiciaID,
+		HotelID:      f.HotelID,
+		RoomNumber:   f.RoomNumber,
+		FolioID:      &folioID,
+		Type:         taskType,
+		Status:       domain.TaskPending,
+		Date:         date,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := s.repo.CreateTask(ctx, task); err != nil {
+		log.Printf("Error al crear tarea de limpieza: %v", err)
+		return err
+	}
+
+	// Una habitación sin registrar conserva la tarea pero no se crea en el tablero.
+	_, err = s.saveRoom(ctx, f.FranquiciaID, f.HotelID, f.RoomNumber, domain.RoomDirty, task)
+	if errors.Is(err, ErrRoomNotFound) {
+		log.Printf("Tarea de limpieza %s para la habitación %s sin registrar", task.ID.Hex(), f.RoomNumber)
+		return nil
+	}
+	return err
+}
+
+// saveRoom actualiza el estado de una habitación registrada; no crea habitaciones y
+// devuelve ErrRoomNotFound si no existe.
+func (s *service) saveRoom(ctx context.Context, franquiciaID primitive.ObjectID, hotelID, number string, status domain.RoomStatus, task *domain.HousekeepingTask) (domain.Room, error) {
+	room := domain.Room{
+		FranquiciaID: franquiciaID,
+		HotelID:      hotelID,
+		Number:       number,
+		Status:       status,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if err := s.repo.SaveRoom(ctx, &room); err != nil {
+		return domain.Room{}, err
+	}
+
+	s.broker.publish(boardKey(franquiciaID, hotelID), domain.RoomStatusEvent{Room: room, Task: task})
+	return room, nil
+}
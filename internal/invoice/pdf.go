@@ -0,0 +1,121 @@
+package invoice
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/domain"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+type totalLine struct {
+	label  string
+	amount domain.Money
+}
+
+func renderPDF(inv domain.Invoice, logo []byte, w io.Writer) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Factura "+inv.Number, true)
+	pdf.AddPage()
+
+	if len(logo) > 0 {
+		if err := addLogo(pdf, logo); err != nil {
+			// El logo es opcional, la factura se emite igual.
+			log.Printf("No se pudo agregar el logo a la factura %s: %v", inv.Number, err)
+		}
+	}
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, tr(inv.Issuer.Name), "", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range issuerLines(inv.Issuer) {
+		pdf.CellFormat(0, 5, tr(line), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 7, tr("Factura N° "+inv.Number), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5, "Fecha: "+inv.IssuedAt.Format("2006-01-02"), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr("Huésped: "+inv.GuestName), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("Habitación %s, %s al %s", inv.RoomNumber, inv.CheckIn, inv.CheckOut)), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(25, 6, "Fecha", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(25, 6, "Tipo", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(100, 6, tr("Descripción"), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(40, 6, "Importe", "1", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, l := range inv.Lines {
+		pdf.CellFormat(25, 6, l.Date, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 6, string(l.Type), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(100, 6, tr(l.Description), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 6, formatMoney(l.Amount), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	totals := []totalLine{{"Subtotal", inv.Subtotal}}
+	for _, t := range inv.Taxes {
+		totals = append(totals, totalLine{fmt.Sprintf("%s (%s%%)", t.Name, t.Rate.String()), t.Amount})
+	}
+	totals = append(totals, totalLine{"Total", inv.Total}, totalLine{"Pagado", inv.Paid}, totalLine{"Saldo", inv.Balance})
+
+	for _, t := range totals {
+		pdf.CellFormat(150, 6, tr(t.label), "", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 6, formatMoney(t.amount), "", 1, "R", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
+
+func issuerLines(issuer domain.InvoiceIssuer) []string {
+	var lines []string
+	if issuer.Address != "" {
+		lines = append(lines, issuer.Address)
+	}
+	var cityLine []string
+	for _, part := range []string{issuer.ZipCode, issuer.City, issuer.Country} {
+		if part != "" {
+			cityLine = append(cityLine, part)
+		}
+	}
+	if len(cityLine) > 0 {
+		lines = append(lines, strings.Join(cityLine, " "))
+	}
+	return lines
+}
+
+func formatMoney(m domain.Money) string {
+	return m.Amount.StringFixed(domain.MinorUnits(m.Currency)) + " " + m.Currency
+}
+
+func addLogo(pdf *fpdf.Fpdf, body []byte) error {
+	var imageType string
+	switch http.DetectContentType(body) {
+	case "image/png":
+		imageType = "PNG"
+	case "image/jpeg":
+		imageType = "JPG"
+	case "image/gif":
+		imageType = "GIF"
+	default:
+		return fmt.Errorf("unsupported logo format")
+	}
+
+	opts := fpdf.ImageOptions{ImageType: imageType, ReadDpi: true}
+	pdf.RegisterImageOptionsReader("logo", opts, bytes.NewReader(body))
+	if err := pdf.Error(); err != nil {
+		pdf.ClearError()
+		return err
+	}
+	pdf.ImageOptions("logo", 10, 10, 0, 20, false, opts, 0, "")
+	return nil
+}
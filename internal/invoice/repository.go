@@ -0,0 +1,141 @@
+package invoice
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrNotFound        = errors.New("invoice not found")
+	ErrAlreadyInvoiced = errors.New("folio already has an invoice")
+)
+
+type Repository interface {
+	Create(ctx context.Context, inv *domain.Invoice) error
+	GetOne(ctx context.Context, id primitive.ObjectID) (domain.Invoice, error)
+	GetByFolio(ctx context.Context, folioID primitive.ObjectID) (domain.Invoice, error)
+	GetByHotel(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.Invoice, error)
+	NextSequence(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (int64, error)
+	ReleaseSequence(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, seq int64) error
+}
+
+type repository struct {
+	db       *mongo.Collection
+	counters *mongo.Collection
+}
+
+func NewRepository(db, counters *mongo.Collection) Repository {
+	return &repository{
+		db:       db,
+		counters: counters,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, inv *domain.Invoice) error {
+	_, err := r.db.InsertOne(ctx, inv)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", ErrAlreadyInvoiced, inv.FolioID.Hex())
+	}
+	return err
+}
+
+// EnsureIndexes crea el índice único de folio_id: un folio tiene a lo sumo una factura aunque
+// dos checkouts lleguen a emitirla a la vez.
+func EnsureIndexes(ctx context.Context, invoices *mongo.Collection) error {
+	_, err := invoices.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "folio_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("folio_id_unique"),
+	})
+	return err
+}
+
+func (r *repository) GetOne(ctx context.Context, id primitive.ObjectID) (domain.Invoice, error) {
+	var inv domain.Invoice
+	err := r.db.FindOne(ctx, bson.M{"_id": id}).Decode(&inv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return inv, ErrNotFound
+	}
+	return inv, err
+}
+
+func (r *repository) GetByFolio(ctx context.Context, folioID primitive.ObjectID) (domain.Invoice, error) {
+	var inv domain.Invoice
+	err := r.db.FindOne(ctx, bson.M{"folio_id": folioID}).Decode(&inv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return inv, ErrNotFound
+	}
+	return inv, err
+}
+
+func (r *repository) GetByHotel(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	filter := bson.M{"franquicia_id": franquiciaID}
+	if hotelID != "" {
+		filter["hotel_id"] = hotelID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "hotel_id", Value: 1}, {Key: "sequence", Value: 1}})
+	cursor, err := r.db.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var inv domain.Invoice
+		if err := cursor.Decode(&inv); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, inv)
+	}
+
+	return invoices, nil
+}
+
+type sequenceCounter struct {
+	Seq      int64   `bson:"seq"`
+	Released []int64 `bson:"released"`
+}
+
+func counterID(franquiciaID primitive.ObjectID, hotelID string) string {
+	return "invoice:" + franquiciaID.Hex() + ":" + hotelID
+}
+
+// NextSequence devuelve el menor número liberado por una factura que no se pudo guardar o,
+// si no hay ninguno, incrementa el contador del hotel. Las dos operaciones son atómicas.
+func (r *repository) NextSequence(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) (int64, error) {
+	id := counterID(franquiciaID, hotelID)
+
+	// $pop sobre el documento anterior a la actualización: released[0] es el número tomado.
+	var counter sequenceCounter
+	filter := bson.M{"_id": id, "released.0": bson.M{"$exists": true}}
+	err := r.counters.FindOneAndUpdate(ctx, filter, bson.M{"$pop": bson.M{"released": -1}}).Decode(&counter)
+	if err == nil {
+		return counter.Released[0], nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+
+	update := bson.M{"$inc": bson.M{"seq": int64(1)}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := r.counters.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&counter); err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+// ReleaseSequence devuelve al contador un número que no llegó a usarse, así el próximo
+// NextSequence lo reutiliza y la numeración del hotel no queda con huecos.
+func (r *repository) ReleaseSequence(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, seq int64) error {
+	filter := bson.M{"_id": counterID(franquiciaID, hotelID)}
+	update := bson.M{"$push": bson.M{"released": bson.M{"$each": []int64{seq}, "$sort": 1}}}
+	_, err := r.counters.UpdateOne(ctx, filter, update)
+	return err
+}
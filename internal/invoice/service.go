@@ -0,0 +1,230 @@
+package invoice
+
+import (
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/media"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Service interface {
+	IssueInvoice(ctx context.Context, f domain.Folio, issuer domain.InvoiceIssuer) (*domain.Invoice, error)
+	GetInvoice(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.Invoice, error)
+	GetInvoiceByFolio(ctx context.Context, folioID primitive.ObjectID) (domain.Invoice, error)
+	GetInvoices(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.Invoice, error)
+	RenderPDF(ctx context.Context, inv domain.Invoice, w io.Writer) error
+	InCurrency(ctx context.Context, inv domain.Invoice, to string) (domain.Invoice, error)
+}
+
+// Miniatura del logo que se imprime en el PDF.
+const logoSize = "small"
+
+// Tiempo para devolver al contador el número de una factura que no se guardó.
+const releaseTimeout = 10 * time.Second
+
+type service struct {
+	repo  Repository
+	rates currency.Service
+	media media.Service
+}
+
+// NewService crea un nuevo servicio de facturación.
+func NewService(r Repository, rates currency.Service, mediaService media.Service) Service {
+	return &service{
+		repo:  r,
+		rates: rates,
+		media: mediaService,
+	}
+}
+
+// IssueInvoice emite la factura de un folio con el próximo número correlativo del hotel. Si
+// la factura no se guarda, el número vuelve al contador para no dejar huecos.
+func (s *service) IssueInvoice(ctx context.Context, f domain.Folio, issuer domain.InvoiceIssuer) (*domain.Invoice, error) {
+	// Un folio ya facturado no consume un número.
+	if _, err := s.repo.GetByFolio(ctx, f.ID); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyInvoiced, f.ID.Hex())
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	seq, err := s.repo.NextSequence(ctx, f.FranquiciaID, f.HotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := buildInvoice(f, issuer)
+	inv.ID = primitive.NewObjectID()
+	inv.Sequence = seq
+	inv.Number = fmt.Sprintf("%s-%06d", f.HotelID, seq)
+	inv.IssuedAt = time.Now().UTC()
+
+	if err := s.repo.Create(ctx, inv); err != nil {
+		log.Printf("Error al guardar factura %s: %v", inv.Number, err)
+		s.releaseSequence(ctx, inv)
+		return nil, err
+	}
+
+	log.Printf("Factura %s emitida para folio %s", inv.Number, f.ID.Hex())
+	return inv, nil
+}
+
+// releaseSequence devuelve el número de una factura que no se guardó. Si el insert falló
+// después de escribirse (por ejemplo, un timeout), la factura existe y el número se queda.
+func (s *service) releaseSequence(ctx context.Context, inv *domain.Invoice) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	stored, err := s.repo.GetOne(ctx, inv.ID)
+	if err == nil && stored.Sequence == inv.Sequence {
+		return
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		log.Printf("No se pudo comprobar la factura %s, el número no se libera: %v", inv.Number, err)
+		return
+	}
+	if err := s.repo.ReleaseSequence(ctx, inv.FranquiciaID, inv.HotelID, inv.Sequence); err != nil {
+		log.Printf("Error al liberar el número de factura %s: %v", inv.Number, err)
+	}
+}
+
+func (s *service) GetInvoice(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.Invoice, error) {
+	inv, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if inv.FranquiciaID != franquiciaID {
+		return domain.Invoice{}, ErrNotFound
+	}
+	return inv, nil
+}
+
+// GetInvoiceByFolio devuelve la factura emitida para un folio, o ErrNotFound.
+func (s *service) GetInvoiceByFolio(ctx context.Context, folioID primitive.ObjectID) (domain.Invoice, error) {
+	return s.repo.GetByFolio(ctx, folioID)
+}
+
+func (s *service) GetInvoices(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.Invoice, error) {
+	result, err := s.repo.GetByHotel(ctx, franquiciaID, hotelID)
+	if err != nil {
+		return []domain.Invoice{}, err
+	}
+	return result, nil
+}
+
+// RenderPDF imprime el logo guardado en el blob store al crear la franquicia; no se vuelve
+// a descargar del sitio. El logo es opcional: si no se puede leer la factura sale sin él.
+func (s *service) RenderPDF(ctx context.Context, inv domain.Invoice, w io.Writer) error {
+	var logo []byte
+	if id := inv.Issuer.LogoMediaID; id != nil {
+		_, data, err := s.media.Open(ctx, *id, logoSize)
+		if err != nil {
+			log.Printf("No se pudo leer el logo %s de la factura %s: %v", id.Hex(), inv.Number, err)
+		}
+		logo = data
+	}
+	return renderPDF(inv, logo, w)
+}
+
+// InCurrency devuelve una copia de la factura con los montos en la moneda to. Todos se
+// convierten con el tipo de cambio vigente en el check-out, así los totales siguen
+// sumando lo mismo que las líneas.
+func (s *service) InCurrency(ctx context.Context, inv domain.Invoice, to string) (domain.Invoice, error) {
+	if to == inv.Subtotal.Currency {
+		return inv, nil
+	}
+	var err error
+	convert := func(m *domain.Money) {
+		if err == nil {
+			*m, err = s.rates.Convert(ctx, *m, to, inv.CheckOut)
+		}
+	}
+
+	lines := make([]domain.InvoiceLine, len(inv.Lines))
+	for i, l := range inv.Lines {
+		convert(&l.Amount)
+		lines[i] = l
+	}
+	taxes := make([]domain.InvoiceTax, len(inv.Taxes))
+	for i, t := range inv.Taxes {
+		convert(&t.Amount)
+		taxes[i] = t
+	}
+	inv.OriginalCurrency = inv.Subtotal.Currency
+	for _, m := range []*domain.Money{&inv.Subtotal, &inv.TaxTotal, &inv.Total, &inv.Paid, &inv.Balance} {
+		convert(m)
+	}
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	inv.Lines, inv.Taxes = lines, taxes
+	return inv, nil
+}
+
+func buildInvoice(f domain.Folio, issuer domain.InvoiceIssuer) *domain.Invoice {
+	inv := &domain.Invoice{
+		FranquiciaID: f.FranquiciaID,
+		HotelID:      f.HotelID,
+		FolioID:      f.ID,
+		Issuer:       issuer,
+		GuestName:    f.GuestName,
+		RoomNumber:   f.RoomNumber,
+		CheckIn:      f.CheckIn,
+		CheckOut:     f.CheckOut,
+		Lines:        make([]domain.InvoiceLine, 0, len(f.Charges)),
+		Taxes:        []domain.InvoiceTax{},
+	}
+
+	subtotal, taxTotal, paid := decimal.Zero, decimal.Zero, decimal.Zero
+	taxIndex := map[string]int{}
+
+	for _, c := range f.Charges {
+		inv.Lines = append(inv.Lines, domain.InvoiceLine{
+			Date:        c.Date,
+			Type:        c.Type,
+			Description: c.Description,
+			Amount:      c.Amount,
+		})
+		subtotal = subtotal.Add(c.Amount.Amount)
+
+		for _, t := range c.Taxes {
+			key := t.Name + "|" + t.Rate.String()
+			i, ok := taxIndex[key]
+			if !ok {
+				i = len(inv.Taxes)
+				taxIndex[key] = i
+				inv.Taxes = append(inv.Taxes, domain.InvoiceTax{
+					Name:   t.Name,
+					Rate:   t.Rate,
+					Amount: domain.NewMoney(decimal.Zero, f.Currency),
+				})
+			}
+			inv.Taxes[i].Amount.Amount = inv.Taxes[i].Amount.Amount.Add(t.Amount.Amount)
+			taxTotal = taxTotal.Add(t.Amount.Amount)
+		}
+	}
+
+	for _, p := range f.Payments {
+		if p.Kind == domain.PaymentKindRefund {
+			paid = paid.Sub(p.Amount.Amount)
+			continue
+		}
+		paid = paid.Add(p.Amount.Amount)
+	}
+
+	total := subtotal.Add(taxTotal)
+	inv.Subtotal = domain.NewMoney(subtotal, f.Currency)
+	inv.TaxTotal = domain.NewMoney(taxTotal, f.Currency)
+	inv.Total = domain.NewMoney(total, f.Currency)
+	inv.Paid = domain.NewMoney(paid, f.Currency)
+	inv.Balance = domain.NewMoney(total.Sub(paid), f.Currency)
+
+	return inv
+}
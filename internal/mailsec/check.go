@@ -0,0 +1,226 @@
+package mailsec
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrDNSUnavailable = errors.New("dns lookups failed for the email security checks")
+
+// Controles del reporte de seguridad del correo.
+const (
+	CheckSPF            = "spf"
+	CheckDMARC          = "dmarc"
+	CheckDMARCReporting = "dmarc-reporting"
+	CheckDKIM           = "dkim"
+	CheckMTASTS         = "mta-sts"
+	CheckBIMI           = "bimi"
+)
+
+// dkimMinRSAKeyBits es el largo mínimo aceptado para una clave DKIM RSA (RFC 8301).
+const dkimMinRSAKeyBits = 1024
+
+// checkWeights es cuánto suma cada control al puntaje; se normaliza a 100.
+var checkWeights = map[string]int{
+	CheckSPF:            25,
+	CheckDMARC:          30,
+	CheckDMARCReporting: 5,
+	CheckDKIM:           20,
+	CheckMTASTS:         10,
+	CheckBIMI:           5,
+}
+
+// Fetcher descarga la política MTA-STS; crawler.Factory lo implementa.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (*crawler.Response, error)
+}
+
+// Checker evalúa la protección contra suplantación del correo de un dominio.
+type Checker interface {
+	Check(ctx context.Context, domainName string) (*domain.EmailSecurity, error)
+}
+
+type checker struct {
+	resolver Resolver
+	fetcher  Fetcher
+}
+
+// NewChecker crea el evaluador con el resolver DNS y el cliente HTTP para MTA-STS.
+func NewChecker(resolver Resolver, fetcher Fetcher) Checker {
+	return &checker{resolver: resolver, fetcher: fetcher}
+}
+
+// Check consulta SPF, DMARC, DKIM, MTA-STS y BIMI del dominio. El error de una consulta
+// queda en su control; solo si fallan todas se devuelve ErrDNSUnavailable.
+func (c *checker) Check(ctx context.Context, domainName string) (*domain.EmailSecurity, error) {
+	domainName = strings.TrimSuffix(strings.ToLower(strings.TrimPrefix(domainName, "www.")), ".")
+	report := &domain.EmailSecurity{Domain: domainName, CheckedAt: time.Now().UTC()}
+
+	failed := 0
+	lookupFailed := func(name string, err error) domain.SecurityCheck {
+		failed++
+		return domain.SecurityCheck{Name: name, Detail: fmt.Sprintf("lookup failed: %v", err)}
+	}
+
+	var spfCheck, dmarcCheck, reportingCheck, dkimCheck, mtaSTSCheck, bimiCheck domain.SecurityCheck
+	var err error
+	if report.SPF, err = checkSPF(ctx, c.resolver, domainName); err != nil {
+		spfCheck = lookupFailed(CheckSPF, err)
+	} else {
+		spfCheck = gradeSPF(report.SPF)
+	}
+	if report.DMARC, err = checkDMARC(ctx, c.resolver, domainName); err != nil {
+		dmarcCheck = lookupFailed(CheckDMARC, err)
+		reportingCheck = domain.SecurityCheck{Name: CheckDMARCReporting, Detail: dmarcCheck.Detail}
+	} else {
+		dmarcCheck, reportingCheck = gradeDMARC(report.DMARC)
+	}
+	if report.DKIM, err = checkDKIM(ctx, c.resolver, domainName); err != nil {
+		dkimCheck = lookupFailed(CheckDKIM, err)
+	} else {
+		dkimCheck = gradeDKIM(report.DKIM)
+	}
+	if report.MTASTS, err = checkMTASTS(ctx, c.resolver, c.fetcher, domainName); err != nil {
+		mtaSTSCheck = lookupFailed(CheckMTASTS, err)
+	} else {
+		mtaSTSCheck = gradeMTASTS(report.MTASTS)
+	}
+	if report.BIMI, err = checkBIMI(ctx, c.resolver, domainName); err != nil {
+		bimiCheck = lookupFailed(CheckBIMI, err)
+	} else {
+		bimiCheck = gradeBIMI(report.BIMI)
+	}
+	// Las cinco consultas fallaron: el problema es el DNS, no el dominio.
+	if failed == 5 {
+		return nil, fmt.Errorf("%w: %s", ErrDNSUnavailable, domainName)
+	}
+
+	report.Checks = []domain.SecurityCheck{spfCheck, dmarcCheck, reportingCheck, dkimCheck, mtaSTSCheck, bimiCheck}
+	var total, passed int
+	for _, check := range report.Checks {
+		total += checkWeights[check.Name]
+		if check.Pass {
+			passed += checkWeights[check.Name]
+		}
+	}
+	report.Score = passed * 100 / total
+	report.Grade = domain.SecurityGrade(report.Score)
+	return report, nil
+}
+
+// gradeSPF aprueba un SPF válido, dentro del límite de consultas y que termina en -all o ~all.
+func gradeSPF(spf *domain.SPFReport) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckSPF}
+	if spf == nil {
+		check.Detail = "no SPF record"
+		return check
+	}
+	check.Value = spf.Record
+	switch {
+	case len(spf.Errors) > 0:
+		check.Detail = strings.Join(spf.Errors, "; ")
+	case spf.All == "-all", spf.All == "~all":
+		check.Pass = true
+	case spf.All == "":
+		check.Detail = "record does not end with an all mechanism"
+	default:
+		check.Detail = fmt.Sprintf("%s lets any server send mail for the domain", spf.All)
+	}
+	return check
+}
+
+// gradeDMARC aprueba una política que pone en cuarentena o rechaza todo el correo que no
+// pasa, y aparte controla que se reciban los reportes agregados.
+func gradeDMARC(dmarc *domain.DMARCReport) (domain.SecurityCheck, domain.SecurityCheck) {
+	check := domain.SecurityCheck{Name: CheckDMARC}
+	reporting := domain.SecurityCheck{Name: CheckDMARCReporting}
+	if dmarc == nil {
+		check.Detail = "no DMARC record"
+		reporting.Detail = "no DMARC record"
+		return check, reporting
+	}
+
+	check.Value = dmarc.Record
+	switch {
+	case len(dmarc.Errors) > 0:
+		check.Detail = strings.Join(dmarc.Errors, "; ")
+	case dmarc.Policy == "none":
+		check.Detail = "policy none only monitors"
+	case dmarc.Percent < 100:
+		check.Detail = fmt.Sprintf("policy applies to %d%% of the mail", dmarc.Percent)
+	default:
+		check.Pass = true
+	}
+
+	if len(dmarc.AggregateReport) > 0 {
+		reporting.Pass = true
+		reporting.Value = strings.Join(dmarc.AggregateReport, ", ")
+	} else {
+		reporting.Detail = "no aggregate report address (rua)"
+	}
+	return check, reporting
+}
+
+// gradeDKIM aprueba si alguno de los selectores habituales publica una clave vigente y
+// suficientemente larga.
+func gradeDKIM(selectors []domain.DKIMSelector) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckDKIM}
+	var names, problems []string
+	for _, s := range selectors {
+		names = append(names, s.Selector)
+		switch {
+		case s.Revoked:
+			problems = append(problems, s.Selector+" is revoked")
+		case s.KeyType == "rsa" && s.KeyBits < dkimMinRSAKeyBits:
+			problems = append(problems, fmt.Sprintf("%s uses a %d-bit RSA key", s.Selector, s.KeyBits))
+		default:
+			check.Pass = true
+		}
+	}
+	check.Value = strings.Join(names, ", ")
+	switch {
+	case len(selectors) == 0:
+		check.Detail = "no DKIM key found for the common selectors"
+	case !check.Pass:
+		check.Detail = strings.Join(problems, "; ")
+	}
+	return check
+}
+
+func gradeMTASTS(mtaSTS *domain.MTASTSReport) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckMTASTS}
+	if mtaSTS == nil {
+		check.Detail = "no MTA-STS record"
+		return check
+	}
+	check.Value = mtaSTS.Record
+	switch {
+	case mtaSTS.Error != "":
+		check.Detail = mtaSTS.Error
+	case mtaSTS.Mode != "enforce":
+		check.Detail = fmt.Sprintf("policy mode is %s", mtaSTS.Mode)
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+func gradeBIMI(bimi *domain.BIMIReport) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckBIMI}
+	switch {
+	case bimi == nil:
+		check.Detail = "no BIMI record"
+	case bimi.Logo == "":
+		check.Value = bimi.Record
+		check.Detail = "record has no logo (l=)"
+	default:
+		check.Value = bimi.Record
+		check.Pass = true
+	}
+	return check
+}
@@ -0,0 +1,110 @@
+package mailsec
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	const policyURL = "https://mta-sts.example.com/.well-known/mta-sts.txt"
+	enforced := fakeFetcher{policyURL: "version: STSv1\nmode: enforce\nmx: mx1.example.com\nmax_age: 86400\n"}
+	strong := rsaKey(t, 2048)
+
+	tests := []struct {
+		name    string
+		txt     map[string][]string
+		fetcher fakeFetcher
+		score   int
+		grade   string
+		// passed son los controles aprobados; el resto tiene que fallar con detalle.
+		passed []string
+	}{
+		{
+			name: "everything published",
+			txt: map[string][]string{
+				"example.com":                   {"v=spf1 ip4:192.0.2.0/24 -all"},
+				"_dmarc.example.com":            {"v=DMARC1; p=reject; rua=mailto:dmarc@example.com"},
+				"google._domainkey.example.com": {"v=DKIM1; p=" + strong},
+				"_mta-sts.example.com":          {"v=STSv1; id=1"},
+				"default._bimi.example.com":     {"v=BIMI1; l=https://example.com/logo.svg"},
+			},
+			fetcher: enforced,
+			score:   100,
+			grade:   "A",
+			passed:  []string{CheckSPF, CheckDMARC, CheckDMARCReporting, CheckDKIM, CheckMTASTS, CheckBIMI},
+		},
+		{
+			name: "no mta-sts nor bimi",
+			txt: map[string][]string{
+				"example.com":                   {"v=spf1 ip4:192.0.2.0/24 ~all"},
+				"_dmarc.example.com":            {"v=DMARC1; p=quarantine; rua=mailto:dmarc@example.com"},
+				"google._domainkey.example.com": {"v=DKIM1; p=" + strong},
+			},
+			// 80 de 95.
+			score:  84,
+			grade:  "B",
+			passed: []string{CheckSPF, CheckDMARC, CheckDMARCReporting, CheckDKIM},
+		},
+		{
+			name: "monitoring only",
+			txt: map[string][]string{
+				"example.com":                      {"v=spf1 +all"},
+				"_dmarc.example.com":               {"v=DMARC1; p=none; rua=mailto:dmarc@example.com"},
+				"selector1._domainkey.example.com": {"v=DKIM1; p=" + rsaKey(t, 512)},
+				"_mta-sts.example.com":             {"v=STSv1; id=1"},
+				"default._bimi.example.com":        {"v=BIMI1; l="},
+			},
+			fetcher: fakeFetcher{policyURL: "version: STSv1\nmode: testing\nmx: mx1.example.com\n"},
+			score:   5,
+			grade:   "F",
+			passed:  []string{CheckDMARCReporting},
+		},
+		{
+			name:  "nothing published",
+			score: 0,
+			grade: "F",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(fakeResolver{txt: tt.txt}, tt.fetcher)
+			report, err := c.Check(context.Background(), "www.Example.com.")
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if report.Domain != "example.com" {
+				t.Errorf("Domain = %q, want example.com", report.Domain)
+			}
+			if report.Score != tt.score || report.Grade != tt.grade {
+				t.Errorf("Score = %d (%s), want %d (%s)", report.Score, report.Grade, tt.score, tt.grade)
+			}
+
+			passed := map[string]bool{}
+			for _, name := range tt.passed {
+				passed[name] = true
+			}
+			if len(report.Checks) != len(checkWeights) {
+				t.Fatalf("got %d checks, want %d", len(report.Checks), len(checkWeights))
+			}
+			for _, check := range report.Checks {
+				if check.Pass != passed[check.Name] {
+					t.Errorf("%s: Pass = %v, want %v (%s)", check.Name, check.Pass, passed[check.Name], check.Detail)
+				}
+				if !check.Pass && check.Detail == "" {
+					t.Errorf("%s failed without a detail", check.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDNSUnavailable(t *testing.T) {
+	r := fakeResolver{err: &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}}
+	_, err := NewChecker(r, fakeFetcher{}).Check(context.Background(), "example.com")
+	if !errors.Is(err, ErrDNSUnavailable) {
+		t.Errorf("Check = %v, want ErrDNSUnavailable", err)
+	}
+}
@@ -0,0 +1,216 @@
+package mailsec
+
+import (
+	"bufio"
+	"bytes"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dkimSelectors son los selectores de los proveedores de correo más comunes; DKIM no
+// permite listar los de un dominio.
+var dkimSelectors = []string{
+	"default", "google", "selector1", "selector2", "k1", "k2", "s1", "s2",
+	"mail", "dkim", "smtp", "mandrill", "zoho", "mxvault", "everlytickey1",
+}
+
+// tagList separa un registro "v=X; k=v; ..." en etiquetas en minúscula.
+func tagList(record string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(record, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, dup := tags[name]; !dup {
+			tags[name] = strings.TrimSpace(value)
+		}
+	}
+	return tags
+}
+
+// findRecord devuelve el primer TXT de name que empieza con la versión indicada.
+func findRecord(ctx context.Context, r Resolver, name, version string) (string, error) {
+	records, err := lookupTXT(ctx, r, name)
+	if err != nil {
+		return "", err
+	}
+	for _, record := range records {
+		record = strings.TrimSpace(record)
+		if v, ok := tagList(record)["v"]; ok && strings.EqualFold(v, version) {
+			return record, nil
+		}
+	}
+	return "", nil
+}
+
+// checkDMARC devuelve nil si el dominio no publica DMARC.
+func checkDMARC(ctx context.Context, r Resolver, domainName string) (*domain.DMARCReport, error) {
+	record, err := findRecord(ctx, r, "_dmarc."+domainName, "DMARC1")
+	if err != nil || record == "" {
+		return nil, err
+	}
+
+	tags := tagList(record)
+	report := &domain.DMARCReport{
+		Record:          record,
+		Policy:          strings.ToLower(tags["p"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Percent:         100,
+		AlignmentDKIM:   alignment(tags["adkim"]),
+		AlignmentSPF:    alignment(tags["aspf"]),
+		AggregateReport: reportAddresses(tags["rua"]),
+		ForensicReport:  reportAddresses(tags["ruf"]),
+	}
+	switch report.Policy {
+	case "none", "quarantine", "reject":
+	case "":
+		report.Errors = append(report.Errors, "policy (p) is missing")
+	default:
+		report.Errors = append(report.Errors, fmt.Sprintf("unknown policy %q", report.Policy))
+	}
+	if pct, ok := tags["pct"]; ok {
+		n, err := strconv.Atoi(pct)
+		if err != nil || n < 0 || n > 100 {
+			report.Errors = append(report.Errors, fmt.Sprintf("invalid pct %q", pct))
+		} else {
+			report.Percent = n
+		}
+	}
+	return report, nil
+}
+
+func alignment(value string) string {
+	if strings.EqualFold(value, "s") {
+		return "strict"
+	}
+	return "relaxed"
+}
+
+// reportAddresses separa "mailto:a@x.com!10m,mailto:b@y.com" en direcciones.
+func reportAddresses(value string) []string {
+	var addresses []string
+	for _, uri := range strings.Split(value, ",") {
+		uri = strings.TrimSpace(uri)
+		uri, _, _ = strings.Cut(uri, "!")
+		uri = strings.TrimPrefix(strings.TrimPrefix(uri, "mailto:"), "MAILTO:")
+		if uri != "" {
+			addresses = append(addresses, uri)
+		}
+	}
+	return addresses
+}
+
+// checkDKIM prueba los selectores habituales y devuelve las claves publicadas.
+func checkDKIM(ctx context.Context, r Resolver, domainName string) ([]domain.DKIMSelector, error) {
+	var found []domain.DKIMSelector
+	for _, selector := range dkimSelectors {
+		records, err := lookupTXT(ctx, r, selector+"._domainkey."+domainName)
+		if err != nil {
+			return found, err
+		}
+		for _, record := range records {
+			tags := tagList(record)
+			key, ok := tags["p"]
+			if !ok {
+				continue
+			}
+			found = append(found, dkimKey(selector, tags["k"], key))
+			break
+		}
+	}
+	return found, nil
+}
+
+func dkimKey(selector, keyType, key string) domain.DKIMSelector {
+	s := domain.DKIMSelector{Selector: selector, KeyType: strings.ToLower(keyType)}
+	if s.KeyType == "" {
+		s.KeyType = "rsa"
+	}
+	key = strings.Join(strings.Fields(key), "")
+	if key == "" {
+		s.Revoked = true
+		return s
+	}
+
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return s
+	}
+	if s.KeyType == "ed25519" {
+		if len(der) == ed25519.PublicKeySize {
+			s.KeyBits = 256
+		}
+		return s
+	}
+	if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+		if rsaKey, ok := pub.(*rsa.PublicKey); ok {
+			s.KeyBits = rsaKey.N.BitLen()
+		}
+	} else if rsaKey, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		s.KeyBits = rsaKey.N.BitLen()
+	}
+	return s
+}
+
+// checkMTASTS lee el registro _mta-sts y, si existe, la política publicada por HTTPS.
+func checkMTASTS(ctx context.Context, r Resolver, fetcher Fetcher, domainName string) (*domain.MTASTSReport, error) {
+	record, err := findRecord(ctx, r, "_mta-sts."+domainName, "STSv1")
+	if err != nil || record == "" {
+		return nil, err
+	}
+
+	report := &domain.MTASTSReport{Record: record, ID: tagList(record)["id"]}
+	resp, err := fetcher.Fetch(ctx, "https://mta-sts."+domainName+"/.well-known/mta-sts.txt")
+	if err != nil {
+		report.Error = err.Error()
+		return report, nil
+	}
+
+	version := ""
+	scanner := bufio.NewScanner(bytes.NewReader(resp.Body))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "version":
+			version = value
+		case "mode":
+			report.Mode = strings.ToLower(value)
+		case "mx":
+			report.MX = append(report.MX, value)
+		case "max_age":
+			report.MaxAge, _ = strconv.Atoi(value)
+		}
+	}
+	switch {
+	case version != "STSv1":
+		report.Error = "policy version is not STSv1"
+	case report.Mode != "enforce" && report.Mode != "testing" && report.Mode != "none":
+		report.Error = fmt.Sprintf("unknown policy mode %q", report.Mode)
+	case report.Mode != "none" && len(report.MX) == 0:
+		report.Error = "policy lists no mx"
+	}
+	return report, nil
+}
+
+// checkBIMI devuelve nil si el dominio no publica BIMI en el selector default.
+func checkBIMI(ctx context.Context, r Resolver, domainName string) (*domain.BIMIReport, error) {
+	record, err := findRecord(ctx, r, "default._bimi."+domainName, "BIMI1")
+	if err != nil || record == "" {
+		return nil, err
+	}
+	tags := tagList(record)
+	return &domain.BIMIReport{Record: record, Logo: tags["l"], Authority: tags["a"]}, nil
+}
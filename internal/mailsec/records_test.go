@@ -0,0 +1,208 @@
+package mailsec
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// fakeFetcher sirve las políticas MTA-STS desde un mapa; una URL ausente falla.
+type fakeFetcher map[string]string
+
+func (f fakeFetcher) Fetch(_ context.Context, url string) (*crawler.Response, error) {
+	body, ok := f[url]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return &crawler.Response{URL: url, StatusCode: 200, Body: []byte(body)}, nil
+}
+
+// rsaKey devuelve una clave pública RSA de bits en base64, como la publica DKIM.
+func rsaKey(t *testing.T, bits int) string {
+	t.Helper()
+	n := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	der, err := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: n.Add(n, big.NewInt(1)), E: 65537})
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func TestCheckDMARC(t *testing.T) {
+	tests := []struct {
+		name   string
+		txt    []string
+		want   *domain.DMARCReport
+		errors []string
+	}{
+		{
+			name: "no record",
+			txt:  []string{"v=spf1 -all"},
+		},
+		{
+			name: "full record",
+			txt:  []string{"v=DMARC1; p=Reject; sp=quarantine; pct=50; adkim=s; rua=mailto:a@example.com!10m, mailto:b@example.net; ruf=mailto:f@example.com"},
+			want: &domain.DMARCReport{
+				Record:          "v=DMARC1; p=Reject; sp=quarantine; pct=50; adkim=s; rua=mailto:a@example.com!10m, mailto:b@example.net; ruf=mailto:f@example.com",
+				Policy:          "reject",
+				SubdomainPolicy: "quarantine",
+				Percent:         50,
+				AlignmentDKIM:   "strict",
+				AlignmentSPF:    "relaxed",
+				AggregateReport: []string{"a@example.com", "b@example.net"},
+				ForensicReport:  []string{"f@example.com"},
+			},
+		},
+		{
+			name: "missing policy and invalid pct",
+			txt:  []string{"v=DMARC1; pct=150"},
+			want: &domain.DMARCReport{
+				Record:        "v=DMARC1; pct=150",
+				Percent:       100,
+				AlignmentDKIM: "relaxed",
+				AlignmentSPF:  "relaxed",
+				Errors:        []string{"policy (p) is missing", `invalid pct "150"`},
+			},
+		},
+		{
+			name: "unknown policy",
+			txt:  []string{"v=DMARC1; p=block"},
+			want: &domain.DMARCReport{
+				Record:        "v=DMARC1; p=block",
+				Policy:        "block",
+				Percent:       100,
+				AlignmentDKIM: "relaxed",
+				AlignmentSPF:  "relaxed",
+				Errors:        []string{`unknown policy "block"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := fakeResolver{txt: map[string][]string{"_dmarc.example.com": tt.txt}}
+			got, err := checkDMARC(context.Background(), r, "example.com")
+			if err != nil {
+				t.Fatalf("checkDMARC: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkDMARC =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDKIM(t *testing.T) {
+	ed := base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	r := fakeResolver{txt: map[string][]string{
+		"google._domainkey.example.com":    {"v=DKIM1; k=rsa; p=" + rsaKey(t, 2048)},
+		"selector1._domainkey.example.com": {"v=DKIM1; p=" + rsaKey(t, 512)},
+		"s1._domainkey.example.com":        {"v=DKIM1; k=ed25519; p=" + ed},
+		"k1._domainkey.example.com":        {"v=DKIM1; k=rsa; p="},
+		"mail._domainkey.example.com":      {"not a key"},
+	}}
+
+	got, err := checkDKIM(context.Background(), r, "example.com")
+	if err != nil {
+		t.Fatalf("checkDKIM: %v", err)
+	}
+	want := []domain.DKIMSelector{
+		{Selector: "google", KeyType: "rsa", KeyBits: 2048},
+		{Selector: "selector1", KeyType: "rsa", KeyBits: 512},
+		{Selector: "k1", KeyType: "rsa", Revoked: true},
+		{Selector: "s1", KeyType: "ed25519", KeyBits: 256},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkDKIM =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCheckMTASTS(t *testing.T) {
+	const policyURL = "https://mta-sts.example.com/.well-known/mta-sts.txt"
+	tests := []struct {
+		name   string
+		policy fakeFetcher
+		want   *domain.MTASTSReport
+	}{
+		{
+			name:   "enforced policy",
+			policy: fakeFetcher{policyURL: "version: STSv1\r\nmode: enforce\r\nmx: mx1.example.com\r\nmx: *.example.net\r\nmax_age: 604800\r\n"},
+			want: &domain.MTASTSReport{
+				Record: "v=STSv1; id=20240101",
+				ID:     "20240101",
+				Mode:   "enforce",
+				MX:     []string{"mx1.example.com", "*.example.net"},
+				MaxAge: 604800,
+			},
+		},
+		{
+			name:   "policy unreachable",
+			policy: fakeFetcher{},
+			want:   &domain.MTASTSReport{Record: "v=STSv1; id=20240101", ID: "20240101", Error: "connection refused"},
+		},
+		{
+			name:   "wrong version",
+			policy: fakeFetcher{policyURL: "version: STSv2\nmode: enforce\nmx: mx1.example.com\n"},
+			want: &domain.MTASTSReport{
+				Record: "v=STSv1; id=20240101",
+				ID:     "20240101",
+				Mode:   "enforce",
+				MX:     []string{"mx1.example.com"},
+				Error:  "policy version is not STSv1",
+			},
+		},
+		{
+			name:   "testing without mx",
+			policy: fakeFetcher{policyURL: "version: STSv1\nmode: testing\n"},
+			want: &domain.MTASTSReport{
+				Record: "v=STSv1; id=20240101",
+				ID:     "20240101",
+				Mode:   "testing",
+				Error:  "policy lists no mx",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := fakeResolver{txt: map[string][]string{"_mta-sts.example.com": {"v=STSv1; id=20240101"}}}
+			got, err := checkMTASTS(context.Background(), r, tt.policy, "example.com")
+			if err != nil {
+				t.Fatalf("checkMTASTS: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkMTASTS =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckBIMI(t *testing.T) {
+	r := fakeResolver{txt: map[string][]string{
+		"default._bimi.example.com": {"v=BIMI1; l=https://example.com/logo.svg; a=https://example.com/vmc.pem"},
+	}}
+	got, err := checkBIMI(context.Background(), r, "example.com")
+	if err != nil {
+		t.Fatalf("checkBIMI: %v", err)
+	}
+	want := &domain.BIMIReport{
+		Record:    "v=BIMI1; l=https://example.com/logo.svg; a=https://example.com/vmc.pem",
+		Logo:      "https://example.com/logo.svg",
+		Authority: "https://example.com/vmc.pem",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkBIMI = %+v, want %+v", got, want)
+	}
+
+	if got, err := checkBIMI(context.Background(), fakeResolver{}, "example.com"); got != nil || err != nil {
+		t.Errorf("checkBIMI without record = %+v, %v, want nil", got, err)
+	}
+}
@@ -0,0 +1,46 @@
+package mailsec
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// Resolver consulta los registros TXT, de direcciones y MX. *net.Resolver lo implementa;
+// en pruebas se puede usar un stub en memoria o un servidor DNS local con NewResolver.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// NewResolver usa el resolver del sistema o, si addr no está vacío (por ejemplo
+// "127.0.0.1:5353"), consulta solo a ese servidor.
+func NewResolver(addr string) Resolver {
+	if addr == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: 5 * time.Second}
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// lookupTXT trata "no existe" como una respuesta vacía; solo los errores de red o del
+// servidor se devuelven.
+func lookupTXT(ctx context.Context, r Resolver, name string) ([]string, error) {
+	records, err := r.LookupTXT(ctx, name)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return records, err
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
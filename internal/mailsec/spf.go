@@ -0,0 +1,241 @@
+package mailsec
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Límites de RFC 7208 4.6.4: consultas DNS por evaluación, consultas sin resultado y
+// hosts de un mecanismo mx.
+const (
+	spfMaxLookups     = 10
+	spfMaxVoidLookups = 2
+	spfMaxMXHosts     = 10
+)
+
+// errMultipleSPF es un error permanente: el dominio publica más de un registro SPF.
+var errMultipleSPF = errors.New("multiple SPF records")
+
+// spfWalker recorre el SPF del dominio expandiendo include y redirect. Cada evaluación
+// cuenta, aunque el mismo dominio se incluya dos veces, y sigue contando después de pasar
+// el límite para informar el total real de consultas.
+type spfWalker struct {
+	resolver Resolver
+	report   *domain.SPFReport
+	voids    int
+}
+
+// checkSPF devuelve nil si el dominio no publica SPF.
+func checkSPF(ctx context.Context, r Resolver, domainName string) (*domain.SPFReport, error) {
+	record, err := spfRecord(ctx, r, domainName)
+	if errors.Is(err, errMultipleSPF) {
+		return &domain.SPFReport{Errors: []string{err.Error()}}, nil
+	}
+	if err != nil || record == "" {
+		return nil, err
+	}
+
+	w := &spfWalker{resolver: r, report: &domain.SPFReport{Record: record}}
+	w.report.All = w.walk(ctx, []string{strings.ToLower(domainName)}, record)
+	if w.report.Lookups > spfMaxLookups {
+		w.problem("%d DNS lookups exceed the limit of %d", w.report.Lookups, spfMaxLookups)
+	}
+	if w.voids > spfMaxVoidLookups {
+		w.problem("%d void lookups exceed the limit of %d", w.voids, spfMaxVoidLookups)
+	}
+	return w.report, nil
+}
+
+// spfRecord busca el único registro "v=spf1" del dominio. Más de uno es un error.
+func spfRecord(ctx context.Context, r Resolver, domainName string) (string, error) {
+	records, err := lookupTXT(ctx, r, domainName)
+	if err != nil {
+		return "", err
+	}
+	return selectSPF(domainName, records)
+}
+
+func selectSPF(domainName string, records []string) (string, error) {
+	var found []string
+	for _, record := range records {
+		lower := strings.ToLower(strings.TrimSpace(record))
+		if lower == "v=spf1" || strings.HasPrefix(lower, "v=spf1 ") {
+			found = append(found, strings.TrimSpace(record))
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("%w: %s publishes %d", errMultipleSPF, domainName, len(found))
+}
+
+func (w *spfWalker) problem(format string, args ...interface{}) {
+	w.report.Errors = append(w.report.Errors, fmt.Sprintf(format, args...))
+}
+
+// exhausted indica que se pasó con holgura el límite y no tiene sentido seguir consultando.
+func (w *spfWalker) exhausted() bool {
+	return w.report.Lookups > 3*spfMaxLookups
+}
+
+// walk evalúa un registro y devuelve su mecanismo "all" con el calificador; si no tiene,
+// el del registro al que redirige. path es la cadena de include y redirect hasta el
+// dominio actual, que es el último.
+func (w *spfWalker) walk(ctx context.Context, path []string, record string) string {
+	domainName := path[len(path)-1]
+	var all, redirect string
+	for _, term := range strings.Fields(record)[1:] {
+		lower := strings.ToLower(term)
+		if name, value, ok := strings.Cut(lower, "="); ok && !strings.ContainsAny(name, ":/") {
+			// exp y los modificadores desconocidos no afectan el resultado (RFC 7208 6).
+			if name == "redirect" {
+				redirect = value
+			}
+			continue
+		}
+
+		qualifier := "+"
+		if strings.ContainsAny(lower[:1], "+-~?") {
+			qualifier, lower = lower[:1], lower[1:]
+		}
+		mechanism, arg, _ := strings.Cut(lower, ":")
+		mechanism, _, _ = strings.Cut(mechanism, "/")
+		switch mechanism {
+		case "all":
+			all = qualifier + "all"
+		case "include":
+			w.report.Lookups++
+			w.include(ctx, path, arg)
+		case "a", "exists":
+			w.report.Lookups++
+			if mechanism == "exists" && arg == "" {
+				w.problem("exists without a domain")
+				continue
+			}
+			w.lookupHost(ctx, targetDomain(arg, domainName))
+		case "mx":
+			w.report.Lookups++
+			w.lookupMX(ctx, targetDomain(arg, domainName))
+		case "ptr":
+			w.report.Lookups++
+			w.problem("%s uses the deprecated ptr mechanism", domainName)
+		case "ip4", "ip6":
+		default:
+			w.problem("%s has an unknown mechanism %q", domainName, term)
+		}
+	}
+
+	// redirect solo aplica si no hay "all" (RFC 7208 6.1). Un destino sin SPF es un
+	// error permanente.
+	if redirect != "" && all == "" {
+		w.report.Lookups++
+		record, ok := w.follow(ctx, path, redirect)
+		if ok && record == "" {
+			w.problem("redirect=%s has no SPF record (permerror)", redirect)
+		}
+		if record != "" {
+			return w.walk(ctx, append(path[:len(path):len(path)], redirect), record)
+		}
+	}
+	return all
+}
+
+// include expande un include; su "all" no afecta el resultado del dominio, pero un
+// destino sin SPF es un error permanente (RFC 7208 5.2).
+func (w *spfWalker) include(ctx context.Context, path []string, target string) {
+	if target == "" {
+		w.problem("include without a domain")
+		return
+	}
+	w.report.Includes = append(w.report.Includes, target)
+	record, ok := w.follow(ctx, path, target)
+	if ok && record == "" {
+		w.problem("include:%s has no SPF record (permerror)", target)
+	}
+	if record != "" {
+		w.walk(ctx, append(path[:len(path):len(path)], target), record)
+	}
+}
+
+// follow obtiene el SPF de un include o redirect. ok es false si no se consultó: el
+// dominio tiene macros, que dependen del remitente y no se pueden resolver acá, cierra un
+// ciclo, se pasó el límite o falló la consulta.
+func (w *spfWalker) follow(ctx context.Context, path []string, target string) (record string, ok bool) {
+	if strings.Contains(target, "%{") || w.exhausted() {
+		return "", false
+	}
+	for _, name := range path {
+		if name == target {
+			w.problem("%s includes itself through %s", target, strings.Join(path, " -> "))
+			return "", false
+		}
+	}
+
+	records, err := lookupTXT(ctx, w.resolver, target)
+	if err != nil {
+		w.problem("%v", err)
+		return "", false
+	}
+	if len(records) == 0 {
+		w.voids++
+	}
+	record, err = selectSPF(target, records)
+	if err != nil {
+		w.problem("%v", err)
+		return "", false
+	}
+	return record, true
+}
+
+// lookupHost resuelve el dominio de un mecanismo a o exists para contar las consultas
+// sin resultado.
+func (w *spfWalker) lookupHost(ctx context.Context, target string) {
+	if strings.Contains(target, "%{") || w.exhausted() {
+		return
+	}
+	addrs, err := w.resolver.LookupHost(ctx, target)
+	if err != nil && !isNotFound(err) {
+		w.problem("%v", err)
+		return
+	}
+	if len(addrs) == 0 {
+		w.voids++
+	}
+}
+
+// lookupMX resuelve un mecanismo mx. Solo el mecanismo cuenta para el límite de consultas;
+// las de direcciones de sus hosts tienen su propio tope (RFC 7208 §4.6.4) y más de
+// spfMaxMXHosts es un error permanente.
+func (w *spfWalker) lookupMX(ctx context.Context, target string) {
+	if strings.Contains(target, "%{") || w.exhausted() {
+		return
+	}
+	hosts, err := w.resolver.LookupMX(ctx, target)
+	if err != nil && !isNotFound(err) {
+		w.problem("%v", err)
+		return
+	}
+	if len(hosts) == 0 {
+		w.voids++
+		return
+	}
+	if len(hosts) > spfMaxMXHosts {
+		w.problem("mx:%s has %d hosts, more than the limit of %d (permerror)", target, len(hosts), spfMaxMXHosts)
+	}
+}
+
+// targetDomain es el dominio de un mecanismo a o mx, sin la longitud de prefijo; sin
+// argumento es el dominio evaluado.
+func targetDomain(arg, domainName string) string {
+	arg, _, _ = strings.Cut(arg, "/")
+	if arg == "" {
+		return domainName
+	}
+	return arg
+}
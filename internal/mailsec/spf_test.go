@@ -0,0 +1,212 @@
+package mailsec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeResolver responde desde mapas en memoria; un nombre ausente es NXDOMAIN. Con err
+// todas las consultas fallan con ese error.
+type fakeResolver struct {
+	txt   map[string][]string
+	hosts map[string][]string
+	mx    map[string]int
+	err   error
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if records, ok := r.txt[name]; ok {
+		return records, nil
+	}
+	return nil, notFound(name)
+}
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, notFound(host)
+}
+
+func (r fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	n, ok := r.mx[name]
+	if !ok {
+		return nil, notFound(name)
+	}
+	hosts := make([]*net.MX, n)
+	for i := range hosts {
+		hosts[i] = &net.MX{Host: fmt.Sprintf("mx%d.%s.", i, name), Pref: 10}
+	}
+	return hosts, nil
+}
+
+func TestCheckSPF(t *testing.T) {
+	tests := []struct {
+		name     string
+		txt      map[string][]string
+		hosts    map[string][]string
+		mx       map[string]int
+		lookups  int
+		all      string
+		includes []string
+		errors   []string
+	}{
+		{
+			name:    "only addresses",
+			txt:     map[string][]string{"example.com": {"v=spf1 ip4:192.0.2.1 -all"}},
+			lookups: 0,
+			all:     "-all",
+		},
+		{
+			name:  "a and mx with answers",
+			txt:   map[string][]string{"example.com": {"v=spf1 a mx:mail.example.com/24 ~all"}},
+			hosts: map[string][]string{"example.com": {"192.0.2.1"}},
+			mx:    map[string]int{"mail.example.com": 2},
+			// a y mx; las direcciones de los hosts del MX no cuentan.
+			lookups: 2,
+			all:     "~all",
+		},
+		{
+			name: "duplicate includes count every evaluation",
+			txt: map[string][]string{
+				"example.com":      {"v=spf1 include:_spf.vendor.com include:_spf.vendor.com -all"},
+				"_spf.vendor.com":  {"v=spf1 include:_spf2.vendor.com ~all"},
+				"_spf2.vendor.com": {"v=spf1 ip4:192.0.2.0/24 ~all"},
+			},
+			lookups:  4,
+			all:      "-all",
+			includes: []string{"_spf.vendor.com", "_spf2.vendor.com", "_spf.vendor.com", "_spf2.vendor.com"},
+		},
+		{
+			name: "duplicate includes past the limit",
+			txt: map[string][]string{
+				"example.com":     {"v=spf1 include:_spf.vendor.com include:_spf.vendor.com include:_spf.vendor.com -all"},
+				"_spf.vendor.com": {"v=spf1 a a a ~all"},
+			},
+			hosts:    map[string][]string{"_spf.vendor.com": {"192.0.2.1"}},
+			lookups:  12,
+			all:      "-all",
+			includes: []string{"_spf.vendor.com", "_spf.vendor.com", "_spf.vendor.com"},
+			errors:   []string{"12 DNS lookups exceed the limit of 10"},
+		},
+		{
+			name: "include without an SPF record",
+			txt: map[string][]string{
+				"example.com":       {"v=spf1 include:gone.example.net include:other.example.net -all"},
+				"other.example.net": {"google-site-verification=abc"},
+			},
+			lookups:  2,
+			all:      "-all",
+			includes: []string{"gone.example.net", "other.example.net"},
+			errors: []string{
+				"include:gone.example.net has no SPF record (permerror)",
+				"include:other.example.net has no SPF record (permerror)",
+			},
+		},
+		{
+			name:    "redirect without an SPF record",
+			txt:     map[string][]string{"example.com": {"v=spf1 redirect=gone.example.net"}},
+			lookups: 1,
+			errors:  []string{"redirect=gone.example.net has no SPF record (permerror)"},
+		},
+		{
+			name: "redirect",
+			txt: map[string][]string{
+				"example.com":      {"v=spf1 redirect=_spf.example.com"},
+				"_spf.example.com": {"v=spf1 mx -all"},
+			},
+			mx:      map[string]int{"_spf.example.com": 1},
+			lookups: 2,
+			all:     "-all",
+		},
+		{
+			name:    "void lookups of a, mx and exists",
+			txt:     map[string][]string{"example.com": {"v=spf1 a:gone1.example.com mx:gone2.example.com exists:gone3.example.com -all"}},
+			lookups: 3,
+			all:     "-all",
+			errors:  []string{"3 void lookups exceed the limit of 2"},
+		},
+		{
+			name:    "mx hosts count toward the limit",
+			txt:     map[string][]string{"example.com": {"v=spf1 mx -all"}},
+			mx:      map[string]int{"example.com": 11},
+			lookups: 1,
+			all:     "-all",
+			errors:  []string{"mx:example.com has 11 hosts, more than the limit of 10 (permerror)"},
+		},
+		{
+			name: "mx hosts do not count toward the limit",
+			txt: map[string][]string{
+				"example.com":           {"v=spf1 mx include:_spf.google.com ~all"},
+				"_spf.google.com":       {"v=spf1 include:_netblocks.google.com ~all"},
+				"_netblocks.google.com": {"v=spf1 ip4:192.0.2.0/24 ~all"},
+			},
+			mx:       map[string]int{"example.com": 5},
+			lookups:  3,
+			all:      "~all",
+			includes: []string{"_spf.google.com", "_netblocks.google.com"},
+		},
+		{
+			name: "include loop",
+			txt: map[string][]string{
+				"example.com":   {"v=spf1 include:a.example.com -all"},
+				"a.example.com": {"v=spf1 include:example.com ~all"},
+			},
+			lookups:  2,
+			all:      "-all",
+			includes: []string{"a.example.com", "example.com"},
+			errors:   []string{"example.com includes itself through example.com -> a.example.com"},
+		},
+		{
+			name:     "macros are not resolved",
+			txt:      map[string][]string{"example.com": {"v=spf1 exists:%{i}._spf.example.com include:%{d}.example.net -all"}},
+			lookups:  2,
+			all:      "-all",
+			includes: []string{"%{d}.example.net"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := fakeResolver{txt: tt.txt, hosts: tt.hosts, mx: tt.mx}
+			report, err := checkSPF(context.Background(), r, "example.com")
+			if err != nil {
+				t.Fatalf("checkSPF: %v", err)
+			}
+			if report.Lookups != tt.lookups {
+				t.Errorf("Lookups = %d, want %d", report.Lookups, tt.lookups)
+			}
+			if report.All != tt.all {
+				t.Errorf("All = %q, want %q", report.All, tt.all)
+			}
+			if !reflect.DeepEqual(report.Includes, tt.includes) {
+				t.Errorf("Includes = %q, want %q", report.Includes, tt.includes)
+			}
+			if !reflect.DeepEqual(report.Errors, tt.errors) {
+				t.Errorf("Errors = %q, want %q", report.Errors, tt.errors)
+			}
+		})
+	}
+}
+
+func TestCheckSPFMultipleRecords(t *testing.T) {
+	r := fakeResolver{txt: map[string][]string{"example.com": {"v=spf1 -all", "v=spf1 ~all"}}}
+	report, err := checkSPF(context.Background(), r, "example.com")
+	if err != nil {
+		t.Fatalf("checkSPF: %v", err)
+	}
+	if len(report.Errors) != 1 || !strings.Contains(report.Errors[0], "multiple SPF records") {
+		t.Errorf("Errors = %q, want multiple SPF records", report.Errors)
+	}
+}
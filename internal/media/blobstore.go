@@ -0,0 +1,16 @@
+package media
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore guarda el contenido de las imágenes. Las claves son rutas relativas
+// ("sha256/ab/abcd...") y el contenido de una clave no cambia una vez escrito.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,87 @@
+package media
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNotFound = errors.New("media not found")
+
+type Repository interface {
+	Create(ctx context.Context, asset *domain.MediaAsset) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (domain.MediaAsset, error)
+	GetByHash(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, kind domain.MediaKind, hash string) (domain.MediaAsset, error)
+	GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.MediaAsset, error)
+	DeleteByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID) error
+}
+
+type repository struct {
+	collection *mongo.Collection
+}
+
+func NewRepository(collection *mongo.Collection) Repository {
+	return &repository{collection: collection}
+}
+
+func (r *repository) Create(ctx context.Context, asset *domain.MediaAsset) error {
+	_, err := r.collection.InsertOne(ctx, asset)
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, id primitive.ObjectID) (domain.MediaAsset, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *repository) GetByHash(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, kind domain.MediaKind, hash string) (domain.MediaAsset, error) {
+	filter := bson.M{"franquicia_id": franquiciaID, "kind": kind, "hash": hash}
+	if hotelID != "" {
+		filter["hotel_id"] = hotelID
+	} else {
+		filter["hotel_id"] = bson.M{"$exists": false}
+	}
+	return r.findOne(ctx, filter)
+}
+
+// GetByFranquicia devuelve las imágenes de la franquicia; con hotelID solo las de ese hotel.
+func (r *repository) GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.MediaAsset, error) {
+	filter := bson.M{"franquicia_id": franquiciaID}
+	if hotelID != "" {
+		filter["hotel_id"] = hotelID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var assets []domain.MediaAsset
+	for cursor.Next(ctx) {
+		var asset domain.MediaAsset
+		if err := cursor.Decode(&asset); err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, cursor.Err()
+}
+
+func (r *repository) DeleteByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"franquicia_id": franquiciaID})
+	return err
+}
+
+func (r *repository) findOne(ctx context.Context, filter bson.M) (domain.MediaAsset, error) {
+	var asset domain.MediaAsset
+	err := r.collection.FindOne(ctx, filter).Decode(&asset)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return asset, ErrNotFound
+	}
+	return asset, err
+}
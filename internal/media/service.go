@@ -0,0 +1,175 @@
+package media
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/website"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidMedia  = errors.New("invalid media")
+	ErrImageTooLarge = fmt.Errorf("%w: image too large", ErrInvalidMedia)
+	ErrUnknownSize   = errors.New("unknown media size")
+)
+
+// MaxUploadSize es el tamaño máximo de una imagen subida.
+const MaxUploadSize = 10 << 20
+
+// Service guarda logos, favicons y fotos en el BlobStore con sus miniaturas.
+// Recibe context.Context porque también se usa desde la creación de franquicias.
+type Service interface {
+	Store(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, kind domain.MediaKind, sourceURL string, data []byte) (domain.MediaAsset, error)
+	GetAssets(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.MediaAsset, error)
+	Open(ctx context.Context, id primitive.ObjectID, size string) (domain.MediaVariant, []byte, error)
+	DeleteAssets(ctx context.Context, franquiciaID primitive.ObjectID) error
+}
+
+type service struct {
+	repo  Repository
+	blobs BlobStore
+}
+
+// NewService crea un nuevo servicio de imágenes.
+func NewService(r Repository, blobs BlobStore) Service {
+	return &service{
+		repo:  r,
+		blobs: blobs,
+	}
+}
+
+// Store valida la imagen, la guarda junto con sus miniaturas y registra sus metadatos.
+// Si la franquicia ya tiene la misma imagen (mismo hash) devuelve la existente.
+func (s *service) Store(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string, kind domain.MediaKind, sourceURL string, data []byte) (domain.MediaAsset, error) {
+	if !kind.IsValid() {
+		return domain.MediaAsset{}, fmt.Errorf("%w: kind %q", ErrInvalidMedia, kind)
+	}
+	if len(data) == 0 || len(data) > MaxUploadSize {
+		return domain.MediaAsset{}, fmt.Errorf("%w: size %d bytes", ErrInvalidMedia, len(data))
+	}
+	contentType, width, height, err := website.InspectImage(data)
+	if err != nil {
+		return domain.MediaAsset{}, fmt.Errorf("%w: %v", ErrInvalidMedia, err)
+	}
+	// Los SVG y los ICO no se decodifican, así que sus dimensiones no importan.
+	if contentType != "image/svg+xml" && contentType != "image/x-icon" && width*height > MaxPixels {
+		return domain.MediaAsset{}, fmt.Errorf("%w: %dx%d pixels", ErrImageTooLarge, width, height)
+	}
+
+	hash := sha256Hex(data)
+	existing, err := s.repo.GetByHash(ctx, franquiciaID, hotelID, kind, hash)
+	if err == nil {
+		return existing, nil
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return domain.MediaAsset{}, err
+	}
+
+	original, err := s.put(ctx, domain.MediaOriginal, contentType, width, height, data)
+	if err != nil {
+		return domain.MediaAsset{}, err
+	}
+	asset := domain.MediaAsset{
+		ID:           primitive.NewObjectID(),
+		FranquiciaID: franquiciaID,
+		HotelID:      hotelID,
+		Kind:         kind,
+		SourceURL:    sourceURL,
+		Hash:         hash,
+		Variants:     []domain.MediaVariant{original},
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	// Sin miniaturas la imagen sigue sirviendo: se entrega la original en todos los tamaños.
+	thumbs, err := makeThumbnails(data, contentType)
+	if err != nil {
+		log.Printf("Error al generar miniaturas de %s: %v", hash, err)
+	}
+	for _, t := range thumbs {
+		variant, err := s.put(ctx, t.size, t.contentType, t.width, t.height, t.data)
+		if err != nil {
+			return domain.MediaAsset{}, err
+		}
+		asset.Variants = append(asset.Variants, variant)
+	}
+
+	if err := s.repo.Create(ctx, &asset); err != nil {
+		return domain.MediaAsset{}, err
+	}
+	return asset, nil
+}
+
+// put sube el contenido con una clave derivada de su hash, así dos imágenes iguales
+// comparten el mismo blob.
+func (s *service) put(ctx context.Context, size, contentType string, width, height int, data []byte) (domain.MediaVariant, error) {
+	hash := sha256Hex(data)
+	key := fmt.Sprintf("sha256/%s/%s", hash[:2], hash)
+	if err := s.blobs.Put(ctx, key, contentType, data); err != nil {
+		return domain.MediaVariant{}, fmt.Errorf("error guardando %s: %w", key, err)
+	}
+	return domain.MediaVariant{
+		Size:        size,
+		ContentType: contentType,
+		Width:       width,
+		Height:      height,
+		Bytes:       len(data),
+		Hash:        hash,
+		Key:         key,
+	}, nil
+}
+
+func (s *service) GetAssets(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.MediaAsset, error) {
+	return s.repo.GetByFranquicia(ctx, franquiciaID, hotelID)
+}
+
+// DeleteAssets borra las imágenes de la franquicia. Los blobs quedan: la clave sale del
+// hash y pueden estar compartidos con imágenes de otras franquicias.
+func (s *service) DeleteAssets(ctx context.Context, franquiciaID primitive.ObjectID) error {
+	return s.repo.DeleteByFranquicia(ctx, franquiciaID)
+}
+
+// Open devuelve una variante de la imagen. Si la miniatura pedida no existe (la original
+// es más chica o no es rasterizable) se devuelve la original.
+func (s *service) Open(ctx context.Context, id primitive.ObjectID, size string) (domain.MediaVariant, []byte, error) {
+	if size == "" {
+		size = domain.MediaOriginal
+	}
+	if !validSize(size) {
+		return domain.MediaVariant{}, nil, fmt.Errorf("%w: %q", ErrUnknownSize, size)
+	}
+
+	asset, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.MediaVariant{}, nil, err
+	}
+	variant := asset.Variants[0]
+	for _, v := range asset.Variants {
+		if v.Size == size {
+			variant = v
+			break
+		}
+	}
+
+	data, err := s.blobs.Get(ctx, variant.Key)
+	if err != nil {
+		return domain.MediaVariant{}, nil, err
+	}
+	return variant, data, nil
+}
+
+func validSize(size string) bool {
+	if size == domain.MediaOriginal {
+		return true
+	}
+	for _, t := range thumbnailSizes {
+		if t.Name == size {
+			return true
+		}
+	}
+	return false
+}
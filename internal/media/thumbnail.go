@@ -0,0 +1,92 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// MaxPixels limita el tamaño de las imágenes que se decodifican: un PNG de pocos KB puede
+// declarar 50000x50000 píxeles y ocupar gigas de memoria al decodificarlo.
+const MaxPixels = 40_000_000
+
+// thumbnailSizes son las miniaturas que se generan, por lado mayor en píxeles.
+var thumbnailSizes = []struct {
+	Name string
+	Max  int
+}{
+	{"thumb", 128},
+	{"small", 320},
+	{"medium", 800},
+}
+
+type thumbnail struct {
+	size        string
+	contentType string
+	width       int
+	height      int
+	data        []byte
+}
+
+// makeThumbnails genera las miniaturas más chicas que la imagen original. Las imágenes
+// que no se pueden decodificar (SVG, ICO) no tienen miniaturas: se sirven siempre enteras.
+// Los JPEG quedan en JPEG y el resto en PNG para no perder la transparencia.
+func makeThumbnails(data []byte, contentType string) ([]thumbnail, error) {
+	if contentType == "image/svg+xml" || contentType == "image/x-icon" {
+		return nil, nil
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var thumbs []thumbnail
+	bounds := src.Bounds()
+	for _, size := range thumbnailSizes {
+		w, h := fit(bounds.Dx(), bounds.Dy(), size.Max)
+		if w >= bounds.Dx() && h >= bounds.Dy() {
+			continue
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+		var buf bytes.Buffer
+		thumbType := "image/png"
+		if contentType == "image/jpeg" {
+			thumbType = contentType
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return nil, err
+		}
+		thumbs = append(thumbs, thumbnail{size: size.Name, contentType: thumbType, width: w, height: h, data: buf.Bytes()})
+	}
+	return thumbs, nil
+}
+
+// fit escala width x height para que el lado mayor sea limit, manteniendo la proporción.
+func fit(width, height, limit int) (int, int) {
+	if width >= height {
+		if width <= limit {
+			return width, height
+		}
+		return limit, max(1, height*limit/width)
+	}
+	if height <= limit {
+		return width, height
+	}
+	return max(1, width*limit/height), limit
+}
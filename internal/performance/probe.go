@@ -0,0 +1,300 @@
+package performance
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// acceptEncoding se envía explícitamente para ver qué compresión usa el sitio.
+const acceptEncoding = "br, gzip, deflate"
+
+// maxResources acota cuántos recursos de la home se miden y resourceParallelism cuántos
+// se piden a la vez.
+const (
+	maxResources        = 60
+	resourceParallelism = 2
+)
+
+// maxBodySize acota lo que se lee de cada respuesta.
+const maxBodySize = 10 << 20
+
+const maxRedirects = 10
+
+// timings registra los instantes de httptrace. start es el primer pedido de conexión, así
+// el total no incluye robots.txt ni la espera del límite por dominio. Los de hop se
+// reinician en cada petición, así las fases quedan con los valores de la última
+// redirección.
+type timings struct {
+	mu    sync.Mutex
+	start time.Time
+	hop   hopTimings
+}
+
+type hopTimings struct {
+	requested           time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	firstByte           time.Time
+}
+
+func (t *timings) trace() *httptrace.ClientTrace {
+	at := func(dst *time.Time) {
+		t.mu.Lock()
+		*dst = time.Now()
+		t.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			t.hop = hopTimings{requested: time.Now()}
+			if t.start.IsZero() {
+				t.start = t.hop.requested
+			}
+			t.mu.Unlock()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { at(&t.hop.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { at(&t.hop.dnsDone) },
+		ConnectStart:         func(string, string) { at(&t.hop.connStart) },
+		ConnectDone:          func(string, string, error) { at(&t.hop.connDone) },
+		TLSHandshakeStart:    func() { at(&t.hop.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { at(&t.hop.tlsDone) },
+		GotFirstResponseByte: func() { at(&t.hop.firstByte) },
+	}
+}
+
+func millis(from, to time.Time) int64 {
+	if from.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from).Milliseconds()
+}
+
+// probe mide la home: tiempos de cada fase, protocolo, compresión y peso de la página con
+// sus recursos. Un sitio caído no es un error: la medición queda con Up en false. Solo
+// falla si robots.txt no permite visitar la home.
+func probe(ctx context.Context, factory crawler.Factory, siteURL string) (*domain.PerformanceSample, error) {
+	if !strings.HasPrefix(siteURL, "http://") && !strings.HasPrefix(siteURL, "https://") {
+		siteURL = "https://" + siteURL
+	}
+	sample := &domain.PerformanceSample{URL: siteURL}
+	client := factory.NewProbeClient()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		sample.Redirects = len(via)
+		return nil
+	}
+
+	t := &timings{}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, t.trace()), http.MethodGet, siteURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := client.Do(req)
+	if errors.Is(err, crawler.ErrDisallowedByRobots) {
+		return nil, err
+	}
+	if err != nil {
+		sample.Error = err.Error()
+		return sample, nil
+	}
+	defer resp.Body.Close()
+	sample.URL = resp.Request.URL.String()
+	sample.StatusCode = resp.StatusCode
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	end := time.Now()
+	if err != nil {
+		sample.Error = err.Error()
+		return sample, nil
+	}
+
+	t.mu.Lock()
+	start, hop := t.start, t.hop
+	t.mu.Unlock()
+	sample.DNSMs = millis(hop.dnsStart, hop.dnsDone)
+	sample.ConnectMs = millis(hop.connStart, hop.connDone)
+	sample.TLSMs = millis(hop.tlsStart, hop.tlsDone)
+	sample.TTFBMs = millis(hop.requested, hop.firstByte)
+	sample.DownloadMs = millis(hop.firstByte, end)
+	sample.TotalMs = millis(start, end)
+
+	sample.Up = resp.StatusCode < 400
+	sample.Protocol = resp.Proto
+	sample.HTTP2 = resp.ProtoMajor == 2
+	sample.HTTP3 = advertisesHTTP3(resp.Header.Get("Alt-Svc"))
+	sample.Compression = strings.ToLower(resp.Header.Get("Content-Encoding"))
+	sample.HTMLBytes = int64(len(body))
+	sample.PageWeight = sample.HTMLBytes
+	sample.Requests = 1
+	if !sample.Up {
+		return sample, nil
+	}
+
+	html, err := decode(body, sample.Compression)
+	if err != nil {
+		// Brotli no se puede descomprimir con la biblioteca estándar: se pide la home de
+		// nuevo por el crawler, que la recibe sin comprimir o en gzip.
+		page, fetchErr := factory.Fetch(ctx, sample.URL)
+		if fetchErr != nil {
+			return sample, nil
+		}
+		html = page.Body
+	}
+	for _, size := range resourceSizes(ctx, client, resources(resp.Request.URL, html)) {
+		sample.PageWeight += size
+		sample.Requests++
+	}
+	return sample, nil
+}
+
+// advertisesHTTP3 revisa si Alt-Svc ofrece h3 o alguno de sus drafts (h3-29, ...).
+func advertisesHTTP3(altSvc string) bool {
+	for _, alt := range strings.Split(altSvc, ",") {
+		protocol, _, _ := strings.Cut(strings.TrimSpace(alt), "=")
+		if protocol == "h3" || strings.HasPrefix(protocol, "h3-") {
+			return true
+		}
+	}
+	return false
+}
+
+func decode(body []byte, encoding string) ([]byte, error) {
+	var r io.Reader
+	switch encoding {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	case "deflate":
+		// Debería venir con el envoltorio zlib, pero algunos servidores mandan deflate crudo.
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			r = flate.NewReader(bytes.NewReader(body))
+		} else {
+			r = zr
+		}
+	default:
+		return nil, errors.New("unsupported encoding " + encoding)
+	}
+	return io.ReadAll(io.LimitReader(r, maxBodySize))
+}
+
+// resources devuelve las URLs que el navegador pediría al cargar la home: scripts, hojas
+// de estilo, imágenes, iframes y precargas, sin repetir.
+func resources(base *url.URL, html []byte) []string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var urls []string
+	add := func(raw string) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || strings.HasPrefix(raw, "data:") || len(urls) >= maxResources {
+			return
+		}
+		ref, err := url.Parse(raw)
+		if err != nil {
+			return
+		}
+		u := base.ResolveReference(ref)
+		u.Fragment = ""
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return
+		}
+		if key := u.String(); !seen[key] {
+			seen[key] = true
+			urls = append(urls, key)
+		}
+	}
+	doc.Find("script[src], img[src], iframe[src], source[src], video[src], audio[src]").Each(func(_ int, s *goquery.Selection) {
+		add(s.AttrOr("src", ""))
+	})
+	doc.Find(`link[rel~="stylesheet"], link[rel~="preload"], link[rel~="modulepreload"], link[rel~="icon"]`).Each(func(_ int, s *goquery.Selection) {
+		add(s.AttrOr("href", ""))
+	})
+	return urls
+}
+
+// resourceSizes devuelve los bytes transferidos por cada recurso que respondió. Se usa
+// Content-Length de un HEAD y, si el servidor no lo informa, se descarga el recurso.
+func resourceSizes(ctx context.Context, client *http.Client, urls []string) []int64 {
+	sizes := make([]int64, len(urls))
+	ok := make([]bool, len(urls))
+	sem := make(chan struct{}, resourceParallelism)
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, u string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sizes[i], ok[i] = resourceSize(ctx, client, u)
+		}(i, u)
+	}
+	wg.Wait()
+
+	var measured []int64
+	for i, size := range sizes {
+		if ok[i] {
+			measured = append(measured, size)
+		}
+	}
+	return measured
+}
+
+func resourceSize(ctx context.Context, client *http.Client, u string) (int64, bool) {
+	if size, ok := request(ctx, client, http.MethodHead, u); ok && size >= 0 {
+		return size, true
+	}
+	size, ok := request(ctx, client, http.MethodGet, u)
+	return size, ok && size >= 0
+}
+
+// request devuelve Content-Length en un HEAD y los bytes leídos en un GET; -1 si el HEAD
+// no informa el largo.
+func request(ctx context.Context, client *http.Client, method, u string) (int64, bool) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return 0, false
+	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return 0, false
+	}
+	if method == http.MethodHead {
+		return resp.ContentLength, true
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodySize))
+	return n, err == nil
+}
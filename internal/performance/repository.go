@@ -0,0 +1,65 @@
+package performance
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository interface {
+	Create(ctx context.Context, sample *domain.PerformanceSample) error
+	// GetByFranquicia devuelve las mediciones en [from, to), de la más vieja a la más nueva;
+	// cualquiera de los extremos puede ser cero.
+	GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID, from, to time.Time) ([]domain.PerformanceSample, error)
+}
+
+type repository struct {
+	db *mongo.Collection
+}
+
+func NewRepository(db *mongo.Collection) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, sample *domain.PerformanceSample) error {
+	_, err := r.db.InsertOne(ctx, sample)
+	return err
+}
+
+func (r *repository) GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID, from, to time.Time) ([]domain.PerformanceSample, error) {
+	filter := bson.M{"franquicia_id": franquiciaID}
+	created := bson.M{}
+	if !from.IsZero() {
+		created["$gte"] = from
+	}
+	if !to.IsZero() {
+		created["$lt"] = to
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+
+	samples := []domain.PerformanceSample{}
+	cursor, err := r.db.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var sample domain.PerformanceSample
+		if err := cursor.Decode(&sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples, nil
+}
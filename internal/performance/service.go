@@ -0,0 +1,177 @@
+package performance
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/period"
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Métricas del resumen; los nombres coinciden con los campos JSON de la medición.
+const (
+	MetricDNS        = "dns_ms"
+	MetricConnect    = "connect_ms"
+	MetricTLS        = "tls_ms"
+	MetricTTFB       = "ttfb_ms"
+	MetricDownload   = "download_ms"
+	MetricTotal      = "total_ms"
+	MetricHTMLBytes  = "html_bytes"
+	MetricPageWeight = "page_weight"
+	MetricRequests   = "requests"
+)
+
+var metrics = map[string]func(domain.PerformanceSample) int64{
+	MetricDNS:        func(s domain.PerformanceSample) int64 { return s.DNSMs },
+	MetricConnect:    func(s domain.PerformanceSample) int64 { return s.ConnectMs },
+	MetricTLS:        func(s domain.PerformanceSample) int64 { return s.TLSMs },
+	MetricTTFB:       func(s domain.PerformanceSample) int64 { return s.TTFBMs },
+	MetricDownload:   func(s domain.PerformanceSample) int64 { return s.DownloadMs },
+	MetricTotal:      func(s domain.PerformanceSample) int64 { return s.TotalMs },
+	MetricHTMLBytes:  func(s domain.PerformanceSample) int64 { return s.HTMLBytes },
+	MetricPageWeight: func(s domain.PerformanceSample) int64 { return s.PageWeight },
+	MetricRequests:   func(s domain.PerformanceSample) int64 { return int64(s.Requests) },
+}
+
+// Service mide la disponibilidad y la velocidad de los sitios y guarda la serie.
+type Service interface {
+	Run(ctx context.Context, franquiciaID primitive.ObjectID) (*domain.PerformanceSample, error)
+	RunAll(ctx context.Context) error
+	GetSamples(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) ([]domain.PerformanceSample, error)
+	GetSummary(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) (*domain.PerformanceSummary, error)
+}
+
+type service struct {
+	repo        Repository
+	franquicias franquicia.Repository
+	crawler     crawler.Factory
+}
+
+// NewService crea un nuevo servicio de mediciones de los sitios.
+func NewService(r Repository, franquicias franquicia.Repository, crawler crawler.Factory) Service {
+	return &service{
+		repo:        r,
+		franquicias: franquicias,
+		crawler:     crawler,
+	}
+}
+
+// Run mide la home de la franquicia y guarda la medición, aun si el sitio no respondió.
+func (s *service) Run(ctx context.Context, franquiciaID primitive.ObjectID) (*domain.PerformanceSample, error) {
+	f, err := s.franquicias.GetOne(ctx, franquiciaID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, f)
+}
+
+func (s *service) run(ctx context.Context, f domain.Franquicia) (*domain.PerformanceSample, error) {
+	sample, err := probe(ctx, s.crawler, f.URL)
+	if err != nil {
+		return nil, err
+	}
+	sample.ID = primitive.NewObjectID()
+	sample.FranquiciaID = f.ID
+	sample.CreatedAt = time.Now().UTC()
+	if err := s.repo.Create(ctx, sample); err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+// RunAll mide los sitios de todas las franquicias. Un sitio que falla no detiene al resto.
+func (s *service) RunAll(ctx context.Context) error {
+	franquicias, err := s.franquicias.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range franquicias {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := s.run(ctx, f); err != nil {
+			log.Printf("Error al medir el sitio de %s: %v", f.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *service) GetSamples(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) ([]domain.PerformanceSample, error) {
+	start, end, err := period.Parse(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByFranquicia(ctx, franquiciaID, start, end)
+}
+
+// GetSummary calcula la disponibilidad del período y los percentiles de cada métrica.
+func (s *service) GetSummary(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) (*domain.PerformanceSummary, error) {
+	samples, err := s.GetSamples(ctx, franquiciaID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(samples), nil
+}
+
+func summarize(samples []domain.PerformanceSample) *domain.PerformanceSummary {
+	summary := &domain.PerformanceSummary{
+		Samples: len(samples),
+		Metrics: map[string]domain.MetricSummary{},
+	}
+	var up []domain.PerformanceSample
+	http2 := 0
+	for _, sample := range samples {
+		if sample.Up {
+			up = append(up, sample)
+			if sample.HTTP2 {
+				http2++
+			}
+		}
+	}
+	if len(samples) > 0 {
+		summary.Uptime = ratio(len(up), len(samples))
+	}
+	if len(up) == 0 {
+		return summary
+	}
+	summary.HTTP2 = ratio(http2, len(up))
+
+	for name, value := range metrics {
+		values := make([]int64, len(up))
+		var total int64
+		for i, sample := range up {
+			values[i] = value(sample)
+			total += values[i]
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		summary.Metrics[name] = domain.MetricSummary{
+			Min:  values[0],
+			Mean: total / int64(len(values)),
+			P50:  percentile(values, 50),
+			P75:  percentile(values, 75),
+			P90:  percentile(values, 90),
+			P95:  percentile(values, 95),
+			P99:  percentile(values, 99),
+			Max:  values[len(values)-1],
+		}
+	}
+	return summary
+}
+
+// percentile usa el método del rango más cercano sobre valores ya ordenados.
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// ratio devuelve n/total como porcentaje con dos decimales.
+func ratio(n, total int) float64 {
+	return math.Round(float64(n)*10000/float64(total)) / 100
+}
@@ -0,0 +1,31 @@
+// Package period interpreta los rangos de fechas from/to de los reportes históricos.
+package period
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid period")
+
+// Parse interpreta from y to como fechas YYYY-MM-DD; to incluye el día completo, así que
+// end es el inicio del día siguiente. Un extremo vacío queda en cero.
+func Parse(from, to string) (start, end time.Time, err error) {
+	if from != "" {
+		if start, err = time.Parse(domain.DateLayout, from); err != nil {
+			return start, end, fmt.Errorf("%w: from: %v", ErrInvalid, err)
+		}
+	}
+	if to != "" {
+		if end, err = time.Parse(domain.DateLayout, to); err != nil {
+			return start, end, fmt.Errorf("%w: to: %v", ErrInvalid, err)
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, fmt.Errorf("%w: from is after to", ErrInvalid)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,63 @@
+package rateplan
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// minPercent es el límite (excluido) de los ajustes porcentuales: -100% deja la tarifa en cero.
+var minPercent = hundred.Neg()
+
+// nightlyRate calcula la tarifa de una noche. chain contiene el plan cotizado
+// seguido de sus planes padre, terminando en un plan no derivado. Cada plan derivado
+// parte de la tarifa de su padre, salvo que tenga una temporada propia para esa noche,
+// y después aplica sus ajustes por día de la semana.
+func nightlyRate(chain []domain.RatePlan, night time.Time) (domain.Money, string) {
+	root := chain[len(chain)-1]
+	rate, season := root.BaseRate, ""
+	if s := seasonOf(root, night); s != nil {
+		rate, season = s.Rate, s.Name
+	}
+	rate.Amount = dayOfWeekRate(root, night, rate.Amount)
+
+	for i := len(chain) - 2; i >= 0; i-- {
+		p := chain[i]
+		if s := seasonOf(p, night); s != nil {
+			rate, season = s.Rate, s.Name
+		} else {
+			rate.Amount = applyPercent(rate.Amount, p.Derived.Percent).Add(p.Derived.Amount.Amount)
+		}
+		rate.Amount = dayOfWeekRate(p, night, rate.Amount)
+	}
+
+	return rate.Round(), season
+}
+
+// seasonOf devuelve la temporada de rp que cubre la noche, o nil.
+func seasonOf(rp domain.RatePlan, night time.Time) *domain.Season {
+	day := night.Format(domain.DateLayout)
+	for i, s := range rp.Seasons {
+		// Las fechas tienen formato fijo, la comparación de strings respeta el orden cronológico.
+		if day >= s.StartDate && day <= s.EndDate {
+			return &rp.Seasons[i]
+		}
+	}
+	return nil
+}
+
+func dayOfWeekRate(rp domain.RatePlan, night time.Time, amount decimal.Decimal) decimal.Decimal {
+	for _, adj := range rp.DayOfWeekAdjustments {
+		if adj.Weekday == night.Weekday() {
+			amount = applyPercent(amount, adj.Percent)
+		}
+	}
+	return amount
+}
+
+func applyPercent(amount, percent decimal.Decimal) decimal.Decimal {
+	return amount.Mul(hundred.Add(percent)).Div(hundred)
+}
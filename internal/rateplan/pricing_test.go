@@ -0,0 +1,209 @@
+package rateplan
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	franchiseID = primitive.NewObjectID()
+	// 2026-01-03 es sábado y 2026-01-05, lunes.
+	saturday = date("2026-01-03")
+	monday   = date("2026-01-05")
+	summer   = date("2026-07-10")
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(domain.DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func usd(amount string) domain.Money {
+	return domain.NewMoney(decimal.RequireFromString(amount), "USD")
+}
+
+func percent(p string) decimal.Decimal {
+	return decimal.RequireFromString(p)
+}
+
+// bar es un plan raíz de 100 USD con temporada alta en julio y +20% los sábados.
+func bar() domain.RatePlan {
+	return domain.RatePlan{
+		ID:           primitive.NewObjectID(),
+		FranquiciaID: franchiseID,
+		HotelID:      "HTL-1",
+		Code:         "BAR",
+		Type:         domain.RatePlanBAR,
+		BaseRate:     usd("100"),
+		Seasons: []domain.Season{
+			{Name: "summer", StartDate: "2026-07-01", EndDate: "2026-07-31", Rate: usd("150")},
+		},
+		DayOfWeekAdjustments: []domain.DayOfWeekAdjustment{
+			{Weekday: time.Saturday, Percent: percent("20")},
+		},
+	}
+}
+
+func derived(parent domain.RatePlan, pct, amount string) domain.RatePlan {
+	rp := domain.RatePlan{
+		ID:           primitive.NewObjectID(),
+		FranquiciaID: parent.FranquiciaID,
+		HotelID:      parent.HotelID,
+		Code:         "DRV",
+		Derived:      &domain.DerivedRate{ParentID: parent.ID, Percent: percent(pct)},
+	}
+	if amount != "" {
+		rp.Derived.Amount = usd(amount)
+	}
+	return rp
+}
+
+func TestNightlyRate(t *testing.T) {
+	root := bar()
+	corporate := derived(root, "-10", "")
+	// package = corporate + 15 USD, con su propia temporada y -50% los lunes.
+	pkg := derived(corporate, "0", "15")
+	pkg.Seasons = []domain.Season{{Name: "package-week", StartDate: "2026-01-05", EndDate: "2026-01-11", Rate: usd("200")}}
+	pkg.DayOfWeekAdjustments = []domain.DayOfWeekAdjustment{{Weekday: time.Monday, Percent: percent("-50")}}
+
+	tests := []struct {
+		name   string
+		chain  []domain.RatePlan
+		night  time.Time
+		rate   string
+		season string
+	}{
+		{name: "base rate", chain: []domain.RatePlan{root}, night: date("2026-01-06"), rate: "100"},
+		{name: "season", chain: []domain.RatePlan{root}, night: summer, rate: "150", season: "summer"},
+		{name: "day of week", chain: []domain.RatePlan{root}, night: saturday, rate: "120"},
+		{name: "derived percent", chain: []domain.RatePlan{corporate, root}, night: date("2026-01-06"), rate: "90"},
+		{name: "derived from parent season", chain: []domain.RatePlan{corporate, root}, night: summer, rate: "135", season: "summer"},
+		{name: "derived from parent day of week", chain: []domain.RatePlan{corporate, root}, night: saturday, rate: "108"},
+		{name: "derived amount over a chain", chain: []domain.RatePlan{pkg, corporate, root}, night: saturday, rate: "123"},
+		// La temporada propia reemplaza la tarifa derivada y después aplica su día de la semana.
+		{name: "own season and day of week", chain: []domain.RatePlan{pkg, corporate, root}, night: monday, rate: "100", season: "package-week"},
+		{name: "rounds to minor units", chain: []domain.RatePlan{derived(root, "-33.333", ""), root}, night: date("2026-01-06"), rate: "66.67"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, season := nightlyRate(tt.chain, tt.night)
+			if !rate.Amount.Equal(decimal.RequireFromString(tt.rate)) || rate.Currency != "USD" {
+				t.Errorf("rate = %s %s, want %s USD", rate.Amount, rate.Currency, tt.rate)
+			}
+			if season != tt.season {
+				t.Errorf("season = %q, want %q", season, tt.season)
+			}
+		})
+	}
+}
+
+// fakeRepository guarda los planes en memoria; solo GetOne se usa al resolver cadenas.
+type fakeRepository struct {
+	Repository
+	plans map[primitive.ObjectID]domain.RatePlan
+}
+
+func (r fakeRepository) GetOne(_ context.Context, id primitive.ObjectID) (domain.RatePlan, error) {
+	rp, ok := r.plans[id]
+	if !ok {
+		return domain.RatePlan{}, ErrNotFound
+	}
+	return rp, nil
+}
+
+func TestResolveChain(t *testing.T) {
+	root := bar()
+	corporate := derived(root, "-10", "")
+
+	otherHotel := bar()
+	otherHotel.HotelID = "HTL-2"
+	fromOtherHotel := derived(otherHotel, "0", "")
+	fromOtherHotel.HotelID = root.HotelID
+
+	cycleA := derived(root, "0", "")
+	cycleB := derived(cycleA, "0", "")
+	cycleA.Derived.ParentID = cycleB.ID
+
+	deep := []domain.RatePlan{root}
+	for i := 0; i < maxDerivationDepth; i++ {
+		deep = append(deep, derived(deep[len(deep)-1], "-1", ""))
+	}
+
+	euros := derived(root, "0", "")
+	euros.Derived.Amount = domain.NewMoney(decimal.NewFromInt(10), "EUR")
+
+	tests := []struct {
+		name  string
+		plan  domain.RatePlan
+		chain int
+		err   error
+	}{
+		{name: "root", plan: root, chain: 1},
+		{name: "derived", plan: derived(corporate, "5", "10"), chain: 3},
+		{name: "missing parent", plan: derived(bar(), "0", ""), err: ErrInvalidRatePlan},
+		{name: "parent in another hotel", plan: fromOtherHotel, err: ErrInvalidRatePlan},
+		{name: "circular", plan: cycleA, err: ErrInvalidRatePlan},
+		{name: "too deep", plan: deep[len(deep)-1], err: ErrInvalidRatePlan},
+		{name: "amount in another currency", plan: euros, err: ErrInvalidRatePlan},
+		{name: "percent of -100", plan: derived(root, "-100", ""), err: ErrInvalidRatePlan},
+		{name: "percent below -100", plan: derived(corporate, "-150", ""), err: ErrInvalidRatePlan},
+	}
+
+	plans := map[primitive.ObjectID]domain.RatePlan{}
+	for _, rp := range append([]domain.RatePlan{root, corporate, otherHotel, cycleA, cycleB}, deep...) {
+		plans[rp.ID] = rp
+	}
+	s := &service{repo: fakeRepository{plans: plans}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := s.resolveChain(context.Background(), tt.plan)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("resolveChain = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveChain: %v", err)
+			}
+			if len(chain) != tt.chain || chain[0].ID != tt.plan.ID || chain[len(chain)-1].Derived != nil {
+				t.Errorf("chain of %d plans ending in %+v, want %d plans ending in the root", len(chain), chain[len(chain)-1].Derived, tt.chain)
+			}
+		})
+	}
+}
+
+func TestQuoteRejects(t *testing.T) {
+	root := bar()
+	below := derived(root, "0", "-100")
+	s := &service{repo: fakeRepository{plans: map[primitive.ObjectID]domain.RatePlan{root.ID: root, below.ID: below}}}
+
+	tests := []struct {
+		name     string
+		plan     domain.RatePlan
+		checkOut string
+		err      error
+	}{
+		{name: "stay too long", plan: root, checkOut: "2099-01-01", err: ErrInvalidStay},
+		{name: "non-positive nightly rate", plan: below, checkOut: "2026-01-07", err: ErrInvalidRatePlan},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Quote(context.Background(), franchiseID, tt.plan.ID, "2026-01-05", tt.checkOut, "")
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Quote = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
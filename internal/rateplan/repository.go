@@ -0,0 +1,96 @@
+package rateplan
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNotFound = errors.New("rate plan not found")
+
+type Repository interface {
+	Create(ctx context.Context, rp *domain.RatePlan) error
+	Update(ctx context.Context, rp domain.RatePlan) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	GetOne(ctx context.Context, id primitive.ObjectID) (domain.RatePlan, error)
+	GetByHotel(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.RatePlan, error)
+	CountDerived(ctx context.Context, parentID primitive.ObjectID) (int64, error)
+}
+
+type repository struct {
+	db *mongo.Collection
+}
+
+func NewRepository(db *mongo.Collection) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, rp *domain.RatePlan) error {
+	_, err := r.db.InsertOne(ctx, rp)
+	return err
+}
+
+func (r *repository) Update(ctx context.Context, rp domain.RatePlan) error {
+	res, err := r.db.ReplaceOne(ctx, bson.M{"_id": rp.ID}, rp)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.db.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetOne(ctx context.Context, id primitive.ObjectID) (domain.RatePlan, error) {
+	var rp domain.RatePlan
+	err := r.db.FindOne(ctx, bson.M{"_id": id}).Decode(&rp)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return rp, ErrNotFound
+	}
+	return rp, err
+}
+
+func (r *repository) GetByHotel(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.RatePlan, error) {
+	var plans []domain.RatePlan
+	filter := bson.M{"franquicia_id": franquiciaID}
+	if hotelID != "" {
+		filter["hotel_id"] = hotelID
+	}
+	cursor, err := r.db.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var rp domain.RatePlan
+		if err := cursor.Decode(&rp); err != nil {
+			return nil, err
+		}
+		plans = append(plans, rp)
+	}
+
+	return plans, nil
+}
+
+// CountDerived cuenta los planes que derivan directamente de parentID.
+func (r *repository) CountDerived(ctx context.Context, parentID primitive.ObjectID) (int64, error) {
+	return r.db.CountDocuments(ctx, bson.M{"derived.parent_id": parentID})
+}
@@ -0,0 +1,298 @@
+package rateplan
+
+import (
+	"clubhub-hotel-management/internal/currency"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cantidad máxima de planes encadenados (ej: package -> corporate -> BAR).
+const maxDerivationDepth = 5
+
+// Noches máximas de una cotización. Cada noche puede requerir una conversión de moneda, que
+// consulta la base, así que el rango se acota antes de recorrerlo.
+const maxQuoteNights = 365
+
+var (
+	ErrInvalidRatePlan   = errors.New("invalid rate plan")
+	ErrInvalidStay       = errors.New("invalid stay dates")
+	ErrStayRestriction   = errors.New("stay does not meet rate plan restrictions")
+	ErrFranchiseNotFound = errors.New("franchise not found")
+	ErrRatePlanInUse     = errors.New("rate plan has derived plans")
+)
+
+type Service interface {
+	CreateRatePlan(ctx context.Context, rp *domain.RatePlan) error
+	UpdateRatePlan(ctx context.Context, rp domain.RatePlan) error
+	DeleteRatePlan(ctx context.Context, franquiciaID, id primitive.ObjectID) error
+	GetRatePlan(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.RatePlan, error)
+	GetRatePlans(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.RatePlan, error)
+	Quote(ctx context.Context, franquiciaID, id primitive.ObjectID, checkIn, checkOut, currency string) (*domain.RateQuote, error)
+}
+
+type service struct {
+	repo        Repository
+	rates       currency.Service
+	franquicias franquicia.Repository
+}
+
+// NewService crea un nuevo servicio de planes tarifarios.
+func NewService(r Repository, rates currency.Service, franquicias franquicia.Repository) Service {
+	return &service{
+		repo:        r,
+		rates:       rates,
+		franquicias: franquicias,
+	}
+}
+
+func (s *service) CreateRatePlan(ctx context.Context, rp *domain.RatePlan) error {
+	if _, err := s.franquicias.GetOne(ctx, rp.FranquiciaID.Hex()); errors.Is(err, franquicia.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrFranchiseNotFound, rp.FranquiciaID.Hex())
+	} else if err != nil {
+		return err
+	}
+
+	rp.ID = primitive.NewObjectID()
+	if err := s.validate(ctx, *rp); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, rp); err != nil {
+		log.Printf("Error al crear plan tarifario: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) UpdateRatePlan(ctx context.Context, rp domain.RatePlan) error {
+	if _, err := s.GetRatePlan(ctx, rp.FranquiciaID, rp.ID); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, rp); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, rp)
+}
+
+// DeleteRatePlan no borra un plan del que derivan otros: quedarían cotizando contra un
+// padre inexistente.
+func (s *service) DeleteRatePlan(ctx context.Context, franquiciaID, id primitive.ObjectID) error {
+	if _, err := s.GetRatePlan(ctx, franquiciaID, id); err != nil {
+		return err
+	}
+	derived, err := s.repo.CountDerived(ctx, id)
+	if err != nil {
+		return err
+	}
+	if derived > 0 {
+		return fmt.Errorf("%w: %d plans derive from it", ErrRatePlanInUse, derived)
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) GetRatePlan(ctx context.Context, franquiciaID, id primitive.ObjectID) (domain.RatePlan, error) {
+	rp, err := s.repo.GetOne(ctx, id)
+	if err != nil {
+		return domain.RatePlan{}, err
+	}
+	if rp.FranquiciaID != franquiciaID {
+		return domain.RatePlan{}, ErrNotFound
+	}
+	return rp, nil
+}
+
+func (s *service) GetRatePlans(ctx context.Context, franquiciaID primitive.ObjectID, hotelID string) ([]domain.RatePlan, error) {
+	result, err := s.repo.GetByHotel(ctx, franquiciaID, hotelID)
+	if err != nil {
+		return []domain.RatePlan{}, err
+	}
+	return result, nil
+}
+
+// Quote cotiza una estadía noche por noche. checkIn y checkOut usan domain.DateLayout;
+// la noche del checkOut no se cobra. Si se indica toCurrency, cada noche se convierte con
+// el tipo de cambio vigente en esa fecha.
+func (s *service) Quote(ctx context.Context, franquiciaID, id primitive.ObjectID, checkIn, checkOut, toCurrency string) (*domain.RateQuote, error) {
+	rp, err := s.GetRatePlan(ctx, franquiciaID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := time.Parse(domain.DateLayout, checkIn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: check_in: %v", ErrInvalidStay, err)
+	}
+	out, err := time.Parse(domain.DateLayout, checkOut)
+	if err != nil {
+		return nil, fmt.Errorf("%w: check_out: %v", ErrInvalidStay, err)
+	}
+
+	nights := int(out.Sub(in).Hours() / 24)
+	if nights <= 0 {
+		return nil, fmt.Errorf("%w: check_out must be after check_in", ErrInvalidStay)
+	}
+	if nights > maxQuoteNights {
+		return nil, fmt.Errorf("%w: stays longer than %d nights can't be quoted", ErrInvalidStay, maxQuoteNights)
+	}
+	if rp.MinLOS > 0 && nights < rp.MinLOS {
+		return nil, fmt.Errorf("%w: minimum stay is %d nights", ErrStayRestriction, rp.MinLOS)
+	}
+	if rp.MaxLOS > 0 && nights > rp.MaxLOS {
+		return nil, fmt.Errorf("%w: maximum stay is %d nights", ErrStayRestriction, rp.MaxLOS)
+	}
+
+	chain, err := s.resolveChain(ctx, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	planCurrency := chain[len(chain)-1].BaseRate.Currency
+	if toCurrency == "" {
+		toCurrency = planCurrency
+	}
+
+	quote := &domain.RateQuote{
+		RatePlanID: rp.ID,
+		HotelID:    rp.HotelID,
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		Nights:     make([]domain.QuoteNight, 0, nights),
+		Total:      domain.NewMoney(decimal.Zero, toCurrency),
+	}
+	for night := in; night.Before(out); night = night.AddDate(0, 0, 1) {
+		rate, season := nightlyRate(chain, night)
+		// Un monto fijo negativo puede dejar la noche sin precio aunque los porcentajes sean
+		// válidos.
+		if !rate.Amount.IsPositive() {
+			return nil, fmt.Errorf("%w: rate for %s is not positive", ErrInvalidRatePlan, night.Format(domain.DateLayout))
+		}
+		qn := domain.QuoteNight{
+			Date:   night.Format(domain.DateLayout),
+			Season: season,
+			Rate:   rate,
+		}
+
+		if toCurrency != planCurrency {
+			original := rate
+			if qn.Rate, err = s.rates.Convert(ctx, rate, toCurrency, qn.Date); err != nil {
+				return nil, err
+			}
+			qn.OriginalRate = &original
+		}
+
+		quote.Nights = append(quote.Nights, qn)
+		quote.Total.Amount = quote.Total.Amount.Add(qn.Rate.Amount)
+	}
+
+	return quote, nil
+}
+
+// resolveChain devuelve el plan seguido de sus padres hasta llegar a un plan no derivado.
+func (s *service) resolveChain(ctx context.Context, rp domain.RatePlan) ([]domain.RatePlan, error) {
+	chain := []domain.RatePlan{rp}
+	visited := map[primitive.ObjectID]bool{rp.ID: true}
+
+	current := rp
+	for current.Derived != nil {
+		if len(chain) >= maxDerivationDepth {
+			return nil, fmt.Errorf("%w: derivation chain deeper than %d plans", ErrInvalidRatePlan, maxDerivationDepth)
+		}
+
+		parent, err := s.repo.GetOne(ctx, current.Derived.ParentID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: parent plan %s not found", ErrInvalidRatePlan, current.Derived.ParentID.Hex())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.FranquiciaID != rp.FranquiciaID || parent.HotelID != rp.HotelID {
+			return nil, fmt.Errorf("%w: parent plan belongs to another hotel", ErrInvalidRatePlan)
+		}
+		if visited[parent.ID] {
+			return nil, fmt.Errorf("%w: circular derivation", ErrInvalidRatePlan)
+		}
+
+		visited[parent.ID] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	planCurrency := current.BaseRate.Currency
+	for _, p := range chain[:len(chain)-1] {
+		if amount := p.Derived.Amount; !amount.IsZero() && amount.Currency != planCurrency {
+			return nil, fmt.Errorf("%w: derived amount must be in %s", ErrInvalidRatePlan, planCurrency)
+		}
+		// Un descuento de 100% o más deja la tarifa en cero o negativa.
+		if p.Derived.Percent.LessThanOrEqual(minPercent) {
+			return nil, fmt.Errorf("%w: derived percent must be greater than -100", ErrInvalidRatePlan)
+		}
+	}
+
+	return chain, nil
+}
+
+func (s *service) validate(ctx context.Context, rp domain.RatePlan) error {
+	if rp.HotelID == "" || rp.Code == "" {
+		return fmt.Errorf("%w: hotel_id and code are required", ErrInvalidRatePlan)
+	}
+	if !rp.Type.IsValid() {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidRatePlan, rp.Type)
+	}
+	if rp.MinLOS < 0 || rp.MaxLOS < 0 || (rp.MaxLOS > 0 && rp.MinLOS > rp.MaxLOS) {
+		return fmt.Errorf("%w: min_los must not exceed max_los", ErrInvalidRatePlan)
+	}
+
+	for _, adj := range rp.DayOfWeekAdjustments {
+		if adj.Weekday < time.Sunday || adj.Weekday > time.Saturday {
+			return fmt.Errorf("%w: weekday must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidRatePlan)
+		}
+		if adj.Percent.LessThanOrEqual(minPercent) {
+			return fmt.Errorf("%w: day of week percent must be greater than -100", ErrInvalidRatePlan)
+		}
+	}
+
+	planCurrency := rp.BaseRate.Currency
+	if rp.Derived != nil {
+		chain, err := s.resolveChain(ctx, rp)
+		if err != nil {
+			return err
+		}
+		planCurrency = chain[len(chain)-1].BaseRate.Currency
+	} else {
+		if !domain.IsValidCurrency(rp.BaseRate.Currency) {
+			return fmt.Errorf("%w: base_rate currency must be an ISO-4217 code", ErrInvalidRatePlan)
+		}
+		if !rp.BaseRate.Amount.IsPositive() {
+			return fmt.Errorf("%w: base_rate must be positive", ErrInvalidRatePlan)
+		}
+	}
+
+	// Un plan derivado también puede tener temporadas propias, que reemplazan la tarifa
+	// derivada; van en la moneda del plan raíz.
+	for _, season := range rp.Seasons {
+		start, err := time.Parse(domain.DateLayout, season.StartDate)
+		if err != nil {
+			return fmt.Errorf("%w: season %q start_date: %v", ErrInvalidRatePlan, season.Name, err)
+		}
+		end, err := time.Parse(domain.DateLayout, season.EndDate)
+		if err != nil {
+			return fmt.Errorf("%w: season %q end_date: %v", ErrInvalidRatePlan, season.Name, err)
+		}
+		if end.Before(start) || !season.Rate.Amount.IsPositive() {
+			return fmt.Errorf("%w: season %q has an invalid range or rate", ErrInvalidRatePlan, season.Name)
+		}
+		if season.Rate.Currency != planCurrency {
+			return fmt.Errorf("%w: season %q must be in %s", ErrInvalidRatePlan, season.Name, planCurrency)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Package scheduler corre periódicamente las tareas en segundo plano de los servicios.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Task es una tarea periódica. Si falla se registra el error y se vuelve a correr en el
+// siguiente intervalo.
+type Task func(ctx context.Context) error
+
+// Scheduler corre una tarea cada intervalo.
+type Scheduler struct {
+	name     string
+	task     Task
+	interval time.Duration
+}
+
+// New crea el scheduler. name describe la tarea en los logs, por ejemplo
+// "importar los calendarios".
+func New(name string, task Task, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		task:     task,
+		interval: interval,
+	}
+}
+
+// Run bloquea hasta que se cancela el contexto. Con un intervalo de cero la tarea queda
+// desactivada y Run vuelve enseguida.
+func (s *Scheduler) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		log.Printf("Tarea periódica desactivada: %s", s.name)
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.task(ctx); err != nil {
+				log.Printf("Error al %s: %v", s.name, err)
+			}
+		}
+	}
+}
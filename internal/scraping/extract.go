@@ -0,0 +1,183 @@
+package scraping
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/domain"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// Campos de la franquicia que puede completar una regla.
+const (
+	FieldName      = "name"
+	FieldAddress   = "address"
+	FieldCity      = "city"
+	FieldRegion    = "region"
+	FieldCountry   = "country"
+	FieldZipCode   = "zip_code"
+	FieldLatitude  = "latitude"
+	FieldLongitude = "longitude"
+	FieldTelephone = "telephone"
+	FieldEmail     = "email"
+	FieldFax       = "fax"
+)
+
+var knownFields = map[string]bool{
+	FieldName: true, FieldAddress: true, FieldCity: true, FieldRegion: true, FieldCountry: true,
+	FieldZipCode: true, FieldLatitude: true, FieldLongitude: true,
+	FieldTelephone: true, FieldEmail: true, FieldFax: true,
+}
+
+// compiledRule es una regla ya validada, lista para aplicar sobre una página.
+type compiledRule struct {
+	rule  domain.ScrapeRule
+	css   cascadia.Selector
+	xpath *xpath.Expr
+	regex *regexp.Regexp
+}
+
+// ValidateProfile compila todas las reglas del perfil y devuelve un error por cada una
+// que no sirve, para poder corregirlas de una vez.
+func ValidateProfile(profile domain.ScrapeProfile) error {
+	_, err := compileProfile(profile)
+	return err
+}
+
+func compileProfile(profile domain.ScrapeProfile) ([]compiledRule, error) {
+	var problems []string
+	// La página tiene que ser del sitio de la franquicia: no se acepta una URL absoluta
+	// ni una relativa al protocolo ("//otro-host/...").
+	if profile.Path != "" {
+		if u, err := url.Parse(profile.Path); err != nil || u.Scheme != "" || u.Host != "" {
+			problems = append(problems, fmt.Sprintf("invalid path %q: must be relative to the franchise URL", profile.Path))
+		}
+	}
+	if len(profile.Rules) == 0 {
+		problems = append(problems, "at least one rule is required")
+	}
+
+	rules := make([]compiledRule, 0, len(profile.Rules))
+	seen := map[string]bool{}
+	for i, rule := range profile.Rules {
+		c, err := compileRule(rule)
+		if err == nil && seen[rule.Field] {
+			err = errors.New("duplicated field")
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("rule %d (%s): %v", i+1, rule.Field, err))
+			continue
+		}
+		seen[rule.Field] = true
+		rules = append(rules, c)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProfile, strings.Join(problems, "; "))
+	}
+	return rules, nil
+}
+
+func compileRule(rule domain.ScrapeRule) (compiledRule, error) {
+	c := compiledRule{rule: rule}
+	if !knownFields[rule.Field] {
+		return c, fmt.Errorf("unknown field %q", rule.Field)
+	}
+	if strings.TrimSpace(rule.Selector) == "" {
+		return c, errors.New("selector is required")
+	}
+
+	var err error
+	switch rule.Type {
+	case domain.SelectorCSS, "":
+		c.rule.Type = domain.SelectorCSS
+		if c.css, err = cascadia.Compile(rule.Selector); err != nil {
+			return c, fmt.Errorf("invalid css selector %q: %w", rule.Selector, err)
+		}
+	case domain.SelectorXPath:
+		if c.xpath, err = xpath.Compile(rule.Selector); err != nil {
+			return c, fmt.Errorf("invalid xpath %q: %w", rule.Selector, err)
+		}
+	default:
+		return c, fmt.Errorf("unknown selector type %q", rule.Type)
+	}
+
+	if rule.Regex != "" {
+		if c.regex, err = regexp.Compile(rule.Regex); err != nil {
+			return c, fmt.Errorf("invalid regex %q: %w", rule.Regex, err)
+		}
+	}
+	return c, nil
+}
+
+// extract aplica las reglas al HTML. Se queda con el primer elemento que da un valor no
+// vacío y devuelve los campos sin valor en missing.
+func extract(body []byte, rules []compiledRule) (values map[string]string, missing []string, err error) {
+	root, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	doc := goquery.NewDocumentFromNode(root)
+
+	values = map[string]string{}
+	for _, r := range rules {
+		var candidates []string
+		switch r.rule.Type {
+		case domain.SelectorXPath:
+			for _, node := range htmlquery.QuerySelectorAll(root, r.xpath) {
+				candidates = append(candidates, nodeValue(node, r.rule.Attr))
+			}
+		default:
+			doc.FindMatcher(r.css).Each(func(_ int, s *goquery.Selection) {
+				if r.rule.Attr != "" {
+					candidates = append(candidates, s.AttrOr(r.rule.Attr, ""))
+					return
+				}
+				candidates = append(candidates, s.Text())
+			})
+		}
+
+		for _, candidate := range candidates {
+			if value := r.postProcess(candidate); value != "" {
+				values[r.rule.Field] = value
+				break
+			}
+		}
+		if values[r.rule.Field] == "" {
+			missing = append(missing, r.rule.Field)
+		}
+	}
+	return values, missing, nil
+}
+
+func nodeValue(node *html.Node, attr string) string {
+	if attr != "" {
+		return htmlquery.SelectAttr(node, attr)
+	}
+	return htmlquery.InnerText(node)
+}
+
+// postProcess normaliza los espacios y aplica la regex: con grupos devuelve el primero,
+// sin grupos la coincidencia completa.
+func (r compiledRule) postProcess(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if r.regex == nil || value == "" {
+		return value
+	}
+	match := r.regex.FindStringSubmatch(value)
+	switch {
+	case match == nil:
+		return ""
+	case len(match) > 1:
+		return strings.TrimSpace(match[1])
+	default:
+		return strings.TrimSpace(match[0])
+	}
+}
@@ -0,0 +1,88 @@
+package scraping
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrProfileNotFound = errors.New("scrape profile not found")
+
+type Repository interface {
+	Create(ctx context.Context, profile *domain.ScrapeProfile) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (domain.ScrapeProfile, error)
+	GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID) ([]domain.ScrapeProfile, error)
+	GetByTemplate(ctx context.Context, template string) ([]domain.ScrapeProfile, error)
+	GetTemplates(ctx context.Context) ([]domain.ScrapeProfile, error)
+}
+
+type repository struct {
+	collection *mongo.Collection
+}
+
+func NewRepository(collection *mongo.Collection) Repository {
+	return &repository{collection: collection}
+}
+
+func (r *repository) Create(ctx context.Context, profile *domain.ScrapeProfile) error {
+	_, err := r.collection.InsertOne(ctx, profile)
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrProfileNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id primitive.ObjectID) (domain.ScrapeProfile, error) {
+	var profile domain.ScrapeProfile
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&profile)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return profile, ErrProfileNotFound
+	}
+	return profile, err
+}
+
+func (r *repository) GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID) ([]domain.ScrapeProfile, error) {
+	return r.find(ctx, bson.M{"franquicia_id": franquiciaID})
+}
+
+func (r *repository) GetByTemplate(ctx context.Context, template string) ([]domain.ScrapeProfile, error) {
+	return r.find(ctx, bson.M{"template": template, "franquicia_id": bson.M{"$exists": false}})
+}
+
+// GetTemplates devuelve los perfiles que no pertenecen a ninguna franquicia.
+func (r *repository) GetTemplates(ctx context.Context) ([]domain.ScrapeProfile, error) {
+	return r.find(ctx, bson.M{"franquicia_id": bson.M{"$exists": false}})
+}
+
+func (r *repository) find(ctx context.Context, filter bson.M) ([]domain.ScrapeProfile, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var profiles []domain.ScrapeProfile
+	for cursor.Next(ctx) {
+		var profile domain.ScrapeProfile
+		if err := cursor.Decode(&profile); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, cursor.Err()
+}
@@ -0,0 +1,290 @@
+package scraping
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidProfile = errors.New("invalid scrape profile")
+	ErrNoProfile      = errors.New("no scrape profile to run")
+)
+
+// SourceScrape identifica los datos completados por perfiles de scraping.
+const SourceScrape = "scrape"
+
+// Service administra los perfiles de scraping y los corre contra el sitio de la franquicia.
+type Service interface {
+	CreateProfile(ctx context.Context, profile *domain.ScrapeProfile) error
+	GetProfiles(ctx context.Context, franquiciaID primitive.ObjectID) ([]domain.ScrapeProfile, error)
+	GetTemplates(ctx context.Context) ([]domain.ScrapeProfile, error)
+	DeleteProfile(ctx context.Context, franquiciaID *primitive.ObjectID, id primitive.ObjectID) error
+	Scrape(ctx context.Context, franquiciaID primitive.ObjectID, req domain.ScrapeRequest) (*domain.ScrapeResponse, error)
+}
+
+type service struct {
+	repo        Repository
+	franquicias franquicia.Repository
+	crawler     crawler.Factory
+}
+
+// NewService crea un nuevo servicio de scraping.
+func NewService(r Repository, franquicias franquicia.Repository, crawler crawler.Factory) Service {
+	return &service{
+		repo:        r,
+		franquicias: franquicias,
+		crawler:     crawler,
+	}
+}
+
+func (s *service) CreateProfile(ctx context.Context, profile *domain.ScrapeProfile) error {
+	if profile.FranquiciaID == nil && profile.Template == "" {
+		return fmt.Errorf("%w: template name is required", ErrInvalidProfile)
+	}
+	if err := ValidateProfile(*profile); err != nil {
+		return err
+	}
+	if profile.FranquiciaID != nil {
+		if _, err := s.franquicias.GetOne(ctx, profile.FranquiciaID.Hex()); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	profile.ID = primitive.NewObjectID()
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+	return s.repo.Create(ctx, profile)
+}
+
+func (s *service) GetProfiles(ctx context.Context, franquiciaID primitive.ObjectID) ([]domain.ScrapeProfile, error) {
+	return s.repo.GetByFranquicia(ctx, franquiciaID)
+}
+
+func (s *service) GetTemplates(ctx context.Context) ([]domain.ScrapeProfile, error) {
+	return s.repo.GetTemplates(ctx)
+}
+
+// DeleteProfile borra un perfil de la franquicia o, sin franquiciaID, una plantilla.
+func (s *service) DeleteProfile(ctx context.Context, franquiciaID *primitive.ObjectID, id primitive.ObjectID) error {
+	profile, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !sameOwner(profile.FranquiciaID, franquiciaID) {
+		return ErrProfileNotFound
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Scrape corre los perfiles elegidos por req sobre el sitio de la franquicia. Fuera del
+// modo preview guarda los valores extraídos en la franquicia.
+func (s *service) Scrape(ctx context.Context, franquiciaID primitive.ObjectID, req domain.ScrapeRequest) (*domain.ScrapeResponse, error) {
+	f, err := s.franquicias.GetOne(ctx, franquiciaID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := s.profilesFor(ctx, franquiciaID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se validan todos los perfiles antes de visitar ninguna página.
+	compiled := make([][]compiledRule, len(profiles))
+	for i, p := range profiles {
+		if compiled[i], err = compileProfile(p); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &domain.ScrapeResponse{Preview: req.Preview}
+	values := map[string]string{}
+	for i, p := range profiles {
+		result := s.run(ctx, f.URL, p, compiled[i])
+		for field, value := range result.Values {
+			if _, ok := values[field]; !ok {
+				values[field] = value
+			}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.Preview || len(values) == 0 {
+		return resp, nil
+	}
+	applyValues(&f, values)
+	if err := s.franquicias.Update(ctx, f); err != nil {
+		return nil, err
+	}
+	resp.Franquicia = &f
+	return resp, nil
+}
+
+// profilesFor resuelve qué perfiles correr según la petición.
+func (s *service) profilesFor(ctx context.Context, franquiciaID primitive.ObjectID, req domain.ScrapeRequest) ([]domain.ScrapeProfile, error) {
+	switch {
+	case req.Profile != nil:
+		return []domain.ScrapeProfile{{
+			FranquiciaID: &franquiciaID,
+			Name:         req.Profile.Name,
+			Path:         req.Profile.Path,
+			Rules:        req.Profile.Rules,
+		}}, nil
+	case req.ProfileID != "":
+		id, err := primitive.ObjectIDFromHex(req.ProfileID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid profile_id", ErrInvalidProfile)
+		}
+		profile, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// Un perfil de otra franquicia no se puede usar; una plantilla sí.
+		if profile.FranquiciaID != nil && *profile.FranquiciaID != franquiciaID {
+			return nil, ErrProfileNotFound
+		}
+		return []domain.ScrapeProfile{profile}, nil
+	case req.Template != "":
+		profiles, err := s.repo.GetByTemplate(ctx, req.Template)
+		if err != nil {
+			return nil, err
+		}
+		if len(profiles) == 0 {
+			return nil, fmt.Errorf("%w: template %q", ErrProfileNotFound, req.Template)
+		}
+		return profiles, nil
+	}
+
+	profiles, err := s.repo.GetByFranquicia(ctx, franquiciaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, ErrNoProfile
+	}
+	return profiles, nil
+}
+
+// run visita la página del perfil y aplica sus reglas. Un error de red queda en el
+// resultado para no perder lo obtenido por los demás perfiles.
+func (s *service) run(ctx context.Context, siteURL string, profile domain.ScrapeProfile, rules []compiledRule) domain.ScrapeResult {
+	result := domain.ScrapeResult{Name: profile.Name, Values: map[string]string{}}
+	if !profile.ID.IsZero() {
+		id := profile.ID
+		result.ProfileID = &id
+	}
+
+	pageURL, err := pageURL(siteURL, profile.Path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.URL = pageURL
+
+	resp, err := s.crawler.Fetch(ctx, pageURL)
+	if err != nil {
+		log.Printf("Error al visitar %s con el perfil %q: %v", pageURL, profile.Name, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	values, missing, err := extract(resp.Body, rules)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Values = values
+	result.Missing = missing
+	return result
+}
+
+// pageURL resuelve path contra la URL de la franquicia. El resultado tiene que quedar en el
+// mismo esquema y host: un perfil no puede hacer que el servidor visite otro sitio.
+func pageURL(siteURL, path string) (string, error) {
+	if !strings.HasPrefix(siteURL, "http://") && !strings.HasPrefix(siteURL, "https://") {
+		siteURL = "https://" + siteURL
+	}
+	base, err := url.Parse(siteURL)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return base.String(), nil
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	page := base.ResolveReference(ref)
+	if page.Scheme != base.Scheme || page.Host != base.Host {
+		return "", fmt.Errorf("%w: path %q leaves %s", ErrInvalidProfile, path, base.Host)
+	}
+	return page.String(), nil
+}
+
+// applyValues copia los valores extraídos a la franquicia. Las reglas son explícitas, así
+// que pisan lo que se haya obtenido automáticamente.
+func applyValues(f *domain.Franquicia, values map[string]string) {
+	location := false
+	for field, value := range values {
+		switch field {
+		case FieldName:
+			f.Name = value
+			f.NameSource = SourceScrape
+		case FieldAddress:
+			f.Location.Address, location = value, true
+		case FieldCity:
+			f.Location.City, location = value, true
+		case FieldRegion:
+			f.Location.Region, location = value, true
+		case FieldCountry:
+			f.Location.Country, location = value, true
+		case FieldZipCode:
+			f.Location.ZipCode, location = value, true
+		case FieldLatitude, FieldLongitude:
+			n, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+			if err != nil {
+				continue
+			}
+			if field == FieldLatitude {
+				f.Location.Latitude = n
+			} else {
+				f.Location.Longitude = n
+			}
+			location = true
+		case FieldTelephone, FieldEmail, FieldFax:
+			if f.Contact == nil {
+				f.Contact = &domain.Contact{}
+			}
+			switch field {
+			case FieldTelephone:
+				f.Contact.Telephone = value
+			case FieldEmail:
+				f.Contact.Email = strings.TrimPrefix(value, "mailto:")
+			case FieldFax:
+				f.Contact.Fax = value
+			}
+		}
+	}
+	if location {
+		f.LocationSource = SourceScrape
+	}
+}
+
+func sameOwner(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,453 @@
+package sitehealth
+
+import (
+	"bufio"
+	"bytes"
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// Controles del reporte.
+const (
+	CheckTitle           = "title"
+	CheckMetaDescription = "meta-description"
+	CheckCanonical       = "canonical"
+	CheckHreflang        = "hreflang"
+	CheckRobotsTxt       = "robots-txt"
+	CheckSitemap         = "sitemap"
+	CheckBrokenLinks     = "broken-links"
+	CheckHTMLLang        = "html-lang"
+	CheckImageAlt        = "image-alt"
+	CheckFormLabels      = "form-labels"
+	CheckLinkText        = "link-text"
+)
+
+// maxLinksChecked acota el crawl: solo se visitan los primeros enlaces internos de la home.
+const maxLinksChecked = 20
+
+// maxListed acota cuántos elementos se nombran en el detalle de un control.
+const maxListed = 5
+
+// Largos recomendados por los buscadores, en caracteres.
+const (
+	titleMinLength       = 10
+	titleMaxLength       = 60
+	descriptionMinLength = 50
+	descriptionMaxLength = 160
+)
+
+var hreflangPattern = regexp.MustCompile(`^(?i:x-default|[a-z]{2,3}(-[a-z]{4})?(-([a-z]{2}|[0-9]{3}))?)$`)
+
+// unlabeledInputTypes son los input que no necesitan label: no se completan o ya tienen texto.
+var unlabeledInputTypes = map[string]bool{
+	"hidden": true, "submit": true, "button": true, "reset": true, "image": true,
+}
+
+var ErrSiteUnreachable = errors.New("could not fetch the franchise homepage")
+
+// page es la home ya descargada.
+type page struct {
+	url *url.URL
+	doc *goquery.Document
+}
+
+// audit descarga la home, la analiza y hace un crawl corto de sus enlaces internos.
+func audit(ctx context.Context, factory crawler.Factory, siteURL string) (*domain.SiteHealthReport, error) {
+	if !strings.HasPrefix(siteURL, "http://") && !strings.HasPrefix(siteURL, "https://") {
+		siteURL = "https://" + siteURL
+	}
+	status, finalURL, body, err := fetch(ctx, factory, siteURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSiteUnreachable, err)
+	}
+	if status != 200 {
+		return nil, fmt.Errorf("%w: status %d", ErrSiteUnreachable, status)
+	}
+	u, err := url.Parse(finalURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSiteUnreachable, err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSiteUnreachable, err)
+	}
+	p := &page{url: u, doc: doc}
+
+	report := &domain.SiteHealthReport{URL: finalURL}
+	report.Title = strings.TrimSpace(doc.Find("head title").First().Text())
+	doc.Find("meta[name]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if !strings.EqualFold(s.AttrOr("name", ""), "description") {
+			return true
+		}
+		report.MetaDescription = strings.TrimSpace(s.AttrOr("content", ""))
+		return false
+	})
+	report.Canonical = strings.TrimSpace(doc.Find(`link[rel~="canonical"]`).First().AttrOr("href", ""))
+	report.Lang = strings.TrimSpace(doc.Find("html").First().AttrOr("lang", ""))
+	doc.Find(`link[rel~="alternate"][hreflang]`).Each(func(_ int, s *goquery.Selection) {
+		report.Hreflang = append(report.Hreflang, domain.HreflangLink{
+			Lang: strings.TrimSpace(s.AttrOr("hreflang", "")),
+			URL:  strings.TrimSpace(s.AttrOr("href", "")),
+		})
+	})
+
+	robots, sitemaps := robotsTxt(ctx, factory, p.url)
+	report.HasRobotsTxt = robots
+	report.SitemapURL = findSitemap(ctx, factory, p.url, sitemaps)
+
+	links := internalLinks(p)
+	report.BrokenLinks, report.LinksChecked = brokenLinks(ctx, factory, links)
+
+	doc.Find("img").Each(func(_ int, s *goquery.Selection) {
+		report.Images++
+		// alt="" es válido: marca una imagen decorativa.
+		if _, ok := s.Attr("alt"); ok {
+			report.ImagesWithAlt++
+		}
+	})
+	report.UnlabeledFields = unlabeledFields(doc)
+
+	report.Checks = []domain.HealthCheck{
+		checkTitle(report.Title),
+		checkMetaDescription(report.MetaDescription),
+		checkCanonical(report.Canonical),
+		checkHreflang(report.Hreflang),
+		checkPresence(CheckRobotsTxt, report.HasRobotsTxt, "", "robots.txt not found"),
+		checkPresence(CheckSitemap, report.SitemapURL != "", report.SitemapURL, "sitemap.xml not found"),
+		checkBrokenLinks(report.BrokenLinks, report.LinksChecked),
+		checkHTMLLang(report.Lang),
+		checkImageAlt(report.Images, report.ImagesWithAlt),
+		checkFormLabels(report.UnlabeledFields),
+		checkLinkText(emptyLinks(doc)),
+	}
+	report.SEOScore = categoryScore(report.Checks, domain.HealthSEO)
+	report.AccessibilityScore = categoryScore(report.Checks, domain.HealthAccessibility)
+	return report, nil
+}
+
+// fetch visita una URL y devuelve el código aun cuando es un error HTTP.
+func fetch(ctx context.Context, factory crawler.Factory, target string) (status int, finalURL string, body []byte, err error) {
+	c := factory.NewCollector(ctx)
+	c.OnResponse(func(r *colly.Response) {
+		status, finalURL, body = r.StatusCode, r.Request.URL.String(), r.Body
+	})
+	c.OnError(func(r *colly.Response, _ error) {
+		status = r.StatusCode
+	})
+	err = c.Visit(target)
+	if status >= 400 {
+		err = nil
+	}
+	return status, finalURL, body, err
+}
+
+// robotsTxt indica si el sitio publica robots.txt y devuelve los sitemaps que declara.
+func robotsTxt(ctx context.Context, factory crawler.Factory, base *url.URL) (bool, []string) {
+	status, _, body, err := fetch(ctx, factory, base.Scheme+"://"+base.Host+"/robots.txt")
+	if err != nil || status != 200 {
+		return false, nil
+	}
+	var sitemaps []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "sitemap") {
+			sitemaps = append(sitemaps, strings.TrimSpace(value))
+		}
+	}
+	return true, sitemaps
+}
+
+// findSitemap prueba los sitemaps de robots.txt y después /sitemap.xml. Devuelve la URL
+// del primero que responde con un urlset o sitemapindex.
+func findSitemap(ctx context.Context, factory crawler.Factory, base *url.URL, declared []string) string {
+	for _, candidate := range append(declared, base.Scheme+"://"+base.Host+"/sitemap.xml") {
+		status, _, body, err := fetch(ctx, factory, candidate)
+		if err != nil || status != 200 {
+			continue
+		}
+		if bytes.Contains(body, []byte("<urlset")) || bytes.Contains(body, []byte("<sitemapindex")) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// internalLinks devuelve los enlaces de la home al mismo sitio, sin repetir y sin la
+// propia home.
+func internalLinks(p *page) []string {
+	host := strings.TrimPrefix(p.url.Hostname(), "www.")
+	self := strings.TrimSuffix(p.url.String(), "/")
+	seen := map[string]bool{self: true}
+
+	var links []string
+	p.doc.Find("a[href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		ref, err := url.Parse(strings.TrimSpace(s.AttrOr("href", "")))
+		if err != nil {
+			return true
+		}
+		link := p.url.ResolveReference(ref)
+		link.Fragment = ""
+		if (link.Scheme != "http" && link.Scheme != "https") || strings.TrimPrefix(link.Hostname(), "www.") != host {
+			return true
+		}
+		key := strings.TrimSuffix(link.String(), "/")
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		links = append(links, link.String())
+		return len(links) < maxLinksChecked
+	})
+	return links
+}
+
+// brokenLinks visita los enlaces internos y devuelve los rotos y cuántos se visitaron.
+// Los que robots.txt no deja visitar no se cuentan.
+func brokenLinks(ctx context.Context, factory crawler.Factory, links []string) ([]domain.BrokenLink, int) {
+	var broken []domain.BrokenLink
+	checked := 0
+	for _, link := range links {
+		if ctx.Err() != nil {
+			break
+		}
+		status, _, _, err := fetch(ctx, factory, link)
+		if errors.Is(err, crawler.ErrDisallowedByRobots) {
+			continue
+		}
+		checked++
+		switch {
+		case err != nil:
+			broken = append(broken, domain.BrokenLink{URL: link, StatusCode: status, Error: err.Error()})
+		case status >= 400:
+			broken = append(broken, domain.BrokenLink{URL: link, StatusCode: status})
+		}
+	}
+	return broken, checked
+}
+
+// unlabeledFields lista los campos de formulario sin nombre accesible: ni label, ni
+// aria-label/aria-labelledby, ni title.
+func unlabeledFields(doc *goquery.Document) []string {
+	labeled := map[string]bool{}
+	doc.Find("label[for]").Each(func(_ int, s *goquery.Selection) {
+		labeled[s.AttrOr("for", "")] = true
+	})
+
+	var fields []string
+	doc.Find("input, select, textarea").Each(func(_ int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		inputType := strings.ToLower(s.AttrOr("type", "text"))
+		if tag == "input" && unlabeledInputTypes[inputType] {
+			return
+		}
+		if id, ok := s.Attr("id"); ok && labeled[id] {
+			return
+		}
+		for _, attr := range []string{"aria-label", "aria-labelledby", "title"} {
+			if strings.TrimSpace(s.AttrOr(attr, "")) != "" {
+				return
+			}
+		}
+		if s.Closest("label").Length() > 0 {
+			return
+		}
+		fields = append(fields, fieldName(s, tag, inputType))
+	})
+	return fields
+}
+
+func fieldName(s *goquery.Selection, tag, inputType string) string {
+	name := tag
+	if tag == "input" {
+		name += "[type=" + inputType + "]"
+	}
+	if v := s.AttrOr("name", s.AttrOr("id", "")); v != "" {
+		name += "[name=" + v + "]"
+	}
+	return name
+}
+
+// emptyLinks lista los enlaces sin texto accesible (por ejemplo un ícono sin alt).
+func emptyLinks(doc *goquery.Document) []string {
+	var links []string
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		if strings.TrimSpace(s.Text()) != "" ||
+			strings.TrimSpace(s.AttrOr("aria-label", "")) != "" ||
+			strings.TrimSpace(s.AttrOr("title", "")) != "" {
+			return
+		}
+		hasAlt := false
+		s.Find("img[alt]").Each(func(_ int, img *goquery.Selection) {
+			hasAlt = hasAlt || strings.TrimSpace(img.AttrOr("alt", "")) != ""
+		})
+		if !hasAlt {
+			links = append(links, s.AttrOr("href", ""))
+		}
+	})
+	return links
+}
+
+func categoryScore(checks []domain.HealthCheck, category string) int {
+	var total, passed int
+	for _, check := range checks {
+		if check.Category != category {
+			continue
+		}
+		total++
+		if check.Pass {
+			passed++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return passed * 100 / total
+}
+
+func listed(items []string) string {
+	if len(items) > maxListed {
+		return strings.Join(items[:maxListed], ", ") + fmt.Sprintf(" and %d more", len(items)-maxListed)
+	}
+	return strings.Join(items, ", ")
+}
+
+func checkTitle(title string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckTitle, Category: domain.HealthSEO, Value: title}
+	n := utf8.RuneCountInString(title)
+	switch {
+	case title == "":
+		check.Detail = "title missing"
+	case n < titleMinLength || n > titleMaxLength:
+		check.Detail = fmt.Sprintf("title has %d characters, recommended %d-%d", n, titleMinLength, titleMaxLength)
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+func checkMetaDescription(description string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckMetaDescription, Category: domain.HealthSEO, Value: description}
+	n := utf8.RuneCountInString(description)
+	switch {
+	case description == "":
+		check.Detail = "meta description missing"
+	case n < descriptionMinLength || n > descriptionMaxLength:
+		check.Detail = fmt.Sprintf("meta description has %d characters, recommended %d-%d", n, descriptionMinLength, descriptionMaxLength)
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+func checkCanonical(canonical string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckCanonical, Category: domain.HealthSEO, Value: canonical}
+	u, err := url.Parse(canonical)
+	switch {
+	case canonical == "":
+		check.Detail = "canonical link missing"
+	case err != nil || !u.IsAbs():
+		check.Detail = "canonical URL must be absolute"
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+// checkHreflang solo falla si las alternativas declaradas son inválidas; un sitio en un
+// único idioma no necesita hreflang.
+func checkHreflang(links []domain.HreflangLink) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckHreflang, Category: domain.HealthSEO, Pass: true}
+	if len(links) == 0 {
+		check.Detail = "no alternate languages declared"
+		return check
+	}
+	var langs, problems []string
+	for _, link := range links {
+		langs = append(langs, link.Lang)
+		if !hreflangPattern.MatchString(link.Lang) {
+			problems = append(problems, fmt.Sprintf("invalid code %q", link.Lang))
+		}
+		if u, err := url.Parse(link.URL); err != nil || !u.IsAbs() {
+			problems = append(problems, fmt.Sprintf("%s URL must be absolute", link.Lang))
+		}
+	}
+	check.Value = strings.Join(langs, ", ")
+	if len(problems) > 0 {
+		check.Pass = false
+		check.Detail = listed(problems)
+	}
+	return check
+}
+
+func checkPresence(name string, present bool, value, missing string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: name, Category: domain.HealthSEO, Pass: present, Value: value}
+	if !present {
+		check.Detail = missing
+	}
+	return check
+}
+
+func checkBrokenLinks(broken []domain.BrokenLink, checked int) domain.HealthCheck {
+	check := domain.HealthCheck{
+		Name:     CheckBrokenLinks,
+		Category: domain.HealthSEO,
+		Pass:     len(broken) == 0,
+		Value:    fmt.Sprintf("%d/%d", len(broken), checked),
+	}
+	if len(broken) > 0 {
+		urls := make([]string, len(broken))
+		for i, b := range broken {
+			urls[i] = b.URL
+		}
+		check.Detail = listed(urls)
+	}
+	return check
+}
+
+func checkHTMLLang(lang string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckHTMLLang, Category: domain.HealthAccessibility, Value: lang, Pass: lang != ""}
+	if lang == "" {
+		check.Detail = "html element has no lang attribute"
+	}
+	return check
+}
+
+func checkImageAlt(images, withAlt int) domain.HealthCheck {
+	check := domain.HealthCheck{
+		Name:     CheckImageAlt,
+		Category: domain.HealthAccessibility,
+		Pass:     images == withAlt,
+		Value:    fmt.Sprintf("%d/%d", withAlt, images),
+	}
+	if !check.Pass {
+		check.Detail = fmt.Sprintf("%d images without alt", images-withAlt)
+	}
+	return check
+}
+
+func checkFormLabels(fields []string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckFormLabels, Category: domain.HealthAccessibility, Pass: len(fields) == 0}
+	if !check.Pass {
+		check.Detail = "fields without label: " + listed(fields)
+	}
+	return check
+}
+
+func checkLinkText(links []string) domain.HealthCheck {
+	check := domain.HealthCheck{Name: CheckLinkText, Category: domain.HealthAccessibility, Pass: len(links) == 0}
+	if !check.Pass {
+		check.Detail = "links without text: " + listed(links)
+	}
+	return check
+}
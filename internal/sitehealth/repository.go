@@ -0,0 +1,65 @@
+package sitehealth
+
+import (
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository interface {
+	Create(ctx context.Context, report *domain.SiteHealthReport) error
+	// GetByFranquicia devuelve los reportes en [from, to), del más viejo al más nuevo;
+	// cualquiera de los extremos puede ser cero.
+	GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID, from, to time.Time) ([]domain.SiteHealthReport, error)
+}
+
+type repository struct {
+	db *mongo.Collection
+}
+
+func NewRepository(db *mongo.Collection) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, report *domain.SiteHealthReport) error {
+	_, err := r.db.InsertOne(ctx, report)
+	return err
+}
+
+func (r *repository) GetByFranquicia(ctx context.Context, franquiciaID primitive.ObjectID, from, to time.Time) ([]domain.SiteHealthReport, error) {
+	filter := bson.M{"franquicia_id": franquiciaID}
+	created := bson.M{}
+	if !from.IsZero() {
+		created["$gte"] = from
+	}
+	if !to.IsZero() {
+		created["$lt"] = to
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+
+	reports := []domain.SiteHealthReport{}
+	cursor, err := r.db.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var report domain.SiteHealthReport
+		if err := cursor.Decode(&report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
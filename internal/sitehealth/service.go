@@ -0,0 +1,116 @@
+package sitehealth
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"clubhub-hotel-management/internal/franquicia"
+	"clubhub-hotel-management/internal/period"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Service genera y consulta los reportes de SEO y accesibilidad de los sitios.
+type Service interface {
+	Run(ctx context.Context, franquiciaID primitive.ObjectID) (*domain.SiteHealthReport, error)
+	RunAll(ctx context.Context) error
+	GetReports(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) ([]domain.SiteHealthReport, error)
+	GetTrend(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) (*domain.SiteHealthTrend, error)
+}
+
+type service struct {
+	repo        Repository
+	franquicias franquicia.Repository
+	crawler     crawler.Factory
+}
+
+// NewService crea un nuevo servicio de reportes de salud del sitio.
+func NewService(r Repository, franquicias franquicia.Repository, crawler crawler.Factory) Service {
+	return &service{
+		repo:        r,
+		franquicias: franquicias,
+		crawler:     crawler,
+	}
+}
+
+// Run audita la home de la franquicia y guarda el reporte con la fecha de hoy.
+func (s *service) Run(ctx context.Context, franquiciaID primitive.ObjectID) (*domain.SiteHealthReport, error) {
+	f, err := s.franquicias.GetOne(ctx, franquiciaID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, f)
+}
+
+func (s *service) run(ctx context.Context, f domain.Franquicia) (*domain.SiteHealthReport, error) {
+	report, err := audit(ctx, s.crawler, f.URL)
+	if err != nil {
+		return nil, err
+	}
+	report.ID = primitive.NewObjectID()
+	report.FranquiciaID = f.ID
+	report.CreatedAt = time.Now().UTC()
+	if err := s.repo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RunAll genera el reporte de todas las franquicias. Un sitio que falla no detiene al resto.
+func (s *service) RunAll(ctx context.Context) error {
+	franquicias, err := s.franquicias.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range franquicias {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := s.run(ctx, f); err != nil {
+			log.Printf("Error al generar el reporte del sitio de %s: %v", f.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *service) GetReports(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) ([]domain.SiteHealthReport, error) {
+	start, end, err := period.Parse(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByFranquicia(ctx, franquiciaID, start, end)
+}
+
+// GetTrend resume los reportes del período en una serie y la variación entre el primero
+// y el último.
+func (s *service) GetTrend(ctx context.Context, franquiciaID primitive.ObjectID, from, to string) (*domain.SiteHealthTrend, error) {
+	reports, err := s.GetReports(ctx, franquiciaID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &domain.SiteHealthTrend{Points: []domain.SiteHealthPoint{}}
+	for _, r := range reports {
+		coverage := 100
+		if r.Images > 0 {
+			coverage = r.ImagesWithAlt * 100 / r.Images
+		}
+		trend.Points = append(trend.Points, domain.SiteHealthPoint{
+			Date:               r.CreatedAt,
+			SEOScore:           r.SEOScore,
+			AccessibilityScore: r.AccessibilityScore,
+			BrokenLinks:        len(r.BrokenLinks),
+			AltCoverage:        coverage,
+		})
+	}
+	if n := len(trend.Points); n > 1 {
+		first, last := trend.Points[0], trend.Points[n-1]
+		trend.SEOChange = last.SEOScore - first.SEOScore
+		trend.AccessibilityChange = last.AccessibilityScore - first.AccessibilityScore
+		trend.BrokenLinksChange = last.BrokenLinks - first.BrokenLinks
+	}
+	return trend, nil
+}
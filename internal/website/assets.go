@@ -0,0 +1,325 @@
+package website
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	_ "golang.org/x/image/webp"
+)
+
+var ErrNoBrandAsset = errors.New("no brand asset found")
+
+const maxAssetSize = 5 << 20
+
+// Fuentes de los candidatos a logo o favicon.
+const (
+	AssetSourceJSONLD    = "jsonld"
+	AssetSourceImg       = "img"
+	AssetSourceOGImage   = "og:image"
+	AssetSourceTouchIcon = "apple-touch-icon"
+	AssetSourceIcon      = "icon"
+	AssetSourceDefault   = "favicon.ico"
+)
+
+var logoHint = regexp.MustCompile(`(?i)logo|brand|marca`)
+
+// BrandAssets es el resultado de la extracción: el logo y el favicon ya descargados.
+type BrandAssets struct {
+	Logo    *domain.BrandAsset
+	Favicon *domain.BrandAsset
+}
+
+type assetCandidate struct {
+	URL    string
+	Source string
+	Score  int
+	Size   int
+}
+
+// ExtractBrandAssets busca el logo y el favicon del sitio, los puntúa y descarga el mejor
+// candidato de cada uno que resulte ser una imagen válida.
+func ExtractBrandAssets(ctx context.Context, factory crawler.Factory, siteURL string) (*BrandAssets, error) {
+	logos, icons, err := scrapeAssetCandidates(ctx, factory, siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := &BrandAssets{
+		Logo:    downloadBest(ctx, factory, logos),
+		Favicon: downloadBest(ctx, factory, icons),
+	}
+	if assets.Logo == nil && assets.Favicon == nil {
+		return assets, fmt.Errorf("%w: %s", ErrNoBrandAsset, siteURL)
+	}
+	return assets, nil
+}
+
+func scrapeAssetCandidates(ctx context.Context, factory crawler.Factory, siteURL string) ([]assetCandidate, []assetCandidate, error) {
+	var logos, icons []assetCandidate
+	addLogo := func(e *colly.HTMLElement, src, source string, score int) {
+		if src = strings.TrimSpace(src); src == "" || strings.HasPrefix(src, "data:") {
+			return
+		}
+		if strings.HasSuffix(strings.ToLower(strings.SplitN(src, "?", 2)[0]), ".svg") {
+			score += 5
+		}
+		logos = append(logos, assetCandidate{URL: e.Request.AbsoluteURL(src), Source: source, Score: score})
+	}
+
+	c := factory.NewCollector(ctx)
+
+	c.OnHTML(`script[type="application/ld+json"]`, func(e *colly.HTMLElement) {
+		for _, node := range jsonLDNodes(e.Text) {
+			if logo := jsonLDString(node["logo"]); logo != "" {
+				addLogo(e, logo, AssetSourceJSONLD, 100)
+			}
+		}
+	})
+	c.OnHTML("img", func(e *colly.HTMLElement) {
+		src := firstNonEmpty(e.Attr("src"), e.Attr("data-src"))
+		hints := strings.Join([]string{e.Attr("class"), e.Attr("id"), e.Attr("alt"), src}, " ")
+		inHeader := e.DOM.ParentsFiltered("header, nav, .header, #header, .navbar").Length() > 0
+		switch {
+		case logoHint.MatchString(hints) && inHeader:
+			addLogo(e, src, AssetSourceImg, 90)
+		case logoHint.MatchString(hints):
+			addLogo(e, src, AssetSourceImg, 80)
+		case e.DOM.ParentsFiltered("a").Length() > 0 && inHeader:
+			addLogo(e, src, AssetSourceImg, 45)
+		case inHeader:
+			addLogo(e, src, AssetSourceImg, 40)
+		}
+	})
+	c.OnHTML(`meta[property="og:image"], meta[property="og:logo"]`, func(e *colly.HTMLElement) {
+		score := 50
+		if e.Attr("property") == "og:logo" {
+			score = 95
+		}
+		addLogo(e, e.Attr("content"), AssetSourceOGImage, score)
+	})
+	c.OnHTML("link[rel][href]", func(e *colly.HTMLElement) {
+		rel := strings.ToLower(e.Attr("rel"))
+		href := e.Request.AbsoluteURL(e.Attr("href"))
+		size := iconSize(e.Attr("sizes"))
+		switch {
+		case strings.Contains(rel, "apple-touch-icon"):
+			icons = append(icons, assetCandidate{URL: href, Source: AssetSourceTouchIcon, Score: 60, Size: size})
+			logos = append(logos, assetCandidate{URL: href, Source: AssetSourceTouchIcon, Score: 30})
+		case strings.Contains(rel, "icon"):
+			icons = append(icons, assetCandidate{URL: href, Source: AssetSourceIcon, Score: 70, Size: size})
+		}
+	})
+
+	var pageURL string
+	c.OnResponse(func(r *colly.Response) {
+		pageURL = r.Request.URL.String()
+	})
+
+	if err := c.Visit(ensureScheme(siteURL)); err != nil {
+		return nil, nil, err
+	}
+
+	if pageURL != "" {
+		icons = append(icons, assetCandidate{URL: resolveRef(pageURL, "/favicon.ico"), Source: AssetSourceDefault, Score: 10})
+	}
+
+	// Entre íconos del mismo tipo gana el más grande (hasta 256px, más no aporta).
+	for i := range icons {
+		icons[i].Score += min(icons[i].Size, 256) / 16
+	}
+	return rankAssets(logos), rankAssets(icons), nil
+}
+
+func rankAssets(candidates []assetCandidate) []assetCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	ranked := candidates[:0]
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if seen[c.URL] {
+			continue
+		}
+		seen[c.URL] = true
+		ranked = append(ranked, c)
+	}
+	return ranked
+}
+
+// downloadBest descarga los candidatos en orden y devuelve el primero que es una imagen.
+func downloadBest(ctx context.Context, factory crawler.Factory, candidates []assetCandidate) *domain.BrandAsset {
+	for _, c := range candidates {
+		asset, err := DownloadAsset(ctx, factory, c.URL)
+		if err != nil {
+			log.Printf("Candidato %s descartado (%s): %v", c.URL, c.Source, err)
+			continue
+		}
+		asset.Source = c.Source
+		asset.Score = c.Score
+		return asset
+	}
+	return nil
+}
+
+// DownloadAsset descarga una imagen, detecta su tipo por contenido y lee sus dimensiones.
+func DownloadAsset(ctx context.Context, factory crawler.Factory, assetURL string) (*domain.BrandAsset, error) {
+	resp, err := factory.Fetch(ctx, assetURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data := resp.Body
+	if len(data) > maxAssetSize {
+		return nil, fmt.Errorf("asset larger than %d bytes", maxAssetSize)
+	}
+
+	contentType, width, height, err := InspectImage(data)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BrandAsset{
+		URL:         assetURL,
+		ContentType: contentType,
+		Width:       width,
+		Height:      height,
+		Size:        len(data),
+		Data:        data,
+	}, nil
+}
+
+// InspectImage detecta el tipo real de la imagen (sin confiar en el Content-Type) y sus dimensiones.
+func InspectImage(data []byte) (string, int, int, error) {
+	if len(data) >= 4 && bytes.Equal(data[:4], []byte{0, 0, 1, 0}) {
+		w, h, err := icoSize(data)
+		return "image/x-icon", w, h, err
+	}
+	if isSVG(data) {
+		w, h := svgSize(data)
+		return "image/svg+xml", w, h, nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("not a supported image: %w", err)
+	}
+	return "image/" + format, cfg.Width, cfg.Height, nil
+}
+
+// icoSize devuelve la entrada más grande de un .ico (0 en el encabezado significa 256).
+func icoSize(data []byte) (int, int, error) {
+	if len(data) < 6 {
+		return 0, 0, errors.New("truncated ico")
+	}
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	width, height := 0, 0
+	for i := 0; i < count && 6+16*(i+1) <= len(data); i++ {
+		entry := data[6+16*i:]
+		w, h := int(entry[0]), int(entry[1])
+		if w == 0 {
+			w = 256
+		}
+		if h == 0 {
+			h = 256
+		}
+		if w*h > width*height {
+			width, height = w, h
+		}
+	}
+	if width == 0 {
+		return 0, 0, errors.New("ico without images")
+	}
+	return width, height, nil
+}
+
+func isSVG(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return bytes.Contains(bytes.ToLower(head), []byte("<svg"))
+}
+
+// svgSize lee width/height del elemento raíz o, si faltan, el viewBox.
+func svgSize(data []byte) (int, int) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "svg" {
+			continue
+		}
+		var width, height, viewBox string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = attr.Value
+			case "height":
+				height = attr.Value
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+		w, h := svgLength(width), svgLength(height)
+		if w == 0 || h == 0 {
+			if fields := strings.Fields(strings.ReplaceAll(viewBox, ",", " ")); len(fields) == 4 {
+				w, h = svgLength(fields[2]), svgLength(fields[3])
+			}
+		}
+		return w, h
+	}
+}
+
+func svgLength(v string) int {
+	v = strings.TrimSuffix(strings.TrimSpace(v), "px")
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0
+	}
+	return int(f + 0.5)
+}
+
+// iconSize interpreta el atributo sizes ("32x32", "16x16 32x32", "any").
+func iconSize(sizes string) int {
+	best := 0
+	for _, s := range strings.Fields(strings.ToLower(sizes)) {
+		if s == "any" {
+			return 256
+		}
+		w, _, ok := strings.Cut(s, "x")
+		if n, err := strconv.Atoi(w); ok && err == nil && n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,197 @@
+package website
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/crawler"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testImages son las imágenes que sirve el sitio de prueba, con su tamaño.
+var testImages = map[string]int{
+	"/images/logo.png":      200,
+	"/images/og.png":        1200,
+	"/images/pool.png":      800,
+	"/brand/costa.png":      300,
+	"/icons/favicon-16.png": 16,
+	"/icons/favicon-64.png": 64,
+	"/icons/touch.png":      180,
+}
+
+// newAssetSite sirve las páginas de testdata y las imágenes de testImages. Con favicon,
+// también responde /favicon.ico.
+func newAssetSite(t *testing.T, favicon bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".html"):
+			http.ServeFile(w, r, filepath.Join("testdata", filepath.Base(r.URL.Path)))
+		case r.URL.Path == "/favicon.ico" && favicon:
+			w.Header().Set("Content-Type", "image/x-icon")
+			w.Write(testICO(32))
+		case testImages[r.URL.Path] > 0:
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(testPNG(t, testImages[r.URL.Path]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testPNG(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testICO arma un .ico con una sola entrada del tamaño pedido.
+func testICO(size int) []byte {
+	ico := []byte{0, 0, 1, 0, 1, 0}
+	entry := make([]byte, 16)
+	entry[0], entry[1] = byte(size), byte(size)
+	return append(ico, entry...)
+}
+
+func testFactory() crawler.Factory {
+	cfg := crawler.DefaultConfig()
+	cfg.Delay = 0
+	cfg.Retries = 0
+	cfg.IgnoreRobots = true
+	return crawler.NewFactory(cfg)
+}
+
+func TestExtractBrandAssets(t *testing.T) {
+	tests := []struct {
+		name          string
+		page          string
+		favicon       bool
+		wantLogo      string
+		wantLogoFrom  string
+		wantFavicon   string
+		wantIconFrom  string
+		wantIconWidth int
+		wantErr       error
+	}{
+		{
+			name: "logo in header and largest icon", page: "header-logo.html",
+			wantLogo: "/images/logo.png", wantLogoFrom: AssetSourceImg,
+			wantFavicon: "/icons/favicon-64.png", wantIconFrom: AssetSourceIcon, wantIconWidth: 64,
+		},
+		{
+			name: "json-ld logo wins", page: "jsonld-logo.html", favicon: true,
+			wantLogo: "/brand/costa.png", wantLogoFrom: AssetSourceJSONLD,
+			wantFavicon: "/favicon.ico", wantIconFrom: AssetSourceDefault, wantIconWidth: 32,
+		},
+		{
+			name: "broken candidate falls back", page: "broken-logo.html",
+			wantLogo: "/images/og.png", wantLogoFrom: AssetSourceOGImage,
+		},
+		{
+			name: "only the default favicon", page: "no-assets.html", favicon: true,
+			wantFavicon: "/favicon.ico", wantIconFrom: AssetSourceDefault, wantIconWidth: 32,
+		},
+		{name: "nothing found", page: "no-assets.html", wantErr: ErrNoBrandAsset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newAssetSite(t, tt.favicon)
+			assets, err := ExtractBrandAssets(context.Background(), testFactory(), srv.URL+"/"+tt.page)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractBrandAssets: %v", err)
+			}
+
+			if tt.wantLogo == "" {
+				if assets.Logo != nil {
+					t.Errorf("Logo = %s, want none", assets.Logo.URL)
+				}
+			} else if assets.Logo == nil {
+				t.Errorf("Logo = nil, want %s", tt.wantLogo)
+			} else {
+				if assets.Logo.URL != srv.URL+tt.wantLogo || assets.Logo.Source != tt.wantLogoFrom {
+					t.Errorf("Logo = %s (%s), want %s (%s)", assets.Logo.URL, assets.Logo.Source, tt.wantLogo, tt.wantLogoFrom)
+				}
+				if assets.Logo.ContentType != "image/png" || assets.Logo.Width != testImages[tt.wantLogo] {
+					t.Errorf("Logo is %s %dpx", assets.Logo.ContentType, assets.Logo.Width)
+				}
+			}
+
+			if tt.wantFavicon == "" {
+				if assets.Favicon != nil {
+					t.Errorf("Favicon = %s, want none", assets.Favicon.URL)
+				}
+			} else if assets.Favicon == nil {
+				t.Errorf("Favicon = nil, want %s", tt.wantFavicon)
+			} else if assets.Favicon.URL != srv.URL+tt.wantFavicon || assets.Favicon.Source != tt.wantIconFrom || assets.Favicon.Width != tt.wantIconWidth {
+				t.Errorf("Favicon = %s (%s, %dpx), want %s (%s, %dpx)", assets.Favicon.URL, assets.Favicon.Source, assets.Favicon.Width,
+					tt.wantFavicon, tt.wantIconFrom, tt.wantIconWidth)
+			}
+		})
+	}
+}
+
+func TestInspectImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		wantType   string
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{name: "png", data: testPNG(t, 48), wantType: "image/png", wantWidth: 48, wantHeight: 48},
+		{name: "ico 256", data: testICO(0), wantType: "image/x-icon", wantWidth: 256, wantHeight: 256},
+		{name: "svg size", data: []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="120px" height="40"></svg>`), wantType: "image/svg+xml", wantWidth: 120, wantHeight: 40},
+		{name: "svg viewBox", data: []byte(`<?xml version="1.0"?><svg viewBox="0 0 300 100"></svg>`), wantType: "image/svg+xml", wantWidth: 300, wantHeight: 100},
+		{name: "html", data: []byte(`<!DOCTYPE html><html></html>`), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, w, h, err := InspectImage(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("InspectImage = %s, want error", contentType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InspectImage: %v", err)
+			}
+			if contentType != tt.wantType || w != tt.wantWidth || h != tt.wantHeight {
+				t.Errorf("got %s %dx%d, want %s %dx%d", contentType, w, h, tt.wantType, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestIconSize(t *testing.T) {
+	tests := map[string]int{
+		"":            0,
+		"32x32":       32,
+		"16x16 48x48": 48,
+		"any":         256,
+		"bogus":       0,
+	}
+	for sizes, want := range tests {
+		if got := iconSize(sizes); got != want {
+			t.Errorf("iconSize(%q) = %d, want %d", sizes, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package website
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jsonLDNodes devuelve todos los objetos de un bloque JSON-LD, incluidos los de @graph
+// y los anidados, para poder buscarlos por @type.
+func jsonLDNodes(raw string) []map[string]interface{} {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &doc); err != nil {
+		return nil
+	}
+
+	var nodes []map[string]interface{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case []interface{}:
+			for _, item := range t {
+				walk(item)
+			}
+		case map[string]interface{}:
+			nodes = append(nodes, t)
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	return nodes
+}
+
+// jsonLDTypes devuelve los @type de un nodo sin prefijo de vocabulario.
+func jsonLDTypes(node map[string]interface{}) []string {
+	var types []string
+	switch t := node["@type"].(type) {
+	case string:
+		types = append(types, t)
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	for i, t := range types {
+		types[i] = strings.TrimPrefix(strings.TrimPrefix(t, "http://schema.org/"), "https://schema.org/")
+	}
+	return types
+}
+
+// jsonLDString lee una propiedad de texto; acepta strings, listas y objetos con @value, name o url.
+func jsonLDString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case []interface{}:
+		for _, item := range t {
+			if s := jsonLDString(item); s != "" {
+				return s
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"@value", "url", "contentUrl", "name", "@id"} {
+			if s, ok := t[key].(string); ok && s != "" {
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package website
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"encoding/json"
+	"html"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Fuentes del nombre de una franquicia, de la más a la menos confiable.
+const (
+	NameSourceRequest  = "request"
+	NameSourceJSONLD   = "jsonld"
+	NameSourceOGSite   = "og:site_name"
+	NameSourceManifest = "manifest"
+	NameSourceAppName  = "application-name"
+	NameSourceTitle    = "title"
+	NameSourceWhois    = "whois"
+	NameSourceDomain   = "domain"
+)
+
+var nameSourceScores = map[string]int{
+	NameSourceJSONLD:   100,
+	NameSourceOGSite:   90,
+	NameSourceManifest: 80,
+	NameSourceAppName:  75,
+	NameSourceTitle:    50,
+	NameSourceWhois:    10,
+	NameSourceDomain:   1,
+}
+
+// agreementBonus se suma por cada otra fuente que propone el mismo nombre.
+const agreementBonus = 15
+
+// lodgingTypes son los @type de schema.org que identifican al negocio.
+var lodgingTypes = map[string]int{
+	"Hotel":           10,
+	"LodgingBusiness": 10,
+	"Resort":          10,
+	"Motel":           10,
+	"Hostel":          10,
+	"BedAndBreakfast": 10,
+	"Organization":    5,
+	"Corporation":     5,
+	"LocalBusiness":   5,
+	"Brand":           5,
+	"WebSite":         0,
+}
+
+var (
+	titleSeparators = regexp.MustCompile(`\s+[|\-–—·:•»]\s+`)
+	// genericNames son segmentos de título o nombres que no identifican a la franquicia.
+	genericNames = regexp.MustCompile(`(?i)^(home|inicio|index|welcome|bienvenid[oa]s?|official (web)?site|sitio oficial|página principal|homepage|book now|reservas?|hotel|hotels|hoteles)$`)
+	// redactedNames son valores que publican los registros en lugar del titular.
+	redactedNames = regexp.MustCompile(`(?i)redacted|privacy|private|proxy|protected|not disclosed|gdpr|withheld|data protected|registrant|domains by`)
+)
+
+// ResolveName agrega a los candidatos del sitio el nombre de WHOIS (si no está oculto) y
+// el dominio como últimos recursos, y devuelve todos ordenados por puntaje.
+func ResolveName(scraped []domain.NameCandidate, siteURL, whoisName string) []domain.NameCandidate {
+	candidates := append([]domain.NameCandidate{}, scraped...)
+	if whoisName != "" && !redactedNames.MatchString(whoisName) {
+		candidates = append(candidates, domain.NameCandidate{Name: whoisName, Source: NameSourceWhois})
+	}
+	if host := hostName(siteURL); host != "" {
+		candidates = append(candidates, domain.NameCandidate{Name: host, Source: NameSourceDomain})
+	}
+	return RankNames(candidates)
+}
+
+// RankNames limpia, puntúa y ordena los candidatos; el mejor queda primero.
+func RankNames(candidates []domain.NameCandidate) []domain.NameCandidate {
+	var cleaned []domain.NameCandidate
+	for _, c := range candidates {
+		c.Name = cleanName(c.Name)
+		if c.Name == "" || len(c.Name) > 80 || genericNames.MatchString(c.Name) {
+			continue
+		}
+		c.Score += nameSourceScores[c.Source]
+		cleaned = append(cleaned, c)
+	}
+
+	for i := range cleaned {
+		seen := map[string]bool{cleaned[i].Source: true}
+		for j := range cleaned {
+			if seen[cleaned[j].Source] || !strings.EqualFold(cleaned[i].Name, cleaned[j].Name) {
+				continue
+			}
+			seen[cleaned[j].Source] = true
+			cleaned[i].Score += agreementBonus
+		}
+	}
+
+	sort.SliceStable(cleaned, func(i, j int) bool {
+		return cleaned[i].Score > cleaned[j].Score
+	})
+
+	// Un mismo nombre puede venir de varias fuentes: se deja el de mayor puntaje.
+	ranked := make([]domain.NameCandidate, 0, len(cleaned))
+	seen := map[string]bool{}
+	for _, c := range cleaned {
+		key := strings.ToLower(c.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ranked = append(ranked, c)
+	}
+	return ranked
+}
+
+// ScrapeNames junta los nombres candidatos del sitio: JSON-LD Organization/Hotel,
+// og:site_name, manifest, application-name y <title>.
+func ScrapeNames(ctx context.Context, factory crawler.Factory, siteURL string) []domain.NameCandidate {
+	var candidates []domain.NameCandidate
+	add := func(name, source string, bonus int) {
+		candidates = append(candidates, domain.NameCandidate{Name: name, Source: source, Score: bonus})
+	}
+
+	c := factory.NewCollector(ctx)
+
+	var manifestURL string
+	c.OnHTML("title", func(e *colly.HTMLElement) {
+		title := strings.TrimSpace(e.Text)
+		// "Inicio | Hotel Sol - Buenos Aires": cada parte es un candidato y el título
+		// completo queda apenas por debajo.
+		parts := titleSeparators.Split(title, -1)
+		if len(parts) == 1 {
+			add(title, NameSourceTitle, 0)
+			return
+		}
+		add(title, NameSourceTitle, -5)
+		for _, part := range parts {
+			add(part, NameSourceTitle, 0)
+		}
+	})
+	c.OnHTML(`meta[property="og:site_name"]`, func(e *colly.HTMLElement) {
+		add(e.Attr("content"), NameSourceOGSite, 0)
+	})
+	c.OnHTML(`meta[name="application-name"], meta[name="apple-mobile-web-app-title"]`, func(e *colly.HTMLElement) {
+		add(e.Attr("content"), NameSourceAppName, 0)
+	})
+	c.OnHTML(`script[type="application/ld+json"]`, func(e *colly.HTMLElement) {
+		for _, node := range jsonLDNodes(e.Text) {
+			for _, t := range jsonLDTypes(node) {
+				bonus, ok := lodgingTypes[t]
+				if !ok {
+					continue
+				}
+				add(jsonLDString(node["name"]), NameSourceJSONLD, bonus)
+				break
+			}
+		}
+	})
+	c.OnHTML(`link[rel="manifest"]`, func(e *colly.HTMLElement) {
+		manifestURL = e.Request.AbsoluteURL(e.Attr("href"))
+	})
+
+	if err := c.Visit(ensureScheme(siteURL)); err != nil {
+		log.Printf("Error al buscar el nombre en %s: %v", siteURL, err)
+		return candidates
+	}
+
+	if manifestURL != "" {
+		name, shortName := fetchManifest(ctx, factory, manifestURL)
+		add(name, NameSourceManifest, 0)
+		add(shortName, NameSourceManifest, -10)
+	}
+	return candidates
+}
+
+func fetchManifest(ctx context.Context, factory crawler.Factory, manifestURL string) (string, string) {
+	resp, err := factory.Fetch(ctx, manifestURL)
+	if err != nil {
+		log.Printf("Error al leer el manifest %s: %v", manifestURL, err)
+		return "", ""
+	}
+
+	var manifest struct {
+		Name      string `json:"name"`
+		ShortName string `json:"short_name"`
+	}
+	if json.Unmarshal(resp.Body, &manifest) != nil {
+		return "", ""
+	}
+	return manifest.Name, manifest.ShortName
+}
+
+func cleanName(name string) string {
+	name = html.UnescapeString(name)
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,410 @@
+package website
+
+import (
+	"bytes"
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// Controles de la auditoría de cabeceras de seguridad.
+const (
+	CheckHSTS               = "strict-transport-security"
+	CheckHSTSPreload        = "hsts-preload"
+	CheckCSP                = "content-security-policy"
+	CheckFrameOptions       = "x-frame-options"
+	CheckContentTypeOptions = "x-content-type-options"
+	CheckReferrerPolicy     = "referrer-policy"
+	CheckPermissionsPolicy  = "permissions-policy"
+	CheckCookies            = "cookies"
+	CheckMixedContent       = "mixed-content"
+)
+
+// securityWeights es cuánto suma cada control al puntaje; se normaliza a 100.
+var securityWeights = map[string]int{
+	CheckHSTS:               20,
+	CheckHSTSPreload:        5,
+	CheckCSP:                20,
+	CheckFrameOptions:       10,
+	CheckContentTypeOptions: 10,
+	CheckReferrerPolicy:     10,
+	CheckPermissionsPolicy:  5,
+	CheckCookies:            10,
+	CheckMixedContent:       10,
+}
+
+// hstsMinMaxAge es el max-age mínimo de HSTS, el mismo que pide la lista de preload.
+const hstsMinMaxAge = 31536000
+
+// maxMixedContent acota cuántos recursos inseguros se guardan en el reporte.
+const maxMixedContent = 20
+
+// mixedContentAttrs son los atributos que cargan recursos desde la página.
+var mixedContentAttrs = []struct{ selector, attr string }{
+	{"script[src]", "src"},
+	{"img[src]", "src"},
+	{"iframe[src]", "src"},
+	{"frame[src]", "src"},
+	{"audio[src]", "src"},
+	{"video[src]", "src"},
+	{"source[src]", "src"},
+	{"embed[src]", "src"},
+	{"object[data]", "data"},
+	{`link[rel~="stylesheet"][href]`, "href"},
+	{`link[rel~="icon"][href]`, "href"},
+	{"form[action]", "action"},
+}
+
+var ErrNoSecurityAudit = errors.New("could not fetch the homepage for the security audit")
+
+// AuditSecurityHeaders descarga la home del sitio y evalúa sus cabeceras de seguridad,
+// las cookies que setea y, si se sirve por HTTPS, los recursos cargados por HTTP.
+func AuditSecurityHeaders(ctx context.Context, factory crawler.Factory, siteURL string) (*domain.SecurityAudit, error) {
+	var (
+		finalURL string
+		header   http.Header
+		body     []byte
+	)
+
+	c := factory.NewCollector(ctx)
+	c.OnResponse(func(r *colly.Response) {
+		finalURL = r.Request.URL.String()
+		if r.Headers != nil {
+			header = *r.Headers
+		}
+		body = r.Body
+	})
+	if err := c.Visit(ensureScheme(siteURL)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoSecurityAudit, err)
+	}
+	if header == nil {
+		return nil, ErrNoSecurityAudit
+	}
+
+	https := strings.HasPrefix(finalURL, "https://")
+	audit := &domain.SecurityAudit{URL: finalURL, CheckedAt: time.Now().UTC()}
+	audit.MixedContent = mixedContent(body, https)
+	audit.Checks = []domain.SecurityCheck{
+		checkHSTS(header, https),
+		checkHSTSPreload(header, https),
+		checkCSP(header),
+		checkFrameOptions(header),
+		checkContentTypeOptions(header),
+		checkReferrerPolicy(header),
+		checkPermissionsPolicy(header),
+		checkCookies(header, https),
+		checkMixedContent(audit.MixedContent, https),
+	}
+	audit.Score, audit.Grade = securityScore(audit.Checks)
+	return audit, nil
+}
+
+func securityScore(checks []domain.SecurityCheck) (int, string) {
+	var total, passed int
+	for _, check := range checks {
+		total += securityWeights[check.Name]
+		if check.Pass {
+			passed += securityWeights[check.Name]
+		}
+	}
+	if total == 0 {
+		return 0, "F"
+	}
+	score := passed * 100 / total
+	return score, domain.SecurityGrade(score)
+}
+
+// hstsDirectives separa la cabecera HSTS en directivas en minúscula y su max-age.
+func hstsDirectives(value string) (directives map[string]bool, maxAge int, ok bool) {
+	directives = map[string]bool{}
+	maxAge = -1
+	for _, part := range strings.Split(value, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		directives[name] = true
+		if name == "max-age" {
+			n, err := strconv.Atoi(strings.Trim(strings.TrimSpace(arg), `"`))
+			if err != nil {
+				return directives, -1, false
+			}
+			maxAge = n
+		}
+	}
+	return directives, maxAge, maxAge >= 0
+}
+
+func checkHSTS(header http.Header, https bool) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckHSTS, Value: header.Get("Strict-Transport-Security")}
+	_, maxAge, ok := hstsDirectives(check.Value)
+	switch {
+	case !https:
+		check.Detail = "page is not served over HTTPS"
+	case check.Value == "":
+		check.Detail = "header missing"
+	case !ok:
+		check.Detail = "max-age missing or invalid"
+	case maxAge < hstsMinMaxAge:
+		check.Detail = fmt.Sprintf("max-age %d is lower than %d", maxAge, hstsMinMaxAge)
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+// checkHSTSPreload revisa los requisitos de la lista de preload de los navegadores.
+func checkHSTSPreload(header http.Header, https bool) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckHSTSPreload, Value: header.Get("Strict-Transport-Security")}
+	directives, maxAge, _ := hstsDirectives(check.Value)
+	switch {
+	case !https || check.Value == "":
+		check.Detail = "HSTS is not enabled"
+	case !directives["preload"]:
+		check.Detail = "preload directive missing"
+	case !directives["includesubdomains"]:
+		check.Detail = "includeSubDomains directive missing"
+	case maxAge < hstsMinMaxAge:
+		check.Detail = fmt.Sprintf("max-age %d is lower than %d", maxAge, hstsMinMaxAge)
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+// cspDirectives separa una política CSP en directivas y sus fuentes.
+func cspDirectives(value string) map[string][]string {
+	directives := map[string][]string{}
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, ok := directives[name]; !ok {
+			directives[name] = fields[1:]
+		}
+	}
+	return directives
+}
+
+// checkCSP aprueba una política que restringe los scripts: sin comodines y sin
+// 'unsafe-inline' salvo que lo neutralice un nonce o un hash.
+func checkCSP(header http.Header) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckCSP, Value: header.Get("Content-Security-Policy")}
+	if check.Value == "" {
+		if header.Get("Content-Security-Policy-Report-Only") != "" {
+			check.Detail = "policy is report-only"
+		} else {
+			check.Detail = "header missing"
+		}
+		return check
+	}
+
+	directives := cspDirectives(check.Value)
+	sources, ok := directives["script-src"]
+	if !ok {
+		sources, ok = directives["default-src"]
+	}
+	if !ok {
+		check.Detail = "neither script-src nor default-src is defined"
+		return check
+	}
+
+	var problems []string
+	unsafeInline, nonceOrHash := false, false
+	for _, source := range sources {
+		lower := strings.ToLower(source)
+		switch {
+		case lower == "*", lower == "http:", lower == "https:", lower == "data:":
+			problems = append(problems, "allows scripts from "+source)
+		case lower == "'unsafe-inline'":
+			unsafeInline = true
+		case lower == "'unsafe-eval'":
+			problems = append(problems, "allows 'unsafe-eval'")
+		case strings.HasPrefix(lower, "'nonce-"), strings.HasPrefix(lower, "'sha256-"),
+			strings.HasPrefix(lower, "'sha384-"), strings.HasPrefix(lower, "'sha512-"):
+			nonceOrHash = true
+		}
+	}
+	if unsafeInline && !nonceOrHash {
+		problems = append(problems, "allows 'unsafe-inline'")
+	}
+	if len(problems) > 0 {
+		check.Detail = strings.Join(problems, "; ")
+		return check
+	}
+	check.Pass = true
+	return check
+}
+
+// checkFrameOptions acepta X-Frame-Options o la directiva frame-ancestors de CSP, que la
+// reemplaza en los navegadores actuales.
+func checkFrameOptions(header http.Header) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckFrameOptions, Value: header.Get("X-Frame-Options")}
+	switch strings.ToUpper(strings.TrimSpace(check.Value)) {
+	case "DENY", "SAMEORIGIN":
+		check.Pass = true
+		return check
+	}
+	if ancestors, ok := cspDirectives(header.Get("Content-Security-Policy"))["frame-ancestors"]; ok {
+		for _, source := range ancestors {
+			if source == "*" {
+				check.Detail = "frame-ancestors allows any origin"
+				return check
+			}
+		}
+		check.Pass = true
+		return check
+	}
+	if check.Value == "" {
+		check.Detail = "header missing"
+	} else {
+		check.Detail = "value must be DENY or SAMEORIGIN"
+	}
+	return check
+}
+
+func checkContentTypeOptions(header http.Header) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckContentTypeOptions, Value: header.Get("X-Content-Type-Options")}
+	switch {
+	case check.Value == "":
+		check.Detail = "header missing"
+	case !strings.EqualFold(strings.TrimSpace(check.Value), "nosniff"):
+		check.Detail = "value must be nosniff"
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+// checkReferrerPolicy rechaza las políticas que envían la URL completa a otros sitios o
+// por HTTP. Con varias políticas el navegador usa la última que reconoce.
+func checkReferrerPolicy(header http.Header) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckReferrerPolicy, Value: header.Get("Referrer-Policy")}
+	if check.Value == "" {
+		check.Detail = "header missing"
+		return check
+	}
+
+	policy := ""
+	for _, token := range strings.Split(check.Value, ",") {
+		switch token = strings.ToLower(strings.TrimSpace(token)); token {
+		case "no-referrer", "same-origin", "origin", "strict-origin", "strict-origin-when-cross-origin",
+			"origin-when-cross-origin", "no-referrer-when-downgrade", "unsafe-url":
+			policy = token
+		}
+	}
+	switch policy {
+	case "":
+		check.Detail = "unknown policy"
+	case "unsafe-url", "no-referrer-when-downgrade", "origin-when-cross-origin":
+		check.Detail = fmt.Sprintf("%s leaks the full URL", policy)
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+func checkPermissionsPolicy(header http.Header) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckPermissionsPolicy, Value: header.Get("Permissions-Policy")}
+	switch {
+	case check.Value != "":
+		check.Pass = true
+	case header.Get("Feature-Policy") != "":
+		check.Value = header.Get("Feature-Policy")
+		check.Detail = "only the deprecated Feature-Policy header is set"
+	default:
+		check.Detail = "header missing"
+	}
+	return check
+}
+
+// checkCookies exige Secure (en HTTPS), HttpOnly y SameSite en todas las cookies de la home.
+func checkCookies(header http.Header, https bool) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckCookies}
+	cookies := (&http.Response{Header: header}).Cookies()
+	if len(cookies) == 0 {
+		check.Pass = true
+		check.Detail = "no cookies set"
+		return check
+	}
+
+	names := make([]string, 0, len(cookies))
+	var problems []string
+	for _, cookie := range cookies {
+		names = append(names, cookie.Name)
+		var missing []string
+		if https && !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+		if !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+		// Sin el atributo queda en cero; con "SameSite=" vacío, en SameSiteDefaultMode.
+		if cookie.SameSite == 0 || cookie.SameSite == http.SameSiteDefaultMode {
+			missing = append(missing, "SameSite")
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("%s without %s", cookie.Name, strings.Join(missing, ", ")))
+		}
+	}
+	check.Value = strings.Join(names, ", ")
+	if !https {
+		problems = append([]string{"cookies are sent over plain HTTP"}, problems...)
+	}
+	if len(problems) > 0 {
+		check.Detail = strings.Join(problems, "; ")
+		return check
+	}
+	check.Pass = true
+	return check
+}
+
+func checkMixedContent(resources []string, https bool) domain.SecurityCheck {
+	check := domain.SecurityCheck{Name: CheckMixedContent}
+	switch {
+	case !https:
+		check.Detail = "page is not served over HTTPS"
+	case len(resources) > 0:
+		check.Detail = fmt.Sprintf("%d resources loaded over HTTP", len(resources))
+	default:
+		check.Pass = true
+	}
+	return check
+}
+
+// mixedContent lista los recursos que una página HTTPS carga por HTTP.
+func mixedContent(body []byte, https bool) []string {
+	if !https || len(body) == 0 {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var found []string
+	seen := map[string]bool{}
+	for _, a := range mixedContentAttrs {
+		doc.Find(a.selector).Each(func(_ int, s *goquery.Selection) {
+			ref := strings.TrimSpace(s.AttrOr(a.attr, ""))
+			if len(found) >= maxMixedContent || seen[ref] || !strings.HasPrefix(strings.ToLower(ref), "http://") {
+				return
+			}
+			seen[ref] = true
+			found = append(found, ref)
+		})
+	}
+	return found
+}
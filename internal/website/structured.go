@@ -0,0 +1,381 @@
+package website
+
+import (
+	"clubhub-hotel-management/internal/crawler"
+	"clubhub-hotel-management/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// Formatos de marcado schema.org de los que se extraen los datos del hotel.
+const (
+	MarkupJSONLD    = "jsonld"
+	MarkupMicrodata = "microdata"
+	MarkupRDFa      = "rdfa"
+)
+
+var ErrNoHotelData = errors.New("no schema.org hotel markup found")
+
+// hotelDataTypes puntúa los @type que pueden describir al hotel: los de alojamiento
+// primero y los genéricos de negocio después.
+var hotelDataTypes = map[string]int{
+	"Hotel":           10,
+	"LodgingBusiness": 10,
+	"Resort":          10,
+	"Motel":           10,
+	"Hostel":          10,
+	"BedAndBreakfast": 10,
+	"Campground":      8,
+	"LocalBusiness":   6,
+	"Organization":    4,
+	"Corporation":     4,
+}
+
+var markupScores = map[string]int{
+	MarkupJSONLD:    2,
+	MarkupMicrodata: 1,
+	MarkupRDFa:      0,
+}
+
+// HotelData son los datos estructurados del hotel encontrados en el sitio.
+type HotelData struct {
+	Source   string
+	Location domain.Location
+	Contact  domain.Contact
+	Lodging  domain.LodgingDetails
+}
+
+// HasLocation indica si el marcado trae una dirección o coordenadas utilizables.
+func (d *HotelData) HasLocation() bool {
+	l := d.Location
+	return l.Address != "" || l.City != "" || (l.Latitude != 0 && l.Longitude != 0)
+}
+
+type markupNode struct {
+	source string
+	node   map[string]interface{}
+}
+
+// ExtractHotelData lee el marcado schema.org (JSON-LD, microdata y RDFa) del sitio y
+// devuelve los datos del nodo que mejor describe al hotel.
+func ExtractHotelData(ctx context.Context, factory crawler.Factory, siteURL string) (*HotelData, error) {
+	var nodes []markupNode
+
+	c := factory.NewCollector(ctx)
+
+	c.OnHTML(`script[type="application/ld+json"]`, func(e *colly.HTMLElement) {
+		for _, node := range jsonLDNodes(e.Text) {
+			nodes = append(nodes, markupNode{source: MarkupJSONLD, node: node})
+		}
+	})
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		for _, node := range microdataNodes(e.DOM) {
+			nodes = append(nodes, markupNode{source: MarkupMicrodata, node: node})
+		}
+		for _, node := range rdfaNodes(e.DOM) {
+			nodes = append(nodes, markupNode{source: MarkupRDFa, node: node})
+		}
+	})
+
+	if err := c.Visit(ensureScheme(siteURL)); err != nil {
+		return nil, err
+	}
+
+	data := bestHotelData(nodes)
+	if data == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoHotelData, siteURL)
+	}
+	return data, nil
+}
+
+// bestHotelData elige el nodo con el tipo más específico y más datos; ante empate gana
+// JSON-LD sobre microdata y RDFa.
+func bestHotelData(nodes []markupNode) *HotelData {
+	type scored struct {
+		data  *HotelData
+		score int
+	}
+	var candidates []scored
+	for _, n := range nodes {
+		typeScore, typeName := 0, ""
+		for _, t := range jsonLDTypes(n.node) {
+			if s, ok := hotelDataTypes[t]; ok && s > typeScore {
+				typeScore, typeName = s, t
+			}
+		}
+		if typeScore == 0 {
+			continue
+		}
+
+		data := hotelDataFromNode(n.node)
+		data.Source = n.source
+		data.Lodging.Type = typeName
+
+		score := typeScore*10 + markupScores[n.source]
+		if data.HasLocation() {
+			score += 20
+		}
+		if data.Contact.Telephone != "" {
+			score += 5
+		}
+		candidates = append(candidates, scored{data: data, score: score})
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	return candidates[0].data
+}
+
+func hotelDataFromNode(node map[string]interface{}) *HotelData {
+	data := &HotelData{
+		Contact: domain.Contact{
+			Telephone: jsonLDString(node["telephone"]),
+			Email:     strings.TrimPrefix(jsonLDString(node["email"]), "mailto:"),
+			Fax:       jsonLDString(node["faxNumber"]),
+		},
+		Lodging: domain.LodgingDetails{
+			StarRating:   starRating(node["starRating"]),
+			CheckinTime:  jsonLDString(node["checkinTime"]),
+			CheckoutTime: jsonLDString(node["checkoutTime"]),
+			PriceRange:   jsonLDString(node["priceRange"]),
+			Amenities:    amenities(node["amenityFeature"]),
+		},
+	}
+
+	address := node["address"]
+	if address == nil {
+		if place, ok := node["location"].(map[string]interface{}); ok {
+			address = place["address"]
+		}
+	}
+	data.Location = postalAddress(address)
+
+	geo := node["geo"]
+	if geo == nil {
+		if place, ok := node["location"].(map[string]interface{}); ok {
+			geo = place["geo"]
+		}
+	}
+	if g, ok := firstNode(geo); ok {
+		lat, latOK := jsonLDNumber(g["latitude"])
+		long, longOK := jsonLDNumber(g["longitude"])
+		if latOK && longOK && lat >= -90 && lat <= 90 && long >= -180 && long <= 180 {
+			data.Location.Latitude = lat
+			data.Location.Longitude = long
+		}
+	}
+	return data
+}
+
+// postalAddress acepta un PostalAddress o una dirección en texto libre.
+func postalAddress(v interface{}) domain.Location {
+	if s, ok := v.(string); ok {
+		return domain.Location{Address: strings.TrimSpace(s)}
+	}
+	a, ok := firstNode(v)
+	if !ok {
+		return domain.Location{}
+	}
+	return domain.Location{
+		Address: jsonLDString(a["streetAddress"]),
+		City:    jsonLDString(a["addressLocality"]),
+		Region:  jsonLDString(a["addressRegion"]),
+		Country: jsonLDString(a["addressCountry"]),
+		ZipCode: jsonLDString(a["postalCode"]),
+	}
+}
+
+// starRating acepta un número o un Rating con ratingValue.
+func starRating(v interface{}) float64 {
+	if n, ok := firstNode(v); ok {
+		v = n["ratingValue"]
+	}
+	rating, _ := jsonLDNumber(v)
+	return rating
+}
+
+// amenities devuelve los nombres de los LocationFeatureSpecification que no están en false.
+func amenities(v interface{}) []string {
+	var items []interface{}
+	switch t := v.(type) {
+	case []interface{}:
+		items = t
+	case nil:
+		return nil
+	default:
+		items = []interface{}{t}
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, item := range items {
+		name := jsonLDString(item)
+		if n, ok := item.(map[string]interface{}); ok {
+			name = jsonLDString(n["name"])
+			if value, ok := n["value"]; ok && (value == false || value == "false" || value == "False") {
+				continue
+			}
+		}
+		name = cleanName(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func firstNode(v interface{}) (map[string]interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t, true
+	case []interface{}:
+		for _, item := range t {
+			if n, ok := item.(map[string]interface{}); ok {
+				return n, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// jsonLDNumber lee un número publicado como número o como texto ("4", "-34,6037").
+func jsonLDNumber(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case []interface{}:
+		if len(t) > 0 {
+			return jsonLDNumber(t[0])
+		}
+	}
+	s := strings.ReplaceAll(jsonLDString(v), ",", ".")
+	if s == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.Fields(s)[0], 64)
+	return f, err == nil
+}
+
+// microdataNodes convierte los itemscope de primer nivel en nodos con la misma forma
+// que JSON-LD, para interpretarlos con el mismo código.
+func microdataNodes(doc *goquery.Selection) []map[string]interface{} {
+	return markupItems(doc, markupAttrs{scope: "itemscope", typ: "itemtype", prop: "itemprop"})
+}
+
+// rdfaNodes hace lo mismo con los elementos typeof/property de RDFa Lite.
+func rdfaNodes(doc *goquery.Selection) []map[string]interface{} {
+	return markupItems(doc, markupAttrs{scope: "typeof", typ: "typeof", prop: "property"})
+}
+
+type markupAttrs struct {
+	scope string
+	typ   string
+	prop  string
+}
+
+func markupItems(doc *goquery.Selection, attrs markupAttrs) []map[string]interface{} {
+	var items []map[string]interface{}
+	doc.Find("[" + attrs.scope + "]").Each(func(_ int, s *goquery.Selection) {
+		// Los ítems anidados se leen como propiedad de su ítem padre.
+		if s.ParentsFiltered("["+attrs.scope+"]").Length() > 0 {
+			return
+		}
+		items = append(items, markupItem(s, attrs))
+	})
+	return items
+}
+
+func markupItem(s *goquery.Selection, attrs markupAttrs) map[string]interface{} {
+	node := map[string]interface{}{}
+	var types []interface{}
+	for _, t := range strings.Fields(s.AttrOr(attrs.typ, "")) {
+		types = append(types, schemaTerm(t))
+	}
+	if len(types) > 0 {
+		node["@type"] = types
+	}
+
+	var walk func(*goquery.Selection)
+	walk = func(sel *goquery.Selection) {
+		sel.Children().Each(func(_ int, child *goquery.Selection) {
+			_, isScope := child.Attr(attrs.scope)
+			if prop, ok := child.Attr(attrs.prop); ok {
+				var value interface{}
+				if isScope {
+					value = markupItem(child, attrs)
+				} else {
+					value = markupValue(child)
+				}
+				for _, name := range strings.Fields(prop) {
+					addProperty(node, schemaTerm(name), value)
+				}
+			}
+			if !isScope {
+				walk(child)
+			}
+		})
+	}
+	walk(s)
+	return node
+}
+
+// markupValue lee el valor de una propiedad según el elemento que la lleva.
+func markupValue(s *goquery.Selection) string {
+	if v, ok := s.Attr("content"); ok {
+		return strings.TrimSpace(v)
+	}
+	var attr string
+	switch goquery.NodeName(s) {
+	case "a", "link", "area":
+		attr = "href"
+	case "img", "audio", "video", "source", "iframe", "embed":
+		attr = "src"
+	case "object":
+		attr = "data"
+	case "time":
+		attr = "datetime"
+	case "data", "meter":
+		attr = "value"
+	}
+	if v, ok := s.Attr(attr); ok && attr != "" {
+		return strings.TrimSpace(v)
+	}
+	if v, ok := s.Attr("resource"); ok {
+		return strings.TrimSpace(v)
+	}
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+func addProperty(node map[string]interface{}, name string, value interface{}) {
+	existing, ok := node[name]
+	if !ok {
+		node[name] = value
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		node[name] = append(list, value)
+		return
+	}
+	node[name] = []interface{}{existing, value}
+}
+
+// schemaTerm quita el vocabulario de un tipo o propiedad ("https://schema.org/Hotel",
+// "schema:Hotel" → "Hotel").
+func schemaTerm(s string) string {
+	if i := strings.LastIndexAny(s, "/:#"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
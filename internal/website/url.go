@@ -0,0 +1,35 @@
+package website
+
+import (
+	"net/url"
+	"strings"
+)
+
+func ensureScheme(urlStr string) string {
+	if !strings.HasPrefix(urlStr, "http://") && !strings.HasPrefix(urlStr, "https://") {
+		urlStr = "https://" + urlStr
+	}
+	return urlStr
+}
+
+// hostName devuelve el host sin "www.", usado como último nombre posible.
+func hostName(urlStr string) string {
+	u, err := url.Parse(ensureScheme(urlStr))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// resolveRef resuelve una referencia relativa contra la URL de la página.
+func resolveRef(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
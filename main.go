@@ -3,11 +3,20 @@ package main
 import (
 	"clubhub-hotel-management/cmd/server/routes"
 	"clubhub-hotel-management/internal/db"
+	"clubhub-hotel-management/internal/franquicia"
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func init() {
@@ -17,12 +26,53 @@ func init() {
 }
 
 func main() {
+	migrateDates := flag.Bool("migrate-domain-dates", false, "convierte a fechas BSON las fechas de dominio guardadas como texto y termina")
+	flag.Parse()
+
+	// ctx se cancela con SIGINT/SIGTERM: detiene las tareas periódicas y el servidor.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	engine := gin.Default()
 
-	mongodb := db.MongoClient
+	// MongoDB es obligatorio si guarda las franquicias; con otro FRANCHISE_STORE solo se
+	// conecta si está configurado, para las funciones que guardan sus datos ahí.
+	var mongodb *mongo.Client
+	if store := os.Getenv("FRANCHISE_STORE"); store == "" || store == "mongo" || db.MongoConfigured() {
+		mongodb = db.ConnectionMongodb()
+	}
+
+	if *migrateDates {
+		if mongodb == nil {
+			log.Fatal("-migrate-domain-dates requiere MongoDB")
+		}
+		franchises := mongodb.Database(os.Getenv("MONGODB_DATABASE_NAME")).Collection("franchises")
+		n, err := franquicia.MigrateDomainDates(ctx, franchises)
+		mongodb.Disconnect(context.Background())
+		if err != nil {
+			log.Fatalf("Error al migrar las fechas de dominio: %v", err)
+		}
+		log.Printf("Fechas de dominio migradas en %d franquicias", n)
+		return
+	}
 
 	router := routes.NewRouter(engine, mongodb)
 	router.MapRoutes()
-	defer mongodb.Disconnect(context.Background())
-	engine.Run(":8080")
+	router.RunJobs(ctx)
+	if mongodb != nil {
+		defer mongodb.Disconnect(context.Background())
+	}
+
+	server := &http.Server{Addr: ":8080", Handler: engine}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error al detener el servidor: %v", err)
+		}
+	}()
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
 }